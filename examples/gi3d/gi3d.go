@@ -145,11 +145,11 @@ See <a href="https://github.com/goki/gi/blob/master/examples/gi3d/README.md">REA
 	dir := gi3d.AddNewDirLight(sc, "dir", 1, gi3d.DirectSun)
 	dir.Pos.Set(0, 2, 1) // default: 0,1,1 = above and behind us (we are at 0,0,X)
 
-	// point := gi3d.AddNewPointLight(sc, "point", 1, gi3d.DirectSun)
-	// point.Pos.Set(0, 5, 5)
+	point := gi3d.AddNewPointLight(sc, "point", 1, gi3d.DirectSun)
+	point.Pos.Set(0, 5, 5) // attenuates with distance -- try moving closer / farther
 
-	// spot := gi3d.AddNewSpotLight(sc, "spot", 1, gi3d.DirectSun)
-	// spot.Pose.Pos.Set(0, 5, 5)
+	spot := gi3d.AddNewSpotLight(sc, "spot", 1, gi3d.DirectSun)
+	spot.Pose.Pos.Set(0, 5, 5)
 
 	cbm := gi3d.AddNewBox(sc, "cube1", 1, 1, 1)
 	// cbm.Segs.Set(10, 10, 10) // not clear if any diff really..
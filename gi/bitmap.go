@@ -254,6 +254,41 @@ func ImageClearer(im *image.RGBA, pct float32) {
 	}
 }
 
+// DownsampleRGBA returns given image reduced by integer factor n, averaging
+// each n x n block of source pixels into one destination pixel (area
+// averaging) -- used for supersampled antialiasing (see Viewport2D.SuperSample).
+// Returns im itself if n <= 1.
+func DownsampleRGBA(im *image.RGBA, n int) *image.RGBA {
+	if im == nil || n <= 1 {
+		return im
+	}
+	sz := im.Bounds().Size()
+	dsz := image.Point{sz.X / n, sz.Y / n}
+	ds := image.NewRGBA(image.Rectangle{Max: dsz})
+	n2 := uint32(n * n)
+	for y := 0; y < dsz.Y; y++ {
+		for x := 0; x < dsz.X; x++ {
+			var r, g, b, a uint32
+			for sy := 0; sy < n; sy++ {
+				si := im.PixOffset(im.Rect.Min.X+x*n, im.Rect.Min.Y+y*n+sy)
+				for sx := 0; sx < n; sx++ {
+					r += uint32(im.Pix[si])
+					g += uint32(im.Pix[si+1])
+					b += uint32(im.Pix[si+2])
+					a += uint32(im.Pix[si+3])
+					si += 4
+				}
+			}
+			di := ds.PixOffset(x, y)
+			ds.Pix[di] = uint8(r / n2)
+			ds.Pix[di+1] = uint8(g / n2)
+			ds.Pix[di+2] = uint8(b / n2)
+			ds.Pix[di+3] = uint8(a / n2)
+		}
+	}
+	return ds
+}
+
 //////////////////////////////////////////////////////////////////////////////////
 //  Props
 
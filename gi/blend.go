@@ -0,0 +1,137 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/goki/gi/mat32"
+	"github.com/goki/ki/kit"
+	"golang.org/x/image/draw"
+)
+
+// BlendModes are the blend modes for compositing images and fills, beyond
+// the standard Porter-Duff source-over (Normal) and source (Copy) operators
+// of the draw package: the separable modes (Multiply, Screen, etc) combine
+// the source and backdrop colors per-channel, as in the CSS / SVG
+// compositing spec, and are implemented per-pixel over image.RGBA --
+// see BlendImage, FillStyle.Blend, and Viewport2D.BlendMode.
+type BlendModes int
+
+const (
+	// BlendNormal is the standard Porter-Duff source-over alpha blending
+	// (draw.Over) -- the default.
+	BlendNormal BlendModes = iota
+
+	// BlendCopy replaces the backdrop with the source (draw.Src),
+	// including alpha.
+	BlendCopy
+
+	// BlendMultiply multiplies the backdrop and source colors, always
+	// producing a result at least as dark as either.
+	BlendMultiply
+
+	// BlendScreen multiplies the complements of the backdrop and source
+	// colors, always producing a result at least as light as either.
+	BlendScreen
+
+	// BlendAdd adds the source color to the backdrop (linear dodge),
+	// clamping at white.
+	BlendAdd
+
+	// BlendDarken selects the darker of the backdrop and source colors
+	// per channel.
+	BlendDarken
+
+	// BlendLighten selects the lighter of the backdrop and source colors
+	// per channel.
+	BlendLighten
+
+	BlendModesN
+)
+
+//go:generate stringer -type=BlendModes
+
+var KiT_BlendModes = kit.Enums.AddEnumAltLower(BlendModesN, kit.NotBitFlag, StylePropProps, "Blend")
+
+func (ev BlendModes) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *BlendModes) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// BlendImage composites src onto dst within rectangle r (in dst coordinates,
+// with sp the corresponding start point in src), using given blend mode --
+// the blend-mode generalization of draw.Draw.  BlendNormal and BlendCopy
+// use the optimized draw package operators; the separable modes blend
+// per-pixel: the mixed color is weighted by the backdrop alpha per the CSS
+// compositing spec, then composited source-over.
+func BlendImage(dst *image.RGBA, r image.Rectangle, src image.Image, sp image.Point, mode BlendModes) {
+	switch mode {
+	case BlendNormal:
+		draw.Draw(dst, r, src, sp, draw.Over)
+		return
+	case BlendCopy:
+		draw.Draw(dst, r, src, sp, draw.Src)
+		return
+	}
+	orig := r.Min
+	r = r.Intersect(dst.Bounds())
+	sp = sp.Add(r.Min.Sub(orig)) // shift source point by the amount clipped off the top / left
+	if sr := src.Bounds().Sub(sp).Add(r.Min); !r.In(sr) {
+		orig = r.Min
+		r = r.Intersect(sr)
+		sp = sp.Add(r.Min.Sub(orig))
+	}
+	srgba, _ := src.(*image.RGBA) // fast path
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			var cs color.RGBA
+			if srgba != nil {
+				cs = srgba.RGBAAt(sp.X+x, sp.Y+y)
+			} else {
+				cs = color.RGBAModel.Convert(src.At(sp.X+x, sp.Y+y)).(color.RGBA)
+			}
+			dx, dy := r.Min.X+x, r.Min.Y+y
+			dst.SetRGBA(dx, dy, BlendRGBA(dst.RGBAAt(dx, dy), cs, mode))
+		}
+	}
+}
+
+// BlendRGBA blends (premultiplied) source color cs onto backdrop color cb
+// using given blend mode, returning the composited (premultiplied) result.
+func BlendRGBA(cb, cs color.RGBA, mode BlendModes) color.RGBA {
+	as := float32(cs.A) / 255
+	ab := float32(cb.A) / 255
+	if as == 0 {
+		return cb
+	}
+	blend := func(b, s uint8) uint8 {
+		// un-premultiply to channel values in 0..1
+		var vb, vs float32
+		if ab > 0 {
+			vb = float32(b) / (255 * ab)
+		}
+		vs = float32(s) / (255 * as)
+		var vm float32
+		switch mode {
+		case BlendMultiply:
+			vm = vb * vs
+		case BlendScreen:
+			vm = vb + vs - vb*vs
+		case BlendAdd:
+			vm = mat32.Min(1, vb+vs)
+		case BlendDarken:
+			vm = mat32.Min(vb, vs)
+		case BlendLighten:
+			vm = mat32.Max(vb, vs)
+		default:
+			vm = vs
+		}
+		vm = (1-ab)*vs + ab*vm     // backdrop-alpha weighted mix per CSS spec
+		co := as*vm + ab*(1-as)*vb // source-over composite, premultiplied
+		return uint8(mat32.Min(co, 1)*255 + .5)
+	}
+	ao := as + ab*(1-as)
+	return color.RGBA{blend(cb.R, cs.R), blend(cb.G, cs.G), blend(cb.B, cs.B), uint8(ao*255 + .5)}
+}
@@ -219,6 +219,7 @@ type RenderState struct {
 	Paint  Paint           `desc:"communal painter -- for widgets -- SVG have their own"`
 	XForm  mat32.Mat2      `desc:"current transform"`
 	Path   rasterx.Path    `desc:"current path"`
+	SVGRec *SVGRecorder    `copy:"-" json:"-" xml:"-" view:"-" desc:"if non-nil, all drawing commands are also recorded into this SVG document -- see Viewport2D.SaveSVG"`
 	Raster *rasterx.Dasher `desc:"rasterizer -- stroke / fill rendering engine from rasterx"`
 	//	Scanner        *scanFT.ScannerFT `desc:"scanner for freetype-based rasterx"`
 	// CompSpanner    *scanx.CompressSpanner `desc:"spanner for scanx"`
@@ -347,6 +348,31 @@ func (rs *RenderState) PopBounds() {
 	rs.BoundsStack = rs.BoundsStack[:sz-1]
 }
 
+// PushClipRect pushes current bounds onto the bounds stack and sets the new
+// bounds to the intersection of given rectangle with the current bounds,
+// restricting all subsequent drawing to that region -- use for scrolling /
+// overflow-hidden effects in custom widgets without re-implementing the
+// bounds logic.  Must be balanced with a corresponding PopClipRect.
+func (rs *RenderState) PushClipRect(r image.Rectangle) {
+	rs.RenderMu.Lock()
+	defer rs.RenderMu.Unlock()
+
+	if rs.BoundsStack == nil {
+		rs.BoundsStack = make([]image.Rectangle, 0, 100)
+	}
+	if rs.Bounds.Empty() { // note: method name should be IsEmpty!
+		rs.Bounds = rs.Image.Bounds()
+	}
+	rs.BoundsStack = append(rs.BoundsStack, rs.Bounds)
+	rs.Bounds = r.Intersect(rs.Bounds)
+}
+
+// PopClipRect pops the bounds pushed by PushClipRect, restoring the previous
+// clipping region.
+func (rs *RenderState) PopClipRect() {
+	rs.PopBounds()
+}
+
 // PushClip pushes current Mask onto the clip stack
 func (rs *RenderState) PushClip() {
 	if rs.Mask == nil {
@@ -592,6 +618,10 @@ func (pc *Paint) stroke(rs *RenderState) {
 	rs.Raster.Draw()
 	rs.Raster.Clear()
 
+	if rs.SVGRec != nil {
+		rs.SVGRec.Path(rs, pc, false, true)
+	}
+
 	/*
 		rs.CompSpanner.DrawToImage(rs.Image)
 		rs.CompSpanner.Clear()
@@ -609,6 +639,14 @@ func (pc *Paint) fill(rs *RenderState) {
 	rs.RasterMu.Lock()
 	defer rs.RasterMu.Unlock()
 
+	if pc.FillStyle.Blend != BlendNormal {
+		pc.fillBlend(rs)
+		if rs.SVGRec != nil {
+			rs.SVGRec.Path(rs, pc, true, false)
+		}
+		return
+	}
+
 	rf := &rs.Raster.Filler
 	rf.SetWinding(pc.FillStyle.Rule == FillRuleNonZero)
 	rs.Scanner.SetClip(rs.Bounds)
@@ -625,6 +663,10 @@ func (pc *Paint) fill(rs *RenderState) {
 	rf.Draw()
 	rf.Clear()
 
+	if rs.SVGRec != nil {
+		rs.SVGRec.Path(rs, pc, true, false)
+	}
+
 	/*
 		rs.CompSpanner.DrawToImage(rs.Image)
 		rs.CompSpanner.Clear()
@@ -632,6 +674,29 @@ func (pc *Paint) fill(rs *RenderState) {
 
 }
 
+// fillBlend renders the current path fill into a scratch layer and
+// composites it onto the image using the FillStyle.Blend blend mode (see
+// BlendImage) -- slower than the direct fill path, so only used when a
+// non-default mode is set.  RasterMu must be held.
+func (pc *Paint) fillBlend(rs *RenderState) {
+	sz := rs.Image.Bounds().Size()
+	tmp := image.NewRGBA(rs.Image.Bounds())
+	spanner := scanx.NewImgSpanner(tmp)
+	scanner := scanx.NewScanner(spanner, sz.X, sz.Y)
+	rf := rasterx.NewFiller(sz.X, sz.Y, scanner)
+	rf.SetWinding(pc.FillStyle.Rule == FillRuleNonZero)
+	scanner.SetClip(rs.Bounds)
+	rs.Path.AddTo(rf)
+	fbox := scanner.GetPathExtent()
+	rs.LastRenderBBox = image.Rectangle{Min: image.Point{fbox.Min.X.Floor(), fbox.Min.Y.Floor()},
+		Max: image.Point{fbox.Max.X.Ceil(), fbox.Max.Y.Ceil()}}
+	rf.SetColor(pc.FillStyle.Color.RenderColor(pc.FontStyle.Opacity*pc.FillStyle.Opacity, rs.LastRenderBBox, rs.XForm))
+	rf.Draw()
+	rf.Clear()
+	bb := rs.LastRenderBBox.Intersect(rs.Image.Bounds())
+	BlendImage(rs.Image, bb, tmp, bb.Min, pc.FillStyle.Blend)
+}
+
 // StrokePreserve strokes the current path with the current color, line width,
 // line cap, line join and dash settings. The path is preserved after this
 // operation.
@@ -666,6 +731,9 @@ func (pc *Paint) FillBox(rs *RenderState, pos, size mat32.Vec2, clr *ColorSpec)
 	if clr.Source == SolidColor {
 		b := rs.Bounds.Intersect(mat32.RectFromPosSizeMax(pos, size))
 		draw.Draw(rs.Image, b, &image.Uniform{clr.Color}, image.ZP, draw.Src)
+		if rs.SVGRec != nil {
+			rs.SVGRec.Rect(b, clr.Color)
+		}
 	} else {
 		pc.FillStyle.SetColorSpec(clr)
 		pc.DrawRectangle(rs, pos.X, pos.Y, size.X, size.Y)
@@ -677,6 +745,9 @@ func (pc *Paint) FillBox(rs *RenderState, pos, size mat32.Vec2, clr *ColorSpec)
 func (pc *Paint) FillBoxColor(rs *RenderState, pos, size mat32.Vec2, clr color.Color) {
 	b := rs.Bounds.Intersect(mat32.RectFromPosSizeMax(pos, size))
 	draw.Draw(rs.Image, b, &image.Uniform{clr}, image.ZP, draw.Src)
+	if rs.SVGRec != nil {
+		rs.SVGRec.Rect(b, clr)
+	}
 }
 
 // ClipPreserve updates the clipping region by intersecting the current
@@ -736,6 +807,9 @@ func (pc *Paint) ResetClip(rs *RenderState) {
 func (pc *Paint) Clear(rs *RenderState) {
 	src := image.NewUniform(&pc.FillStyle.Color.Color)
 	draw.Draw(rs.Image, rs.Image.Bounds(), src, image.ZP, draw.Src)
+	if rs.SVGRec != nil {
+		rs.SVGRec.Rect(rs.Image.Bounds(), &pc.FillStyle.Color.Color)
+	}
 }
 
 // SetPixel sets the color of the specified pixel using the current stroke color.
@@ -1021,6 +1095,9 @@ func (pc *Paint) DrawImageAnchored(rs *RenderState, fmIm image.Image, x, y int,
 			DstMaskP: image.ZP,
 		})
 	}
+	if rs.SVGRec != nil {
+		rs.SVGRec.Image(fmIm, m)
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,36 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/goki/gi/mat32"
+)
+
+func TestPushClipRect(t *testing.T) {
+	sz := 20
+	img := image.NewRGBA(image.Rect(0, 0, sz, sz))
+	rs := &RenderState{}
+	rs.Init(sz, sz, img)
+
+	clip := image.Rect(0, 0, 10, 10)
+	rs.PushClipRect(clip)
+	red := color.RGBA{255, 0, 0, 255}
+	rs.Paint.FillBoxColor(rs, mat32.Vec2{}, mat32.Vec2{float32(sz), float32(sz)}, red)
+	rs.PopClipRect()
+
+	if got := img.RGBAAt(5, 5); got != red {
+		t.Errorf("pixel inside clip not filled: got %v, want %v", got, red)
+	}
+	if got := img.RGBAAt(15, 15); got != (color.RGBA{}) {
+		t.Errorf("pixel outside clip was modified: got %v", got)
+	}
+	if rs.Bounds != img.Bounds() {
+		t.Errorf("bounds not restored after PopClipRect: got %v, want %v", rs.Bounds, img.Bounds())
+	}
+}
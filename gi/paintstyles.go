@@ -35,10 +35,11 @@ func (ev *FillRules) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSO
 
 // FillStyle contains all the properties for filling a region
 type FillStyle struct {
-	On      bool      `desc:"is fill active -- if property is none then false"`
-	Color   ColorSpec `xml:"fill" desc:"prop: fill = fill color specification"`
-	Opacity float32   `xml:"fill-opacity" desc:"prop: fill-opacity = global alpha opacity / transparency factor"`
-	Rule    FillRules `xml:"fill-rule" desc:"prop: fill-rule = rule for how to fill more complex shapes with crossing lines"`
+	On      bool       `desc:"is fill active -- if property is none then false"`
+	Color   ColorSpec  `xml:"fill" desc:"prop: fill = fill color specification"`
+	Opacity float32    `xml:"fill-opacity" desc:"prop: fill-opacity = global alpha opacity / transparency factor"`
+	Rule    FillRules  `xml:"fill-rule" desc:"prop: fill-rule = rule for how to fill more complex shapes with crossing lines"`
+	Blend   BlendModes `xml:"blend" desc:"prop: blend = blend mode for compositing the fill over the current image -- BlendNormal (the default) is standard alpha blending, other modes implement multiply, screen etc effects -- see BlendModes"`
 }
 
 // Defaults initializes default values for paint fill
@@ -47,6 +48,7 @@ func (pf *FillStyle) Defaults() {
 	pf.SetColor(color.Black)
 	pf.Rule = FillRuleNonZero
 	pf.Opacity = 1.0
+	pf.Blend = BlendNormal
 }
 
 // SetStylePost does some updating after setting the style from user properties
@@ -459,6 +461,29 @@ var StyleFillFuncs = map[string]StyleFunc{
 			}
 		}
 	},
+	"blend": func(obj interface{}, key string, val interface{}, par interface{}, vp *Viewport2D) {
+		fs := obj.(*FillStyle)
+		if inh, init := StyleInhInit(val, par); inh || init {
+			if inh {
+				fs.Blend = par.(*FillStyle).Blend
+			} else if init {
+				fs.Blend = BlendNormal
+			}
+			return
+		}
+		switch vt := val.(type) {
+		case string:
+			fs.Blend.FromString(vt)
+		case BlendModes:
+			fs.Blend = vt
+		default:
+			if iv, ok := kit.ToInt(val); ok {
+				fs.Blend = BlendModes(iv)
+			} else {
+				StyleSetError(key, val)
+			}
+		}
+	},
 }
 
 // ToDots runs ToDots on unit values, to compile down to raw pixels
@@ -225,8 +225,10 @@ func (s *Style) SetUnitContext(vp *Viewport2D, el mat32.Vec2) {
 		if vp.Win != nil {
 			s.UnContext.DPI = vp.Win.LogicalDPI()
 			// fmt.Printf("set dpi: %v\n", s.UnContext.DPI)
-			// } else {
-			// 	fmt.Printf("No win for vp: %v\n", vp.PathUnique())
+		} else if vp.DevicePixelRatio > 0 || vp.SuperSample > 1 {
+			// standalone viewport: scale DPI so logical coords are DPI-independent,
+			// and up again by any supersampling factor to fill the larger buffer
+			s.UnContext.DPI = units.PxPerInch * vp.DevPixScale() * float32(vp.SSScale())
 		}
 		if vp.Render.Image != nil {
 			sz := vp.Render.Image.Bounds().Size()
@@ -0,0 +1,268 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"sync"
+	"unicode"
+
+	"github.com/goki/gi/mat32"
+)
+
+// SVGRecorder records the vector drawing commands that pass through a
+// RenderState into an SVG document, instead of (in addition to) rasterizing
+// them -- set RenderState.SVGRec and render, or use Viewport2D.SaveSVG for
+// the simple case.  Because all of the Paint primitives (rectangles, arcs,
+// polygons, etc) funnel into the shared path, fill, and stroke machinery,
+// the recorder captures everything at that level, with coordinates already
+// transformed into device coordinates.  Known approximations: gradients are
+// emitted as their solid base color, clipping regions are not applied, and
+// text is emitted with explicit per-rune positions but only the font
+// parameters available at render time.
+type SVGRecorder struct {
+	Size     image.Point  `desc:"size of the document coordinate system (the viewBox), in dots (pixels) -- the device coordinates that rendering produces"`
+	DispSize image.Point  `desc:"display size for the width / height attributes, in logical pixels -- zero (the default) means same as Size -- set to the logical viewport size when the viewport is scaled (DevicePixelRatio, SuperSample) so the document displays at its logical size while the viewBox stays in device coordinates"`
+	Elems    bytes.Buffer `desc:"accumulated SVG elements, in rendering order"`
+	Mu       sync.Mutex   `desc:"mutex protecting updates to Elems"`
+}
+
+// NewSVGRecorder returns a new recorder for a document of given size in dots.
+func NewSVGRecorder(size image.Point) *SVGRecorder {
+	sr := &SVGRecorder{Size: size}
+	return sr
+}
+
+// Element appends a complete SVG element string to the document.
+func (sr *SVGRecorder) Element(el string) {
+	sr.Mu.Lock()
+	sr.Elems.WriteString(el)
+	sr.Elems.WriteString("\n")
+	sr.Mu.Unlock()
+}
+
+// WriteXML writes the accumulated SVG document to given writer.
+func (sr *SVGRecorder) WriteXML(w io.Writer) error {
+	dsz := sr.DispSize
+	if dsz == (image.Point{}) {
+		dsz = sr.Size
+	}
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%d" height="%d" viewBox="0 0 %d %d">%s`,
+		dsz.X, dsz.Y, sr.Size.X, sr.Size.Y, "\n")
+	if err != nil {
+		return err
+	}
+	sr.Mu.Lock()
+	_, err = w.Write(sr.Elems.Bytes())
+	sr.Mu.Unlock()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "</svg>\n")
+	return err
+}
+
+// SVGColor returns the given color as an svg color attribute value,
+// e.g., #a0b0c0 -- "none" for nil or fully-transparent colors.
+func SVGColor(clr color.Color) string {
+	if clr == nil {
+		return "none"
+	}
+	c := Color{}
+	c.SetColor(clr)
+	if c.IsNil() || c.A == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// SVGColorOpacity returns the effective opacity of given color (alpha
+// component) times the given overall opacity factor.
+func SVGColorOpacity(clr color.Color, opacity float32) float32 {
+	c := Color{}
+	c.SetColor(clr)
+	return opacity * float32(c.A) / 255
+}
+
+// svgCapNames maps LineCaps to svg stroke-linecap attribute values
+var svgCapNames = map[LineCaps]string{
+	LineCapButt:   "butt",
+	LineCapRound:  "round",
+	LineCapSquare: "square",
+}
+
+// svgJoinNames maps LineJoins to svg stroke-linejoin attribute values
+var svgJoinNames = map[LineJoins]string{
+	LineJoinMiter:     "miter",
+	LineJoinMiterClip: "miter-clip",
+	LineJoinRound:     "round",
+	LineJoinBevel:     "bevel",
+	LineJoinArcs:      "arcs",
+}
+
+// FillAttrs returns svg fill attributes for the current paint fill style --
+// gradients are approximated by their solid base color.
+func (sr *SVGRecorder) FillAttrs(pc *Paint, doFill bool) string {
+	if !doFill {
+		return `fill="none"`
+	}
+	clr := &pc.FillStyle.Color.Color
+	return fmt.Sprintf(`fill="%s" fill-opacity="%g" fill-rule="%s"`,
+		SVGColor(clr), SVGColorOpacity(clr, pc.FontStyle.Opacity*pc.FillStyle.Opacity),
+		map[FillRules]string{FillRuleNonZero: "nonzero", FillRuleEvenOdd: "evenodd"}[pc.FillStyle.Rule])
+}
+
+// StrokeAttrs returns svg stroke attributes for the current paint stroke
+// style -- gradients are approximated by their solid base color.
+func (sr *SVGRecorder) StrokeAttrs(rs *RenderState, pc *Paint, doStroke bool) string {
+	if !doStroke {
+		return `stroke="none"`
+	}
+	clr := &pc.StrokeStyle.Color.Color
+	s := fmt.Sprintf(`stroke="%s" stroke-opacity="%g" stroke-width="%g" stroke-linecap="%s" stroke-linejoin="%s"`,
+		SVGColor(clr), SVGColorOpacity(clr, pc.FontStyle.Opacity*pc.StrokeStyle.Opacity),
+		pc.StrokeWidth(rs), svgCapNames[pc.StrokeStyle.Cap], svgJoinNames[pc.StrokeStyle.Join])
+	if len(pc.StrokeStyle.Dashes) > 0 {
+		s += ` stroke-dasharray="`
+		for i, d := range pc.StrokeStyle.Dashes {
+			if i > 0 {
+				s += " "
+			}
+			s += fmt.Sprintf("%g", d)
+		}
+		s += `"`
+	}
+	return s
+}
+
+// Path records the current path in the render state, as filled and / or
+// stroked with the current paint styles -- called from the Paint fill and
+// stroke methods, with the path already in device coordinates.
+func (sr *SVGRecorder) Path(rs *RenderState, pc *Paint, doFill, doStroke bool) {
+	if len(rs.Path) == 0 {
+		return
+	}
+	sr.Element(fmt.Sprintf(`<path d="%s" %s %s/>`, rs.Path.ToSVGPath(),
+		sr.FillAttrs(pc, doFill), sr.StrokeAttrs(rs, pc, doStroke)))
+}
+
+// Rect records an axis-aligned rectangle filled with given solid color --
+// used for the optimized box-fill and clear operations that bypass the path
+// machinery.
+func (sr *SVGRecorder) Rect(b image.Rectangle, clr color.Color) {
+	if b.Empty() {
+		return
+	}
+	fc := SVGColor(clr)
+	if fc == "none" {
+		return
+	}
+	sr.Element(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s" fill-opacity="%g"/>`,
+		b.Min.X, b.Min.Y, b.Dx(), b.Dy(), fc, SVGColorOpacity(clr, 1)))
+}
+
+// Image records given image, embedded as a base64-encoded PNG data URI,
+// transformed by the given matrix (from image source to device coordinates).
+func (sr *SVGRecorder) Image(fmIm image.Image, m mat32.Mat2) {
+	var ib bytes.Buffer
+	err := png.Encode(&ib, fmIm)
+	if err != nil {
+		return
+	}
+	isz := fmIm.Bounds().Size()
+	sr.Element(fmt.Sprintf(`<image x="0" y="0" width="%d" height="%d" transform="matrix(%g,%g,%g,%g,%g,%g)" xlink:href="data:image/png;base64,%s"/>`,
+		isz.X, isz.Y, m.XX, m.YX, m.XY, m.YY, m.X0, m.Y0,
+		base64.StdEncoding.EncodeToString(ib.Bytes())))
+}
+
+// Text records a span of text at given baseline positions (one per rune),
+// with given fill color, font size in dots, and font family (empty = default).
+func (sr *SVGRecorder) Text(txt []rune, xs, ys []float32, clr color.Color, fontSz float32, fontFam string) {
+	if len(txt) == 0 {
+		return
+	}
+	var xb, yb bytes.Buffer
+	for i := range txt {
+		if i > 0 {
+			xb.WriteString(" ")
+			yb.WriteString(" ")
+		}
+		fmt.Fprintf(&xb, "%g", xs[i])
+		fmt.Fprintf(&yb, "%g", ys[i])
+	}
+	var tb bytes.Buffer
+	xml.EscapeText(&tb, []byte(string(txt)))
+	fam := ""
+	if fontFam != "" {
+		var fb bytes.Buffer
+		xml.EscapeText(&fb, []byte(fontFam))
+		fam = fmt.Sprintf(` font-family="%s"`, fb.String())
+	}
+	sr.Element(fmt.Sprintf(`<text x="%s" y="%s" fill="%s" font-size="%g"%s>%s</text>`,
+		xb.String(), yb.String(), SVGColor(clr), fontSz, fam, tb.String()))
+}
+
+// SVGRecord records this span into the render state's SVG recorder, using
+// the given starting position and color -- per-rune positions are emitted so
+// the layout matches the raster output, but only the first color of the span
+// and the font family currently in the communal paint are captured.
+func (sr *SpanRender) SVGRecord(rs *RenderState, tpos mat32.Vec2, clr color.Color) {
+	var txt []rune
+	var xs, ys []float32
+	fontSz := float32(0)
+	for i, r := range sr.Text {
+		rr := &(sr.Render[i])
+		if !unicode.IsPrint(r) {
+			continue
+		}
+		rp := tpos.Add(rr.RelPos)
+		txt = append(txt, r)
+		xs = append(xs, rp.X)
+		ys = append(ys, rp.Y)
+		if fontSz == 0 && rr.Size.Y > 0 {
+			fontSz = rr.Size.Y
+		}
+	}
+	rs.SVGRec.Text(txt, xs, ys, clr, fontSz, rs.Paint.FontStyle.Family)
+}
+
+//////////////////////////////////////////////////////////////////////////////////
+//  Viewport2D API
+
+// EncodeSVG re-renders the viewport with an SVG recorder attached and writes
+// the resulting SVG document to the given writer -- see SVGRecorder for the
+// approximations involved.  The document's internal coordinates (viewBox)
+// are the physical pixel coordinates of the viewport, including any
+// DevicePixelRatio / SuperSample scaling; its width / height are the
+// logical size, so it displays at the same size as the on-screen viewport.
+func (vp *Viewport2D) EncodeSVG(w io.Writer) error {
+	rec := NewSVGRecorder(vp.Geom.Size)
+	ss := vp.SSScale()
+	rec.DispSize = vp.DeviceToLogical(image.Point{vp.Geom.Size.X / ss, vp.Geom.Size.Y / ss})
+	vp.Render.SVGRec = rec
+	vp.FullRender2DTree()
+	vp.Render.SVGRec = nil
+	return rec.WriteXML(w)
+}
+
+// SaveSVG re-renders the viewport as a vector SVG document and saves it to
+// given path -- produces scalable output for diagrams and charts built with
+// the 2D API, in contrast to the raster SavePNG.
+func (vp *Viewport2D) SaveSVG(path string) error {
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	return vp.EncodeSVG(fp)
+}
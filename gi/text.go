@@ -853,6 +853,10 @@ func (tr *TextRender) Render(rs *RenderState, pos mat32.Vec2) {
 		}
 
 		// todo: cache flags if these are actually needed
+		if rs.SVGRec != nil {
+			sr.SVGRecord(rs, tpos, curColor)
+		}
+
 		if bitflag.Has32(int32(sr.HasDeco), int(DecoBgColor)) {
 			sr.RenderBg(rs, tpos)
 		}
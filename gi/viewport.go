@@ -13,7 +13,10 @@ import (
 	"log"
 	"sync"
 
+	xdraw "golang.org/x/image/draw"
+
 	"github.com/goki/gi/mat32"
+	"github.com/goki/gi/oswin"
 	"github.com/goki/ki/bitflag"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
@@ -50,18 +53,26 @@ type Viewport interface {
 // with a convenience forwarding of the Paint methods operating on the current Paint
 type Viewport2D struct {
 	WidgetBase
-	Fill         bool         `desc:"fill the viewport with background-color from style"`
-	Geom         Geom2DInt    `desc:"Viewport-level viewbox within any parent Viewport2D"`
-	Render       RenderState  `copy:"-" json:"-" xml:"-" view:"-" desc:"render state for rendering"`
-	Pixels       *image.RGBA  `copy:"-" json:"-" xml:"-" view:"-" desc:"live pixels that we render into"`
-	Win          *Window      `copy:"-" json:"-" xml:"-" desc:"our parent window that we render into"`
-	CurStyleNode Node2D       `copy:"-" json:"-" xml:"-" view:"-" desc:"CurStyleNode2D is always set to the current node that is being styled used for finding url references -- only active during a Style pass"`
-	CurColor     Color        `copy:"-" json:"-" xml:"-" view:"-" desc:"CurColor is automatically updated from the Color setting of a Style and accessible as a color name in any other style as currentcolor use accessor routines for concurrent-safe access"`
-	UpdtMu       sync.Mutex   `copy:"-" json:"-" xml:"-" view:"-" desc:"UpdtMu is mutex for viewport updates"`
-	UpdtStack    []Node2D     `copy:"-" json:"-" xml:"-" view:"-" desc:"stack of nodes requring basic updating"`
-	ReStack      []Node2D     `copy:"-" json:"-" xml:"-" view:"-" desc:"stack of nodes requiring a ReRender (i.e., anchors)"`
-	StackMu      sync.Mutex   `copy:"-" json:"-" xml:"-" view:"-" desc:"StackMu is mutex for adding to UpdtStack"`
-	StyleMu      sync.RWMutex `copy:"-" json:"-" xml:"-" view:"-" desc:"StyleMu is RW mutex protecting access to Style-related global vars"`
+	Fill             bool          `desc:"fill the viewport with background-color from style"`
+	DevicePixelRatio float32       `desc:"device pixel ratio for standalone (non-Window) viewports: when > 1, the Pixels buffer is allocated at this multiple of the logical size given to Resize / NewViewport2D, and the unit context DPI is scaled accordingly, so content renders crisp at native resolution while logical coordinates stay DPI-independent -- 0 or 1 = 1:1 mapping (the default).  Viewports in a Window get their scaling from the window's LogicalDPI instead"`
+	SuperSample      int           `desc:"supersampling factor (typically 2 or 4) for standalone (non-Window) viewports: the Pixels buffer is rendered at this multiple of the (device pixel) size, and area-averaged back down when producing the final image (UploadPix, UploadToTexture, SavePNG), for higher-quality text and shape edges without relying on GPU MSAA -- 0 or 1 = off (the default) -- combines with DevicePixelRatio -- see also SavePNGFullRes"`
+	BgImage          *image.RGBA   `copy:"-" json:"-" xml:"-" view:"-" desc:"optional background image / watermark drawn behind the children, after any Fill, composited Over so transparent regions show the fill color -- see BgImageStyle for how it is placed"`
+	BlendMode        BlendModes    `desc:"blend mode for compositing this viewport into its parent viewport image in DrawIntoParent (sub-viewports such as svg boxes, icons -- not popups or the window main viewport) -- BlendNormal (the default) is standard alpha blending -- see BlendModes for multiply, screen etc effects"`
+	BgImageStyle     BgImageStyles `desc:"how the BgImage is drawn within the viewport: tiled, stretched to fit, or centered"`
+	Geom             Geom2DInt     `desc:"Viewport-level viewbox within any parent Viewport2D"`
+	Render           RenderState   `copy:"-" json:"-" xml:"-" view:"-" desc:"render state for rendering"`
+	Pixels           *image.RGBA   `copy:"-" json:"-" xml:"-" view:"-" desc:"live pixels that we render into"`
+	Win              *Window       `copy:"-" json:"-" xml:"-" desc:"our parent window that we render into"`
+	OverPix          *image.RGBA   `copy:"-" json:"-" xml:"-" view:"-" desc:"overlay pixels for transient graphics (selection boxes, guides, etc) composited on top of Pixels during upload -- nil if no overlay is active -- see DrawOverlay, ClearOverlay"`
+	OverRender       RenderState   `copy:"-" json:"-" xml:"-" view:"-" desc:"render state for rendering into the overlay"`
+	CompPix          *image.RGBA   `copy:"-" json:"-" xml:"-" view:"-" desc:"scratch buffer for compositing Pixels + OverPix for upload -- only allocated when an overlay is active"`
+	CurStyleNode     Node2D        `copy:"-" json:"-" xml:"-" view:"-" desc:"CurStyleNode2D is always set to the current node that is being styled used for finding url references -- only active during a Style pass"`
+	CurColor         Color         `copy:"-" json:"-" xml:"-" view:"-" desc:"CurColor is automatically updated from the Color setting of a Style and accessible as a color name in any other style as currentcolor use accessor routines for concurrent-safe access"`
+	UpdtMu           sync.Mutex    `copy:"-" json:"-" xml:"-" view:"-" desc:"UpdtMu is mutex for viewport updates"`
+	UpdtStack        []Node2D      `copy:"-" json:"-" xml:"-" view:"-" desc:"stack of nodes requring basic updating"`
+	ReStack          []Node2D      `copy:"-" json:"-" xml:"-" view:"-" desc:"stack of nodes requiring a ReRender (i.e., anchors)"`
+	StackMu          sync.Mutex    `copy:"-" json:"-" xml:"-" view:"-" desc:"StackMu is mutex for adding to UpdtStack"`
+	StyleMu          sync.RWMutex  `copy:"-" json:"-" xml:"-" view:"-" desc:"StyleMu is RW mutex protecting access to Style-related global vars"`
 }
 
 var KiT_Viewport2D = kit.Types.AddType(&Viewport2D{}, Viewport2DProps)
@@ -76,11 +87,23 @@ func (vp *Viewport2D) CopyFieldsFrom(frm interface{}) {
 	fr := frm.(*Viewport2D)
 	vp.WidgetBase.CopyFieldsFrom(&fr.WidgetBase)
 	vp.Fill = fr.Fill
+	vp.DevicePixelRatio = fr.DevicePixelRatio
+	vp.SuperSample = fr.SuperSample
+	vp.BgImageStyle = fr.BgImageStyle
+	vp.BlendMode = fr.BlendMode
 	vp.Geom = fr.Geom
 }
 
 // NewViewport2D creates a new Pixels Image with the specified width and height,
-// and initializes the renderer etc
+// and initializes the renderer etc.
+//
+// Such a standalone viewport can be used entirely headless, without any
+// Window or GL context, e.g., for server-side / batch image generation:
+//
+//	vp := gi.NewViewport2D(width, height)
+//	// ... add and configure widget nodes under vp ...
+//	vp.FullRender2DTree() // renders into vp.Pixels
+//	vp.SavePNG("image.png")
 func NewViewport2D(width, height int) *Viewport2D {
 	sz := image.Point{width, height}
 	vp := &Viewport2D{
@@ -91,27 +114,206 @@ func NewViewport2D(width, height int) *Viewport2D {
 	return vp
 }
 
-// Resize resizes the viewport, creating a new image -- updates Geom Size
+// NewViewport2DForRGBA creates a new viewport that renders directly into
+// given existing image.RGBA backing buffer, e.g., one shared with a texture
+// upload pipeline.  The viewport size is taken from the image bounds.
+// See SetBacking for re-binding after a Resize.
+func NewViewport2DForRGBA(im *image.RGBA) *Viewport2D {
+	sz := im.Bounds().Size()
+	vp := &Viewport2D{
+		Geom: Geom2DInt{Size: sz},
+	}
+	vp.Pixels = im
+	vp.Render.Init(sz.X, sz.Y, vp.Pixels)
+	return vp
+}
+
+// SetBacking rebinds rendering to given caller-owned backing buffer, without
+// any allocation or copying: the image bounds must match the current
+// viewport size, else an error is returned.  Note that Resize to a different
+// size allocates fresh internal pixels, so the caller must SetBacking again
+// with a matching buffer after any such resize.
+func (vp *Viewport2D) SetBacking(im *image.RGBA) error {
+	sz := im.Bounds().Size()
+	if sz != vp.Geom.Size {
+		err := fmt.Errorf("gi.Viewport2D SetBacking: image size %v != viewport size %v", sz, vp.Geom.Size)
+		log.Println(err)
+		return err
+	}
+	vp.Pixels = im
+	vp.Render.Init(sz.X, sz.Y, vp.Pixels)
+	vp.CompPix = nil
+	return nil
+}
+
+// DevPixScale returns the device pixel scaling factor: DevicePixelRatio
+// if > 0, else 1 (1:1 mapping).
+func (vp *Viewport2D) DevPixScale() float32 {
+	if vp.DevicePixelRatio > 0 {
+		return vp.DevicePixelRatio
+	}
+	return 1
+}
+
+// DevicePixelSize returns given logical size scaled to physical device
+// pixels according to DevicePixelRatio.
+func (vp *Viewport2D) DevicePixelSize(sz image.Point) image.Point {
+	sc := vp.DevPixScale()
+	if sc == 1 {
+		return sz
+	}
+	return image.Point{int(float32(sz.X)*sc + .5), int(float32(sz.Y)*sc + .5)}
+}
+
+// LogicalToDevice converts a point in logical (DPI-independent) coordinates
+// to physical device pixel coordinates in the Pixels image.
+func (vp *Viewport2D) LogicalToDevice(pt image.Point) image.Point {
+	sc := vp.DevPixScale()
+	if sc == 1 {
+		return pt
+	}
+	return image.Point{int(float32(pt.X)*sc + .5), int(float32(pt.Y)*sc + .5)}
+}
+
+// DeviceToLogical converts a point in physical device pixel coordinates in
+// the Pixels image to logical (DPI-independent) coordinates.
+func (vp *Viewport2D) DeviceToLogical(pt image.Point) image.Point {
+	sc := vp.DevPixScale()
+	if sc == 1 {
+		return pt
+	}
+	return image.Point{int(float32(pt.X)/sc + .5), int(float32(pt.Y)/sc + .5)}
+}
+
+// SSScale returns the effective supersampling factor: SuperSample if > 1,
+// else 1 (off).
+func (vp *Viewport2D) SSScale() int {
+	if vp.SuperSample > 1 {
+		return vp.SuperSample
+	}
+	return 1
+}
+
+// SetSuperSample sets the SuperSample factor, reallocating the Pixels buffer
+// at the new physical size if already allocated -- requires a full re-render
+// after any change.  See SuperSample for semantics.
+func (vp *Viewport2D) SetSuperSample(ss int) {
+	if ss == vp.SuperSample {
+		return
+	}
+	oss := vp.SSScale()
+	vp.SuperSample = ss
+	if vp.Pixels == nil {
+		return
+	}
+	psz := vp.Geom.Size // physical under old supersample
+	lsz := vp.DeviceToLogical(image.Point{psz.X / oss, psz.Y / oss})
+	vp.Resize(lsz)
+}
+
+// SetDevicePixelRatio sets the DevicePixelRatio, reallocating the Pixels
+// buffer at the new physical size if already allocated -- requires a full
+// re-render after any change.  See DevicePixelRatio for semantics.
+func (vp *Viewport2D) SetDevicePixelRatio(dpr float32) {
+	if dpr == vp.DevicePixelRatio {
+		return
+	}
+	osc := vp.DevPixScale()
+	vp.DevicePixelRatio = dpr
+	if vp.Pixels == nil {
+		return
+	}
+	ss := vp.SSScale()
+	psz := vp.Geom.Size // physical under old scale
+	lsz := image.Point{int(float32(psz.X/ss)/osc + .5), int(float32(psz.Y/ss)/osc + .5)}
+	vp.Resize(lsz)
+}
+
+// Resize resizes the viewport, creating a new image -- updates Geom Size.
+// nwsz is in logical coordinates: the Pixels buffer (and Geom.Size) is
+// scaled up by DevicePixelRatio, if set (see DevicePixelSize), and by
+// SuperSample, if on.
 func (vp *Viewport2D) Resize(nwsz image.Point) {
 	if nwsz.X == 0 || nwsz.Y == 0 {
 		return
 	}
+	psz := vp.DevicePixelSize(nwsz)
+	if ss := vp.SSScale(); ss > 1 {
+		psz = psz.Mul(ss)
+	}
 	if vp.Pixels != nil {
 		ib := vp.Pixels.Bounds().Size()
-		if ib == nwsz {
-			vp.Geom.Size = nwsz // make sure
-			return              // already good
+		if ib == psz {
+			vp.Geom.Size = psz // make sure
+			return             // already good
 		}
 	}
 	if vp.Pixels != nil {
 		vp.Pixels = nil
 	}
-	vp.Pixels = image.NewRGBA(image.Rectangle{Max: nwsz})
-	vp.Render.Init(nwsz.X, nwsz.Y, vp.Pixels)
-	vp.Geom.Size = nwsz // make sure
+	vp.Pixels = image.NewRGBA(image.Rectangle{Max: psz})
+	vp.Render.Init(psz.X, psz.Y, vp.Pixels)
+	if vp.OverPix != nil { // overlay tracks the main pixel buffer size
+		vp.OverPix = image.NewRGBA(image.Rectangle{Max: psz})
+		vp.OverRender.Init(psz.X, psz.Y, vp.OverPix)
+	}
+	vp.CompPix = nil
+	vp.Geom.Size = psz // make sure
 	// fmt.Printf("vp %v resized to: %v, bounds: %v\n", vp.PathUnique(), nwsz, vp.Pixels.Bounds())
 }
 
+//////////////////////////////////////////////////////////////////////////////////
+//  Overlay
+
+// DrawOverlay calls the given function with a render state that draws into
+// the overlay pixel buffer, which is composited on top of Pixels whenever
+// the viewport image is uploaded -- use for transient graphics such as
+// rubber-band selection boxes or alignment guides, without re-rendering the
+// underlying scene.  The overlay is allocated on first use, at the same size
+// as Pixels, and is resized along with it.  Call ClearOverlay to remove it.
+func (vp *Viewport2D) DrawOverlay(fun func(rs *RenderState)) {
+	if vp.Pixels == nil {
+		return
+	}
+	sz := vp.Pixels.Bounds().Size()
+	if vp.OverPix == nil || vp.OverPix.Bounds().Size() != sz {
+		vp.OverPix = image.NewRGBA(image.Rectangle{Max: sz})
+		vp.OverRender.Init(sz.X, sz.Y, vp.OverPix)
+	} else {
+		draw.Draw(vp.OverPix, vp.OverPix.Bounds(), image.Transparent, image.ZP, draw.Src)
+	}
+	fun(&vp.OverRender)
+	vp.RenderViewport2D() // upload composited image without re-rendering tree
+}
+
+// ClearOverlay removes any active overlay and re-uploads the viewport image
+// without it.
+func (vp *Viewport2D) ClearOverlay() {
+	if vp.OverPix == nil {
+		return
+	}
+	vp.OverPix = nil
+	vp.CompPix = nil
+	vp.RenderViewport2D()
+}
+
+// UploadPix returns the image that should be uploaded to the window or
+// parent: Pixels if no overlay is active, else Pixels with OverPix
+// composited on top (in a separate scratch buffer, so Pixels itself is
+// never disturbed).  If SuperSample is on, the result is area-averaged
+// back down to the non-supersampled size.
+func (vp *Viewport2D) UploadPix() *image.RGBA {
+	if vp.OverPix == nil || vp.Pixels == nil {
+		return DownsampleRGBA(vp.Pixels, vp.SSScale())
+	}
+	if vp.CompPix == nil || vp.CompPix.Bounds() != vp.Pixels.Bounds() {
+		vp.CompPix = image.NewRGBA(vp.Pixels.Bounds())
+	}
+	draw.Draw(vp.CompPix, vp.CompPix.Bounds(), vp.Pixels, image.ZP, draw.Src)
+	draw.Draw(vp.CompPix, vp.CompPix.Bounds(), vp.OverPix, image.ZP, draw.Over)
+	return DownsampleRGBA(vp.CompPix, vp.SSScale())
+}
+
 // VpFlags extend NodeBase NodeFlags to hold viewport state
 type VpFlags int
 
@@ -261,6 +463,26 @@ func (vp *Viewport2D) SetCurWin() {
 ////////////////////////////////////////////////////////////////////////////////////////
 //  Main Rendering code
 
+// UploadToTexture uploads the viewport image (including any overlay) to the
+// given GPU texture, transferring only the changed region given by dirtyRect
+// (in viewport pixel coordinates) to minimize upload bandwidth -- pass the
+// full Pixels bounds to upload everything, e.g., after a full re-render or
+// resize.  The texture must be at least the size of the viewport; this is
+// the bridge for feeding a 2D viewport into a GPU texture (3D material,
+// window texture, etc).
+// Must be called with a valid gpu context and on proper thread for that context.
+func (vp *Viewport2D) UploadToTexture(tex oswin.Texture, dirtyRect image.Rectangle) error {
+	if vp.Pixels == nil || tex == nil {
+		return nil
+	}
+	img := vp.UploadPix()
+	dr := dirtyRect.Intersect(img.Bounds())
+	if dr.Empty() {
+		return nil
+	}
+	return tex.SetSubImage(dr.Min, img, dr)
+}
+
 // UploadMainToWin is the update call for the main viewport for a window --
 // calls UploadAllViewports in parent window, which uploads the main viewport
 // and any active popups etc over the top of that
@@ -298,7 +520,7 @@ func (vp *Viewport2D) DrawIntoParent(parVp *Viewport2D) {
 	if Render2DTrace {
 		fmt.Printf("Render: vp DrawIntoParent: %v parVp: %v rect: %v sp: %v\n", vp.PathUnique(), parVp.PathUnique(), r, sp)
 	}
-	draw.Draw(parVp.Pixels, r, vp.Pixels, sp, draw.Over)
+	BlendImage(parVp.Pixels, r, vp.UploadPix(), sp, vp.BlendMode)
 }
 
 // ReRender2DNode re-renders a specific node, including uploading updated bits to
@@ -341,6 +563,42 @@ func (vp *Viewport2D) ReRender2DAnchor(gni Node2D) {
 	vp.Win.UploadVpRegion(vp, pw.VpBBox, pw.WinBBox)
 }
 
+// NodesAtPoint returns all nodes in this viewport whose WinBBox contains
+// the given window point, in rendering order (parents before children,
+// later siblings after earlier ones), so the last element is the topmost
+// node at that point.  Invisible nodes, and their subtrees, are skipped.
+// Uses the existing computed WinBBox values, so results are only valid
+// after layout and render have been done.
+func (vp *Viewport2D) NodesAtPoint(pt image.Point) []Node2D {
+	var nds []Node2D
+	vp.FuncDownMeFirst(0, vp.This(), func(k ki.Ki, level int, d interface{}) bool {
+		nii, ni := KiToNode2D(k)
+		if nii == nil || ni.This() == nil {
+			return true
+		}
+		if !nii.IsVisible() {
+			return false // children cannot be visible either
+		}
+		if !pt.In(ni.WinBBox) {
+			return false // children are clipped to parent bbox
+		}
+		nds = append(nds, nii)
+		return true
+	})
+	return nds
+}
+
+// NodeAtPoint returns the topmost visible node whose WinBBox contains the
+// given window point -- the deepest, latest-rendered such node -- or nil
+// if there is none.  See NodesAtPoint for the full stack under the point.
+func (vp *Viewport2D) NodeAtPoint(pt image.Point) Node2D {
+	nds := vp.NodesAtPoint(pt)
+	if len(nds) == 0 {
+		return nil
+	}
+	return nds[len(nds)-1]
+}
+
 // Delete this popup viewport -- has already been disconnected from window
 // events and parent is nil -- called by window when a popup is deleted -- it
 // destroys the vp and its main layout, see VpFlagPopupDestroyAll for whether
@@ -477,6 +735,11 @@ func (vp *Viewport2D) RenderViewport2D() {
 			fmt.Printf("Render: %v at %v DrawIntoParent\n", vp.PathUnique(), vp.VpBBox)
 		}
 		vp.DrawIntoParent(vp.Viewport)
+	} else if vp.Win == nil { // windowless main vp (headless rendering)
+		if Render2DTrace {
+			fmt.Printf("Render: %v at %v headless -- result in Pixels\n", vp.PathUnique(), vp.VpBBox)
+		}
+		// nothing to do -- rendered result is in Pixels, e.g., for SavePNG
 	} else { // we are the main vp
 		if Render2DTrace {
 			fmt.Printf("Render: %v at %v UploadMainToWin\n", vp.PathUnique(), vp.VpBBox)
@@ -539,6 +802,64 @@ func (vp *Viewport2D) Move2D(delta image.Point, parBBox image.Rectangle) {
 	vp.Move2DChildren(image.ZP) // reset delta here -- we absorb the delta in our placement relative to the parent
 }
 
+// BgImageStyles defines how a Viewport2D BgImage is placed within the viewport
+type BgImageStyles int32
+
+const (
+	// BgTile repeats the image across the full viewport area
+	BgTile BgImageStyles = iota
+
+	// BgStretch scales the image to fill the full viewport area
+	BgStretch
+
+	// BgCenter draws the image once, centered in the viewport
+	BgCenter
+
+	BgImageStylesN
+)
+
+//go:generate stringer -type=BgImageStyles
+
+var KiT_BgImageStyles = kit.Enums.AddEnumAltLower(BgImageStylesN, kit.NotBitFlag, StylePropProps, "Bg")
+
+func (ev BgImageStyles) MarshalJSON() ([]byte, error)  { return kit.EnumMarshalJSON(ev) }
+func (ev *BgImageStyles) UnmarshalJSON(b []byte) error { return kit.EnumUnmarshalJSON(ev, b) }
+
+// DrawBgImage draws the BgImage into the viewport pixels according to
+// BgImageStyle, respecting the current render clip bounds, composited Over
+// so children and any Fill background still show appropriately -- called
+// from Render2D after FillViewport and before children render.
+func (vp *Viewport2D) DrawBgImage() {
+	if vp.BgImage == nil || vp.Pixels == nil {
+		return
+	}
+	rs := &vp.Render
+	rs.Lock()
+	defer rs.Unlock()
+	clip := rs.Bounds
+	isz := vp.BgImage.Bounds().Size()
+	vsz := vp.Geom.Size
+	switch vp.BgImageStyle {
+	case BgTile:
+		for y := 0; y < vsz.Y; y += isz.Y {
+			for x := 0; x < vsz.X; x += isz.X {
+				r := image.Rectangle{Min: image.Point{X: x, Y: y}, Max: image.Point{X: x + isz.X, Y: y + isz.Y}}
+				ir := r.Intersect(clip)
+				sp := vp.BgImage.Bounds().Min.Add(ir.Min.Sub(r.Min)) // track any clipping of r
+				draw.Draw(vp.Pixels, ir, vp.BgImage, sp, draw.Over)
+			}
+		}
+	case BgStretch:
+		xdraw.BiLinear.Scale(vp.Pixels, image.Rectangle{Max: vsz}, vp.BgImage, vp.BgImage.Bounds(), xdraw.Over, nil)
+	case BgCenter:
+		mp := image.Point{X: (vsz.X - isz.X) / 2, Y: (vsz.Y - isz.Y) / 2}
+		r := image.Rectangle{Min: mp, Max: mp.Add(isz)}
+		ir := r.Intersect(clip)
+		sp := vp.BgImage.Bounds().Min.Add(ir.Min.Sub(r.Min)) // track any clipping of r
+		draw.Draw(vp.Pixels, ir, vp.BgImage, sp, draw.Over)
+	}
+}
+
 func (vp *Viewport2D) FillViewport() {
 	rs := &vp.Render
 	rs.Lock()
@@ -554,6 +875,9 @@ func (vp *Viewport2D) Render2D() {
 		if vp.Fill {
 			vp.FillViewport()
 		}
+		if vp.BgImage != nil {
+			vp.DrawBgImage()
+		}
 		vp.Render2DChildren() // we must do children first, then us!
 		vp.RenderViewport2D() // update our parent image
 		vp.PopBounds()
@@ -815,11 +1139,23 @@ func (vp *Viewport2D) CurrentColor() Color {
 //  Image utilities
 
 // SavePNG encodes the image as a PNG and writes it to disk.
+// The exported image is the Pixels buffer, i.e., physical device pixels:
+// logical size * DevicePixelRatio, if that is set.  If SuperSample is on,
+// the downsampled result is exported -- see SavePNGFullRes for the
+// full-resolution buffer.
 func (vp *Viewport2D) SavePNG(path string) error {
+	return SavePNG(path, DownsampleRGBA(vp.Pixels, vp.SSScale()))
+}
+
+// SavePNGFullRes encodes the image as a PNG and writes it to disk, at the
+// full resolution of the Pixels buffer, without any SuperSample downscaling
+// -- same as SavePNG when SuperSample is off.
+func (vp *Viewport2D) SavePNGFullRes(path string) error {
 	return SavePNG(path, vp.Pixels)
 }
 
 // EncodePNG encodes the image as a PNG and writes it to the provided io.Writer.
+// If SuperSample is on, the downsampled result is encoded, as for SavePNG.
 func (vp *Viewport2D) EncodePNG(w io.Writer) error {
-	return png.Encode(w, vp.Pixels)
+	return png.Encode(w, DownsampleRGBA(vp.Pixels, vp.SSScale()))
 }
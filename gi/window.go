@@ -115,20 +115,20 @@ var WindowOpenTimer time.Time
 // what you're doing (and it might change over time too..)
 //
 // Rendering logic:
-// * oswin.Texture is a GPU Texture that can be uploaded very quickly to window
-//   or to another texture.  Viewport2D has image.RGBA Pixels that 2D draws onto,
-//   and this can be efficiently uploaded to Texture.
-//   (at some point, could consider GPU accelerated rendering but not necc and
-//    adds a lot of complexity and dependency -- very nice and simple to use basic
-///   CPU-based bitmap rendering)
-// * OSWin has a WinTex that is blitted up to actual window using GPU code (Draw).
-// * Master Viewport is uploaded to WinTex first as the "base layer"
-// * Then DirectUps (e.g., gi3d.Scene) directly upload their own texture to WinTex
-//   (note: cannot upload directly to window as this prevents popups and overlays)
-// * Then any Popups (which have their own Viewports) upload to WinTex.
-// * Finally if there are any overlays (sprites), then we need a separate
-//   transparent texture, OverTex, which critically allows WinTex to remain
-//   intact while overlays are updated.
+//   - oswin.Texture is a GPU Texture that can be uploaded very quickly to window
+//     or to another texture.  Viewport2D has image.RGBA Pixels that 2D draws onto,
+//     and this can be efficiently uploaded to Texture.
+//     (at some point, could consider GPU accelerated rendering but not necc and
+//     adds a lot of complexity and dependency -- very nice and simple to use basic
+//     CPU-based bitmap rendering)
+//   - OSWin has a WinTex that is blitted up to actual window using GPU code (Draw).
+//   - Master Viewport is uploaded to WinTex first as the "base layer"
+//   - Then DirectUps (e.g., gi3d.Scene) directly upload their own texture to WinTex
+//     (note: cannot upload directly to window as this prevents popups and overlays)
+//   - Then any Popups (which have their own Viewports) upload to WinTex.
+//   - Finally if there are any overlays (sprites), then we need a separate
+//     transparent texture, OverTex, which critically allows WinTex to remain
+//     intact while overlays are updated.
 type Window struct {
 	NodeBase
 	Title             string            `desc:"displayed name of window, for window manager etc -- window object name is the internal handle and is used for tracking property info etc"`
@@ -630,15 +630,36 @@ func (w *Window) WinViewport2D() *Viewport2D {
 // from the underlying pixel-level resolution of the window.
 // This will trigger a resize event and be processed
 // that way when it occurs.
-func (w *Window) SetSize(sz image.Point) {
-	w.OSWin.SetSize(sz)
+// Returns true if the requested size was applied exactly, false if it
+// was clamped to fit the screen (see oswin.ConstrainWinGeom).
+func (w *Window) SetSize(sz image.Point) bool {
+	return w.OSWin.SetSize(sz)
 }
 
 // SetPixSize requests that the window be resized to the given size
 // in underlying pixel coordinates, which means that the requested
-// size is divided by the screen's DevicePixelRatio
-func (w *Window) SetPixSize(sz image.Point) {
-	w.OSWin.SetPixSize(sz)
+// size is divided by the screen's DevicePixelRatio.
+// Returns true if the requested size was applied exactly, false if it
+// was clamped to fit the screen (see oswin.ConstrainWinGeom).
+func (w *Window) SetPixSize(sz image.Point) bool {
+	return w.OSWin.SetPixSize(sz)
+}
+
+// KeyState reports whether the given key is currently pressed,
+// according to the most recent key events received by this window.
+// This is useful for polling held keys in game-style loops (e.g., WASD
+// camera movement), as a typed convenience wrapper around the
+// oswin.Window method of the same name.
+func (w *Window) KeyState(code key.Codes) bool {
+	return w.OSWin.KeyState(uint32(code))
+}
+
+// MouseButtonState reports whether the given mouse button is currently
+// pressed, according to the most recent mouse events received by this
+// window.  This is useful for polling in game-style loops, as a typed
+// convenience wrapper around the oswin.Window method of the same name.
+func (w *Window) MouseButtonState(but mouse.Buttons) bool {
+	return w.OSWin.MouseButtonState(int32(but))
 }
 
 // IsResizing means the window is actively being resized by user -- don't try
@@ -947,7 +968,7 @@ func (w *Window) UploadVpRegion(vp *Viewport2D, vpBBox, winBBox image.Rectangle)
 	if Render2DTrace || WinEventTrace {
 		fmt.Printf("Win: %v uploading region Vp %v, vpbbox: %v, wintex bounds: %v\n", w.PathUnique(), vp.PathUnique(), vpBBox, w.OSWin.WinTex().Bounds())
 	}
-	w.OSWin.SetWinTexSubImage(winBBox.Min, vp.Pixels, vpBBox)
+	w.OSWin.SetWinTexSubImage(winBBox.Min, vp.UploadPix(), vpBBox)
 	// pr.End()
 	w.ClearWinUpdating()
 	w.UpMu.Unlock()
@@ -970,7 +991,7 @@ func (w *Window) UploadVp(vp *Viewport2D, offset image.Point) {
 	if Render2DTrace || WinEventTrace {
 		fmt.Printf("Win: %v uploading Vp %v, image bound: %v, wintex bounds: %v\n", w.PathUnique(), vp.PathUnique(), vp.Pixels.Bounds(), w.OSWin.WinTex().Bounds())
 	}
-	w.OSWin.SetWinTexSubImage(offset, vp.Pixels, vp.Pixels.Bounds())
+	w.OSWin.SetWinTexSubImage(offset, vp.UploadPix(), vp.Pixels.Bounds())
 	// pr.End()
 	w.ClearWinUpdating()
 	w.ClearFlag(int(WinFlagPublishFullReRender))
@@ -1026,7 +1047,7 @@ func (w *Window) UploadAllViewports() {
 	if Render2DTrace || WinEventTrace {
 		fmt.Printf("Win: %v uploading full Vp, image bound: %v, wintex bounds: %v updt: %v\n", w.PathUnique(), w.Viewport.Pixels.Bounds(), w.OSWin.WinTex().Bounds(), updt)
 	}
-	w.OSWin.SetWinTexSubImage(image.ZP, w.Viewport.Pixels, w.Viewport.Pixels.Bounds())
+	w.OSWin.SetWinTexSubImage(image.ZP, w.Viewport.UploadPix(), w.Viewport.Pixels.Bounds())
 	// next any direct uploaders
 	w.DirectUploads()
 	// then all the current popups
@@ -1041,7 +1062,7 @@ func (w *Window) UploadAllViewports() {
 				if Render2DTrace {
 					fmt.Printf("Win: %v uploading popup stack Vp %v, image bound: %v, wintex bounds: %v\n", w.PathUnique(), vp.PathUnique(), r.Min, vp.Pixels.Bounds())
 				}
-				w.OSWin.SetWinTexSubImage(r.Min, vp.Pixels, vp.Pixels.Bounds())
+				w.OSWin.SetWinTexSubImage(r.Min, vp.UploadPix(), vp.Pixels.Bounds())
 			}
 		}
 	}
@@ -1053,7 +1074,7 @@ func (w *Window) UploadAllViewports() {
 			if Render2DTrace || WinEventTrace {
 				fmt.Printf("Win: %v uploading top popup Vp %v, image bound: %v, wintex bounds: %v\n", w.PathUnique(), vp.PathUnique(), r.Min, vp.Pixels.Bounds())
 			}
-			w.OSWin.SetWinTexSubImage(r.Min, vp.Pixels, vp.Pixels.Bounds())
+			w.OSWin.SetWinTexSubImage(r.Min, vp.UploadPix(), vp.Pixels.Bounds())
 		}
 	}
 	w.PopMu.RUnlock()
@@ -2267,7 +2288,8 @@ func (w *Window) SetFocus(k ki.Ki) bool {
 	return true
 }
 
-// 	FocusNext sets the focus on the next item that can accept focus after the
+//	FocusNext sets the focus on the next item that can accept focus after the
+//
 // given item (can be nil) -- returns true if a focus item found.
 func (w *Window) FocusNext(foc ki.Ki) bool {
 	gotFocus := false
@@ -0,0 +1,171 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goki/gi/mat32"
+	"github.com/goki/ki/kit"
+)
+
+// AnimChannel is one channel of an AnimClip: keyframes animating the local
+// Pose of one joint in a Skeleton over time.  Times are in seconds in
+// increasing order, and each of the Pos / Quat / Scale keyframe slices is
+// either nil (that component is not animated) or the same length as Times.
+// Values between keyframes are linearly interpolated (spherical for Quat),
+// and the first / last keyframe value is held outside the keyframe range.
+type AnimChannel struct {
+	Joint int          `desc:"index of the joint in the Skeleton that this channel animates"`
+	Times []float32    `desc:"keyframe times in seconds, in increasing order"`
+	Pos   []mat32.Vec3 `desc:"position keyframes -- nil or same length as Times"`
+	Quat  []mat32.Quat `desc:"rotation keyframes -- nil or same length as Times"`
+	Scale []mat32.Vec3 `desc:"scale keyframes -- nil or same length as Times"`
+}
+
+// Apply applies this channel to given skeleton at given time (seconds),
+// setting the animated components of the joint's local Pose.
+func (ac *AnimChannel) Apply(sk *Skeleton, t float32) {
+	n := len(ac.Times)
+	if ac.Joint < 0 || ac.Joint >= len(sk.Joints) || n == 0 {
+		return
+	}
+	ps := &sk.Joints[ac.Joint].Pose
+	i := sort.Search(n, func(i int) bool { return ac.Times[i] > t }) // first keyframe after t
+	if i == 0 || i == n {                                            // outside keyframe range: hold first / last
+		ki := i
+		if ki == n {
+			ki = n - 1
+		}
+		if ac.Pos != nil {
+			ps.Pos = ac.Pos[ki]
+		}
+		if ac.Quat != nil {
+			ps.Quat = ac.Quat[ki]
+		}
+		if ac.Scale != nil {
+			ps.Scale = ac.Scale[ki]
+		}
+		return
+	}
+	prv := i - 1
+	amt := float32(0)
+	if dt := ac.Times[i] - ac.Times[prv]; dt > 0 {
+		amt = (t - ac.Times[prv]) / dt
+	}
+	if ac.Pos != nil {
+		ps.Pos = ac.Pos[prv].Lerp(ac.Pos[i], amt)
+	}
+	if ac.Quat != nil {
+		q := ac.Quat[prv]
+		q.Slerp(ac.Quat[i], amt)
+		ps.Quat = q
+	}
+	if ac.Scale != nil {
+		ps.Scale = ac.Scale[prv].Lerp(ac.Scale[i], amt)
+	}
+}
+
+// AnimClip is a named animation clip: a set of per-joint keyframe channels
+// and an overall duration, driving the joint poses of a Skeleton over time.
+// Clips live in the Scene Anims library (AddAnim) and are played back on a
+// given skeleton via Scene.PlayAnim, advanced by Scene.AnimStep.
+type AnimClip struct {
+	Nm       string        `desc:"name of the clip -- clips are looked up by name in PlayAnim"`
+	Duration float32       `desc:"duration of the clip in seconds -- typically the last keyframe time -- playback loops or stops here"`
+	Channels []AnimChannel `desc:"per-joint keyframe channels"`
+}
+
+var KiT_AnimClip = kit.Types.AddType(&AnimClip{}, nil)
+
+// Apply applies the clip to given skeleton at given time (seconds), setting
+// the animated joint poses and updating the skinning matrix palette
+// (Skeleton.UpdateMats), ready for the next render.
+func (cl *AnimClip) Apply(sk *Skeleton, t float32) {
+	for i := range cl.Channels {
+		cl.Channels[i].Apply(sk, t)
+	}
+	sk.UpdateMats()
+}
+
+// AnimPlay is the playback state of one active animation: a clip driving a
+// skeleton -- managed by Scene.PlayAnim / StopAnim / AnimStep.
+type AnimPlay struct {
+	Clip  *AnimClip `desc:"the clip being played"`
+	Skel  *Skeleton `desc:"the skeleton being driven"`
+	Time  float32   `desc:"current playback time in seconds"`
+	Speed float32   `desc:"playback speed multiplier -- 1 = normal speed -- set by PlayAnim to 1"`
+	Loop  bool      `desc:"wrap back to the start when the end of the clip is reached -- otherwise playback holds the final pose and is removed"`
+}
+
+// AddAnim adds given animation clip to the scene Anims library, under its
+// clip name, so it can be played back by name via PlayAnim.
+func (sc *Scene) AddAnim(an *AnimClip) {
+	if sc.Anims == nil {
+		sc.Anims = make(map[string]*AnimClip)
+	}
+	sc.Anims[an.Nm] = an
+}
+
+// AnimByNameTry looks for animation clip by name -- returns error if not found
+func (sc *Scene) AnimByNameTry(nm string) (*AnimClip, error) {
+	an, ok := sc.Anims[nm]
+	if ok {
+		return an, nil
+	}
+	return nil, fmt.Errorf("Anim named: %v not found in Scene: %v", nm, sc.Nm)
+}
+
+// PlayAnim starts playback of named animation clip (see AddAnim) on given
+// skeleton, stopping any prior animation on that skeleton, and returns the
+// AnimPlay tracking the playback (e.g., to adjust Speed or Time).
+// Playback is advanced by calling AnimStep from your app's tick loop --
+// the scene does not run its own animation clock.
+func (sc *Scene) PlayAnim(sk *Skeleton, clipNm string, loop bool) (*AnimPlay, error) {
+	an, err := sc.AnimByNameTry(clipNm)
+	if err != nil {
+		return nil, err
+	}
+	sc.StopAnim(sk)
+	ap := &AnimPlay{Clip: an, Skel: sk, Speed: 1, Loop: loop}
+	an.Apply(sk, 0)
+	sc.AnimPlays = append(sc.AnimPlays, ap)
+	return ap, nil
+}
+
+// StopAnim stops any active animation playback on given skeleton,
+// leaving the joints in their current pose.
+func (sc *Scene) StopAnim(sk *Skeleton) {
+	for i := len(sc.AnimPlays) - 1; i >= 0; i-- {
+		if sc.AnimPlays[i].Skel == sk {
+			sc.AnimPlays = append(sc.AnimPlays[:i], sc.AnimPlays[i+1:]...)
+		}
+	}
+}
+
+// AnimStep advances all active animation playbacks by dt seconds (times
+// their Speed), applying the clips to their skeletons.  Non-looping
+// playbacks are removed when they reach the end of the clip.  Returns true
+// if any playbacks remain active.  Call this from your app's update / tick
+// loop (e.g., a time.Ticker goroutine) and then trigger a re-render of the
+// scene to display the updated poses.
+func (sc *Scene) AnimStep(dt float32) bool {
+	for i := len(sc.AnimPlays) - 1; i >= 0; i-- {
+		ap := sc.AnimPlays[i]
+		ap.Time += dt * ap.Speed
+		dur := ap.Clip.Duration
+		if dur > 0 && ap.Time >= dur {
+			if ap.Loop {
+				ap.Time = mat32.Mod(ap.Time, dur)
+			} else {
+				ap.Time = dur
+				sc.AnimPlays = append(sc.AnimPlays[:i], sc.AnimPlays[i+1:]...)
+			}
+		}
+		ap.Clip.Apply(ap.Skel, ap.Time)
+	}
+	return len(sc.AnimPlays) > 0
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/mat32"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// ScreenCorners are the corners of the 2D view, for positioning screen-space
+// elements such as the AxisIndicator.
+type ScreenCorners int32
+
+const (
+	// CornerLowerLeft is the bottom-left corner of the view (the default)
+	CornerLowerLeft ScreenCorners = iota
+
+	// CornerLowerRight is the bottom-right corner of the view
+	CornerLowerRight
+
+	// CornerUpperLeft is the top-left corner of the view
+	CornerUpperLeft
+
+	// CornerUpperRight is the top-right corner of the view
+	CornerUpperRight
+
+	ScreenCornersN
+)
+
+//go:generate stringer -type=ScreenCorners
+
+var KiT_ScreenCorners = kit.Enums.AddEnum(ScreenCornersN, kit.NotBitFlag, nil)
+
+// AxisIndicatorName is the reserved top-level child name for the screen-space
+// axis indicator -- see Scene.ShowAxisIndicator
+const AxisIndicatorName = "AxisIndicator"
+
+// AxisIndicator is a small screen-space navigation aid: three axis-colored
+// arrows (world X red, Y green, Z blue -- see GizmoAxisColors) drawn in a
+// corner of the view, always the same size, rotating with the camera so the
+// current orientation is visible at a glance.  It is managed automatically
+// from the Scene.ShowAxisIndicator and AxisIndicatorCorner settings (see
+// ConfigAxisIndicator) and rendered in the overlay layer, on top of the
+// scene.  Each render it re-poses itself in front of the camera at the
+// configured corner, world-axis aligned.
+type AxisIndicator struct {
+	Group
+	Corner ScreenCorners `desc:"which corner of the view the indicator is drawn in -- set from Scene.AxisIndicatorCorner"`
+	Size   float32       `desc:"length of the axis arrows as a fraction of the view height -- default .1"`
+}
+
+var KiT_AxisIndicator = kit.Types.AddType(&AxisIndicator{}, AxisIndicatorProps)
+
+var AxisIndicatorProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+}
+
+func (ai *AxisIndicator) Defaults() {
+	ai.Group.Defaults()
+	ai.Size = .1
+}
+
+// ConfigAxisIndicator adds, removes, or updates the screen-space axis
+// indicator according to the ShowAxisIndicator and AxisIndicatorCorner scene
+// settings -- called automatically at the start of each Render.
+func (sc *Scene) ConfigAxisIndicator() {
+	aii, err := sc.ChildByNameTry(AxisIndicatorName, 0)
+	if !sc.ShowAxisIndicator {
+		if err == nil {
+			sc.DeleteChildByName(AxisIndicatorName, true)
+		}
+		return
+	}
+	var ai *AxisIndicator
+	if err != nil {
+		ai = sc.AddNewChild(KiT_AxisIndicator, AxisIndicatorName).(*AxisIndicator)
+		ai.Defaults()
+		ai.Config(sc)
+		for _, nm := range []string{AxisIndicatorName + "-shaft", AxisIndicatorName + "-cone"} {
+			sc.InitMesh(nm)
+		}
+	} else {
+		ai, _ = aii.Embed(KiT_AxisIndicator).(*AxisIndicator)
+		if ai == nil {
+			return
+		}
+	}
+	ai.Corner = sc.AxisIndicatorCorner
+}
+
+// Config builds the three axis arrows, creating the shared meshes in the
+// scene if not already present.
+func (ai *AxisIndicator) Config(sc *Scene) {
+	ai.DeleteChildren(true)
+	shaftNm := AxisIndicatorName + "-shaft"
+	if sc.MeshByName(shaftNm) == nil {
+		AddNewCylinder(sc, shaftNm, 1, .04, 8, 1, true, true)
+	}
+	tipNm := AxisIndicatorName + "-cone"
+	if sc.MeshByName(tipNm) == nil {
+		AddNewCone(sc, tipNm, .3, .12, 16, 1, true)
+	}
+	// orient the Y-axis-aligned shaft / cone meshes along given axis
+	orientY := func(sld *Solid, ax mat32.Dims) {
+		switch ax {
+		case mat32.X:
+			sld.Pose.SetAxisRotation(0, 0, 1, -90)
+		case mat32.Z:
+			sld.Pose.SetAxisRotation(1, 0, 0, 90)
+		}
+	}
+	for ax := mat32.X; ax <= mat32.Z; ax++ {
+		var av mat32.Vec3
+		av.SetDim(ax, 1)
+		clr := GizmoAxisColors[ax]
+		sh := AddNewSolid(sc, ai, AxisIndicatorName+"-shaft-"+gizmoAxisNames[ax], shaftNm)
+		sh.Mat.Color = clr
+		sh.Mat.Unlit = true // constant axis color regardless of lighting
+		sh.Layer = LayerOverlay
+		sh.Pose.Pos = av.MulScalar(.5)
+		orientY(sh, ax)
+		tip := AddNewSolid(sc, ai, AxisIndicatorName+"-tip-"+gizmoAxisNames[ax], tipNm)
+		tip.Mat.Color = clr
+		tip.Mat.Unlit = true
+		tip.Layer = LayerOverlay
+		tip.Pose.Pos = av
+		orientY(tip, ax)
+	}
+}
+
+// UpdateNode3D re-poses the indicator in front of the camera at the
+// configured corner during render: position is in camera space (so it stays
+// put on screen), while the rotation remains world-aligned, so the arrows
+// reflect the camera's current orientation.
+func (ai *AxisIndicator) UpdateNode3D(sc *Scene) {
+	cm := &sc.Camera
+	d := cm.Near + 1
+	if cm.Ortho {
+		// ortho view size is derived from the distance to the target
+		d = cm.ViewVector().Length()
+		if d == 0 {
+			d = cm.Far
+		}
+	} else if cm.Far < d {
+		d = .5 * (cm.Near + cm.Far)
+	}
+	hh := d * mat32.Tan(mat32.DegToRad(cm.FOV*0.5)) // half view height at d
+	hw := cm.Aspect * hh
+	sz := ai.Size
+	if sz <= 0 {
+		sz = .1
+	}
+	esz := sz * 2 * hh // arrow length in world units at distance d
+	mrg := 1.25 * esz  // keep the full extent inside the view
+	x := hw - mrg
+	y := hh - mrg
+	switch ai.Corner {
+	case CornerLowerLeft:
+		x, y = -x, -y
+	case CornerLowerRight:
+		y = -y
+	case CornerUpperLeft:
+		x = -x
+	}
+	ai.Pose.Pos = mat32.Vec3{x, y, -d}.MulQuat(cm.Pose.Quat).Add(cm.Pose.Pos)
+	ai.Pose.Quat.SetIdentity() // world-aligned
+	ai.Pose.Scale.SetScalar(esz)
+}
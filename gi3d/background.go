@@ -0,0 +1,114 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"github.com/goki/gi/mat32"
+	"github.com/goki/gi/oswin/gpu"
+)
+
+// RenderBackground renders the scene background as a fullscreen quad,
+// before any 3D content: either a vertical two-color gradient or a 2D
+// background image texture -- a cheap alternative to a full skybox.
+// Depth testing is off while it draws, so all geometry renders over it.
+// See Scene.BgGradient, Scene.BgImage.
+type RenderBackground struct {
+	RenderBase
+	Quad gpu.BufferMgr `desc:"fullscreen quad (2 triangles) used to drive the fragment shader"`
+}
+
+func (rb *RenderBackground) Init(rn *Renderers) error {
+	rb.Nm = "RenderBackground"
+	if rb.Pipe == nil {
+		rb.Pipe = gpu.TheGPU.NewPipeline(rb.Nm)
+		rb.Pipe.AddProgram("VtxFrag")
+	}
+	pl := rb.Pipe
+	pr := pl.ProgramByName("VtxFrag")
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx",
+		`
+layout(location = 0) in vec3 VtxPos;
+out vec2 TexCoord;
+
+void main() {
+	TexCoord = VtxPos.xy * 0.5 + 0.5;
+	gl_Position = vec4(VtxPos.xy, 0.0, 1.0);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.AddShader(gpu.FragmentShader, "Frag",
+		`
+uniform vec4 TopColor;
+uniform vec4 BotColor;
+uniform sampler2D Tex;
+uniform bool UseTex;
+uniform bool FlipY;
+in vec2 TexCoord;
+out vec4 outputColor;
+
+void main() {
+	if (UseTex) {
+		vec2 tc = TexCoord;
+		if (FlipY) {
+			tc.y = 1.0 - tc.y;
+		}
+		outputColor = texture(Tex, tc);
+	} else {
+		outputColor = mix(BotColor, TopColor, TexCoord.y);
+	}
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	pr.AddUniform("TopColor", gpu.Vec4fUniType, false, 0)
+	pr.AddUniform("BotColor", gpu.Vec4fUniType, false, 0)
+	pr.AddUniform("Tex", gpu.IUniType, false, 0)
+	pr.AddUniform("UseTex", gpu.BUniType, false, 0)
+	pr.AddUniform("FlipY", gpu.BUniType, false, 0)
+
+	pr.SetFragDataVar("outputColor")
+
+	return nil
+}
+
+// Activate activates the background program -- unlike the material renders,
+// it does not bind the Camera / Lights uniforms, which it does not use.
+func (rb *RenderBackground) Activate(rn *Renderers) {
+	pr := rb.VtxFragProg()
+	pr.Activate()
+	gpu.TheGPU.ErrCheck("background prog activate")
+}
+
+// InitQuad initializes the fullscreen quad buffer if not already done.
+// Must be called with appropriate context activated, on main.
+func (rb *RenderBackground) InitQuad(rn *Renderers) {
+	if rb.Quad != nil {
+		return
+	}
+	rb.Quad = gpu.TheGPU.NewBufferMgr()
+	vbuf := rb.Quad.AddVectorsBuffer(gpu.StaticDraw)
+	vbuf.AddVectors(rn.Vectors[InVtxPos], false)
+	vtxs := mat32.ArrayF32{
+		-1, -1, 0, 1, -1, 0, 1, 1, 0,
+		-1, -1, 0, 1, 1, 0, -1, 1, 0,
+	}
+	vbuf.SetLen(6)
+	vbuf.SetVecData(rn.Vectors[InVtxPos], vtxs)
+	rb.Quad.Activate()
+	rb.Quad.TransferAll()
+}
+
+func (rb *RenderBackground) Delete(rn *Renderers) {
+	if rb.Quad != nil {
+		rb.Quad.Delete()
+		rb.Quad = nil
+	}
+	rb.RenderBase.Delete(rn)
+}
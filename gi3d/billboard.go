@@ -0,0 +1,58 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"github.com/goki/gi/mat32"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// Billboard is a Solid that automatically rotates each frame to face the
+// camera, for floating labels, particles, tree impostors, etc.
+// It uses the shared 2D plane mesh (same as Text2D) with whatever texture
+// or color is set in the Material (e.g., via Mat.SetTexture).
+// If Cylindrical is set, rotation is locked to the Y (up) axis so the
+// billboard stays upright, only yawing to face the camera -- otherwise it
+// fully matches the camera orientation.
+type Billboard struct {
+	Solid
+	Cylindrical bool `desc:"lock rotation to the Y (up) axis, so the billboard stays upright and only yaws to face the camera (e.g., trees, upright labels) -- otherwise fully matches the camera orientation"`
+}
+
+var KiT_Billboard = kit.Types.AddType(&Billboard{}, nil)
+
+// AddNewBillboard adds a new billboard of given name to given parent,
+// using the shared 2D plane mesh.
+func AddNewBillboard(sc *Scene, parent ki.Ki, name string) *Billboard {
+	bb := parent.AddNewChild(KiT_Billboard, name).(*Billboard)
+	bb.Defaults(sc)
+	return bb
+}
+
+func (bb *Billboard) Defaults(sc *Scene) {
+	tm := sc.PlaneMesh2D()
+	bb.SetMesh(sc, tm)
+	bb.Solid.Defaults()
+}
+
+// UpdateNode3D rotates the billboard to face the camera during render.
+func (bb *Billboard) UpdateNode3D(sc *Scene) {
+	bb.FaceCamera(sc)
+}
+
+// FaceCamera sets the Pose rotation to face the camera, either fully
+// (copying the camera orientation) or only around the Y axis if Cylindrical.
+// Note: this uses the local Pose.Pos, so billboards should be at top level
+// or in groups without rotations for the Cylindrical case to be accurate.
+func (bb *Billboard) FaceCamera(sc *Scene) {
+	if bb.Cylindrical {
+		cd := sc.Camera.Pose.Pos.Sub(bb.Pose.Pos)
+		ang := mat32.Atan2(cd.X, cd.Z)
+		bb.Pose.Quat.SetFromAxisAngle(mat32.Vec3Y, ang)
+	} else {
+		bb.Pose.Quat = sc.Camera.Pose.Quat
+	}
+}
@@ -5,6 +5,10 @@
 package gi3d
 
 import (
+	"fmt"
+	"image"
+	"log"
+
 	"github.com/goki/gi/mat32"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
@@ -15,14 +19,19 @@ type Camera struct {
 	Pose          Pose           `desc:"overall orientation and direction of the camera, relative to pointing at negative Z axis with up (positive Y) direction"`
 	Target        mat32.Vec3     `desc:"target location for the camera -- where it is pointing at -- defaults to the origin, but moves with panning movements, and is reset by a call to LookAt method"`
 	UpDir         mat32.Vec3     `desc:"up direction for camera -- which way is up -- defaults to positive Y axis, and is reset by call to LookAt method"`
-	Ortho         bool           `desc:"default is a Perspective camera -- set this to make it Orthographic instead, in which case the view includes the volume specified by the Near - Far distance (i.e., you probably want to decrease Far)."`
+	Ortho         bool           `desc:"default is a Perspective camera -- set this to make it Orthographic instead -- the view size is derived from FOV at the distance to the Target, so toggling at runtime preserves the apparent size of objects at the target"`
 	FOV           float32        `desc:"field of view in degrees "`
 	Aspect        float32        `desc:"aspect ratio (width/height)"`
-	Near          float32        `desc:"near plane z coordinate"`
-	Far           float32        `desc:"far plane z coordinate"`
+	Near          float32        `desc:"near plane z coordinate -- must be > 0 -- see SetNearFar"`
+	Far           float32        `desc:"far plane z coordinate -- must be > Near -- see SetNearFar"`
+	ViewModelNear float32        `desc:"separate, tighter near plane z coordinate used for solids in the LayerViewModel render layer (first-person weapons / tools), so they never clip through the main Near plane -- default .001 -- <= 0 falls back on Near"`
+	ZoomSpeed     float32        `desc:"multiplier on the scroll-wheel / pinch zoom rate -- default 1 -- larger is faster"`
+	MinDist       float32        `desc:"minimum distance from camera to Target allowed by Zoom / ZoomTo -- 0 = no limit"`
+	MaxDist       float32        `desc:"maximum distance from camera to Target allowed by Zoom / ZoomTo -- 0 = no limit"`
 	ViewMatrix    mat32.Mat4     `view:"-" desc:"view matrix (inverse of the Pose.Matrix)"`
 	PrjnMatrix    mat32.Mat4     `view:"-" desc:"projection matrix, defining the camera perspective / ortho transform"`
 	InvPrjnMatrix mat32.Mat4     `view:"-" desc:"inverse of the projection matrix"`
+	VMPrjnMatrix  mat32.Mat4     `view:"-" desc:"projection matrix for the LayerViewModel render layer, using ViewModelNear instead of Near"`
 	Frustum       *mat32.Frustum `view:"-" desc:"frustum of projection -- viewable space defined by 6 planes of a pyrammidal shape"`
 }
 
@@ -30,9 +39,11 @@ var KiT_Camera = kit.Types.AddType(&Camera{}, CameraProps)
 
 func (cm *Camera) Defaults() {
 	cm.FOV = 30
+	cm.ZoomSpeed = 1
 	cm.Aspect = 1.5
 	cm.Near = .01
 	cm.Far = 1000
+	cm.ViewModelNear = .001
 	cm.DefaultPose()
 }
 
@@ -49,16 +60,63 @@ func (cm *Camera) GenGoSet(path string) string {
 	return cm.Pose.GenGoSet(path+".Pose") + "; " + cm.Target.GenGoSet(path+".Target") + "; " + cm.UpDir.GenGoSet(path+".UpDir")
 }
 
+// SetNearFar sets the near and far clipping plane z coordinates, with
+// validation: near must be > 0 and far must be > near.
+// Logs and returns error if invalid, leaving current values unchanged.
+func (cm *Camera) SetNearFar(near, far float32) error {
+	if near <= 0 {
+		err := fmt.Errorf("gi3d.Camera SetNearFar: near must be > 0, got: %v", near)
+		log.Println(err)
+		return err
+	}
+	if far <= near {
+		err := fmt.Errorf("gi3d.Camera SetNearFar: far (%v) must be > near (%v)", far, near)
+		log.Println(err)
+		return err
+	}
+	cm.Near = near
+	cm.Far = far
+	return nil
+}
+
+// ValidNearFar ensures that the Near and Far clipping planes are valid
+// (near > 0, far > near), resetting to usable values and logging if not
+// (e.g., if the fields were set directly, bypassing SetNearFar).
+func (cm *Camera) ValidNearFar() {
+	if cm.Near <= 0 {
+		log.Printf("gi3d.Camera: Near clip plane must be > 0, was: %v -- reset to .01\n", cm.Near)
+		cm.Near = .01
+	}
+	if cm.Far <= cm.Near {
+		log.Printf("gi3d.Camera: Far clip plane must be > Near, was: %v -- reset to Near + 1000\n", cm.Far)
+		cm.Far = cm.Near + 1000
+	}
+}
+
 // UpdateMatrix updates the view and prjn matricies
 func (cm *Camera) UpdateMatrix() {
+	cm.ValidNearFar()
 	cm.Pose.UpdateMatrix()
 	cm.ViewMatrix.SetInverse(&cm.Pose.Matrix)
 	if cm.Ortho {
-		height := 2 * cm.Far * mat32.Tan(mat32.DegToRad(cm.FOV*0.5))
+		// derive view size from FOV at the distance to the target, so that
+		// toggling between ortho and perspective keeps the same apparent
+		// size for objects at the target
+		dist := cm.ViewVector().Length()
+		if dist == 0 {
+			dist = cm.Far
+		}
+		height := 2 * dist * mat32.Tan(mat32.DegToRad(cm.FOV*0.5))
 		width := cm.Aspect * height
 		cm.PrjnMatrix.SetOrthographic(width, height, cm.Near, cm.Far)
+		cm.VMPrjnMatrix = cm.PrjnMatrix // near plane is not an issue for ortho
 	} else {
 		cm.PrjnMatrix.SetPerspective(cm.FOV, cm.Aspect, cm.Near, cm.Far)
+		vmn := cm.ViewModelNear
+		if vmn <= 0 || vmn >= cm.Far {
+			vmn = cm.Near
+		}
+		cm.VMPrjnMatrix.SetPerspective(cm.FOV, cm.Aspect, vmn, cm.Far)
 	}
 	cm.InvPrjnMatrix.SetInverse(&cm.PrjnMatrix)
 	var proj mat32.Mat4
@@ -66,6 +124,42 @@ func (cm *Camera) UpdateMatrix() {
 	cm.Frustum = mat32.NewFrustumFromMatrix(&proj)
 }
 
+// ViewProjMatrix returns the full view-projection matrix (PrjnMatrix *
+// ViewMatrix), which maps world coordinates to clip coordinates.  The
+// ViewMatrix, PrjnMatrix and InvPrjnMatrix fields are directly available
+// for the individual matrices -- all reflect the current ortho / perspective
+// and aspect state as of the last UpdateMatrix call (call UpdateMatrix first
+// if the camera has changed).
+func (cm *Camera) ViewProjMatrix() mat32.Mat4 {
+	var vpm mat32.Mat4
+	vpm.MulMatrices(&cm.PrjnMatrix, &cm.ViewMatrix)
+	return vpm
+}
+
+// Unproject transforms given point in normalized display coordinates (NDC)
+// (X, Y in -1..1, Z = -1 at the near plane, +1 at the far plane) back into
+// world coordinates, using the current inverse projection and camera pose
+// matrices -- the inverse of projecting through ViewProjMatrix.
+// Call UpdateMatrix first if the camera has changed.
+func (cm *Camera) Unproject(ndc mat32.Vec3) mat32.Vec3 {
+	cpos := ndc.MulProjection(&cm.InvPrjnMatrix) // camera coords, w/ persp divide
+	return cpos.MulMat4(&cm.Pose.Matrix)
+}
+
+// NDCToWorldRay returns the ray in world coordinates corresponding to given
+// X, Y point in normalized display coordinates (-1..1, Y up) -- origin is on
+// the near plane and dir is the normalized direction into the scene.  This
+// works for both perspective and orthographic cameras (for ortho, the rays
+// are parallel), and is the building block for picking and other custom
+// screen-to-world interactions.  Call UpdateMatrix first if the camera has
+// changed.
+func (cm *Camera) NDCToWorldRay(x, y float32) (origin, dir mat32.Vec3) {
+	origin = cm.Unproject(mat32.Vec3{x, y, -1})
+	far := cm.Unproject(mat32.Vec3{x, y, 1})
+	dir = far.Sub(origin).Normal()
+	return
+}
+
 // LookAt points the camera at given target location, using given up direction,
 // and sets the Target, UpDir fields for future camera movements.
 func (cm *Camera) LookAt(target, upDir mat32.Vec3) {
@@ -171,18 +265,87 @@ func (cm *Camera) PanTarget(delX, delY, delZ float32) {
 }
 
 // Zoom moves along axis given pct closer or further from the target
-// it always moves the target back also if it distance is < 1
+// it always moves the target back also if it distance is < 1.
+// The pct is multiplied by ZoomSpeed, and the final distance to the target
+// is clamped per MinDist / MaxDist.
 func (cm *Camera) Zoom(zoomPct float32) {
 	ctaxis := cm.ViewVector()
 	if ctaxis.IsNil() {
 		ctaxis.Set(0, 0, 1)
 	}
 	dist := ctaxis.Length()
-	del := ctaxis.MulScalar(zoomPct)
+	del := ctaxis.MulScalar(zoomPct * cm.ZoomSpeedDefault())
 	cm.Pose.Pos.SetAdd(del)
 	if zoomPct < 0 && dist < 1 {
 		cm.Target.SetAdd(del)
 	}
+	cm.ClampDist()
+}
+
+// ZoomTo moves the camera along the ray that goes from the camera through
+// the given point (in scene-relative coordinates, with given scene size),
+// by given pct of the current distance to the target, so that repeated
+// scrolling dollies the camera toward what is under the cursor instead of
+// just along the current view axis.  The target moves with the lateral
+// component of the motion, so subsequent orbiting remains centered on the
+// zoomed-to region.  The pct is multiplied by ZoomSpeed, and the final
+// distance to the target is clamped per MinDist / MaxDist.
+func (cm *Camera) ZoomTo(pt, size image.Point, zoomPct float32) {
+	fsize := mat32.Vec2{float32(size.X), float32(size.Y)}
+	fpos := mat32.Vec2{float32(pt.X), float32(pt.Y)}
+	ndc := fpos.WindowToNDC(fsize, mat32.Vec2{}, true) // flipY
+	ndc.Z = -1                                         // at closest point
+	cdir := mat32.NewVec4FromVec3(ndc, 1).MulMat4(&cm.InvPrjnMatrix)
+	cdir.Z = -1
+	cdir.W = 0 // vec
+	wdir := cdir.MulMat4(&cm.Pose.Matrix)
+	dir := mat32.Vec3{wdir.X, wdir.Y, wdir.Z}.Normal()
+	ctaxis := cm.ViewVector()
+	if ctaxis.IsNil() {
+		ctaxis.Set(0, 0, 1)
+	}
+	dist := ctaxis.Length()
+	del := dir.MulScalar(-zoomPct * cm.ZoomSpeedDefault() * dist)
+	cm.Pose.Pos.SetAdd(del)
+	// move the target by the component of the motion orthogonal to the view
+	// axis, so the zoom converges on the cursor point
+	vn := ctaxis.Normal()
+	lat := del.Sub(vn.MulScalar(del.Dot(vn)))
+	cm.Target.SetAdd(lat)
+	cm.ClampDist()
+}
+
+// ZoomSpeedDefault returns ZoomSpeed, defaulting to 1 if not yet set
+// (e.g., for a zero-initialized camera prior to Defaults).
+func (cm *Camera) ZoomSpeedDefault() float32 {
+	if cm.ZoomSpeed <= 0 {
+		return 1
+	}
+	return cm.ZoomSpeed
+}
+
+// ClampDist enforces the MinDist / MaxDist limits on the distance between
+// the camera position and the target, moving the camera along the view
+// vector as needed -- no-op for limits that are 0.
+func (cm *Camera) ClampDist() {
+	if cm.MinDist <= 0 && cm.MaxDist <= 0 {
+		return
+	}
+	ctaxis := cm.ViewVector()
+	if ctaxis.IsNil() {
+		return
+	}
+	dist := ctaxis.Length()
+	clmp := dist
+	if cm.MinDist > 0 && clmp < cm.MinDist {
+		clmp = cm.MinDist
+	}
+	if cm.MaxDist > 0 && clmp > cm.MaxDist {
+		clmp = cm.MaxDist
+	}
+	if clmp != dist {
+		cm.Pose.Pos = cm.Target.Add(ctaxis.MulScalar(clmp / dist))
+	}
 }
 
 // CameraProps define the ToolBar and MenuBar for StructView
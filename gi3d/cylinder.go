@@ -44,6 +44,8 @@ func AddNewCone(sc *Scene, name string, height, radius float32, radialSegs, heig
 // AddNewCylinder creates a cylinder mesh with the specified radius, height,
 // number of radial segments, number of height segments,
 // and presence of a top and/or bottom cap.
+// top and bottom indicate whether to render the respective end cap disc:
+// pass false for both to create an open-ended tube.
 // Height is along the Y axis.
 func AddNewCylinder(sc *Scene, name string, height, radius float32, radialSegs, heightSegs int, top, bottom bool) *Cylinder {
 	return AddNewCylinderSector(sc, name, height, radius, radius, radialSegs, heightSegs, 0, 360, top, bottom)
@@ -228,122 +230,47 @@ func (ms *MeshBase) AddCylinderSector(height, topRad, botRad float32, radialSegs
 		}
 	}
 
-	// Top cap
+	// Top cap: a flat disc with outward +Y normal and radial UVs
+	// (center = .5, .5, rim on the unit circle), sharing the rim vertex
+	// positions with the side wall but not its normals / UVs.
 	if top && topRad > 0 {
-		// Array of vertex indicesOrig to build used to build the faces.
-		idxsOrig := []uint32{}
-		nextidx := pos.Size() / 3
-
-		// Appends top segments vtxs and builds array of its idxsOrig
-		var uv1, uv2, uv3 mat32.Vec2
-		for x := 0; x < radialSegs; x++ {
-			uv1 = uvsOrig[0][x]
-			uv2 = uvsOrig[0][x+1]
-			uv3 = mat32.Vec2{uv2.X, 0}
-			// Appends CENTER with its own UV.
-			pos.Append(0, hHt, 0)
-			norms.Append(0, 1, 0)
-			uvs.AppendVec2(uv3)
-			idxsOrig = append(idxsOrig, uint32(nextidx))
-			nextidx++
-			// Appends vertex
-			v := mat32.Vec3{}
-			vi := vtxs[0][x]
-			pos.GetVec3(3*vi, &v)
+		ctr := mat32.Vec3{0, hHt, 0}
+		ctr.SetAdd(offset)
+		ctridx := pos.Size() / 3
+		pos.AppendVec3(ctr)
+		norms.Append(0, 1, 0)
+		uvs.Append(.5, .5)
+		rimst := pos.Size() / 3
+		var v mat32.Vec3
+		for x := 0; x <= radialSegs; x++ {
+			pos.GetVec3(3*vtxs[0][x], &v)
 			pos.AppendVec3(v)
 			norms.Append(0, 1, 0)
-			uvs.AppendVec2(uv1)
-			idxsOrig = append(idxsOrig, uint32(nextidx))
-			nextidx++
+			uvs.Append(.5+(v.X-offset.X)/(2*topRad), .5-(v.Z-offset.Z)/(2*topRad))
 		}
-		// Appends copy of first vertex (center)
-		var pt, norm mat32.Vec3
-		var uv mat32.Vec2
-		pos.GetVec3(3*int(idxsOrig[0]), &pt)
-		norms.GetVec3(3*int(idxsOrig[0]), &norm)
-		uvs.GetVec2(2*int(idxsOrig[0]), &uv)
-		pos.AppendVec3(pt)
-		norms.AppendVec3(norm)
-		uvs.AppendVec2(uv)
-		idxsOrig = append(idxsOrig, uint32(nextidx))
-		nextidx++
-
-		// Appends copy of second vertex (v1) USING LAST UV2
-		pos.GetVec3(3*int(idxsOrig[1]), &pt)
-		norms.GetVec3(3*int(idxsOrig[1]), &norm)
-		pos.AppendVec3(pt)
-		norms.AppendVec3(norm)
-		uvs.AppendVec2(uv2)
-		idxsOrig = append(idxsOrig, uint32(nextidx))
-		nextidx++
-
-		// Append faces idxsOrig
 		for x := 0; x < radialSegs; x++ {
-			pos := 2 * x
-			i1 := idxsOrig[pos]
-			i2 := idxsOrig[pos+1]
-			i3 := idxsOrig[pos+3]
-			idxs.Append(uint32(stidx+i1), uint32(stidx+i2), uint32(stidx+i3))
+			idxs.Append(stidx+uint32(ctridx), stidx+uint32(rimst+x), stidx+uint32(rimst+x+1))
 		}
 	}
 
-	// Bottom cap
+	// Bottom cap: a flat disc with outward -Y normal and radial UVs
 	if bottom && botRad > 0 {
-		// Array of vertex idxsOrig to build used to build the faces.
-		idxsOrig := []uint32{}
-		nextidx := pos.Size() / 3
-
-		// Appends top segments vtxs and builds array of its idxsOrig
-		var uv1, uv2, uv3 mat32.Vec2
-		for x := 0; x < radialSegs; x++ {
-			uv1 = uvsOrig[heightSegs][x]
-			uv2 = uvsOrig[heightSegs][x+1]
-			uv3 = mat32.Vec2{uv2.X, 1}
-			// Appends CENTER with its own UV.
-			pos.Append(0, -hHt, 0)
-			norms.Append(0, -1, 0)
-			uvs.AppendVec2(uv3)
-			idxsOrig = append(idxsOrig, uint32(nextidx))
-			nextidx++
-			// Appends vertex
-			v := mat32.Vec3{}
-			vi := vtxs[heightSegs][x]
-			pos.GetVec3(3*vi, &v)
+		ctr := mat32.Vec3{0, -hHt, 0}
+		ctr.SetAdd(offset)
+		ctridx := pos.Size() / 3
+		pos.AppendVec3(ctr)
+		norms.Append(0, -1, 0)
+		uvs.Append(.5, .5)
+		rimst := pos.Size() / 3
+		var v mat32.Vec3
+		for x := 0; x <= radialSegs; x++ {
+			pos.GetVec3(3*vtxs[heightSegs][x], &v)
 			pos.AppendVec3(v)
 			norms.Append(0, -1, 0)
-			uvs.AppendVec2(uv1)
-			idxsOrig = append(idxsOrig, uint32(nextidx))
-			nextidx++
+			uvs.Append(.5+(v.X-offset.X)/(2*botRad), .5+(v.Z-offset.Z)/(2*botRad))
 		}
-
-		// Appends copy of first vertex (center)
-		var pt, norm mat32.Vec3
-		var uv mat32.Vec2
-		pos.GetVec3(3*int(idxsOrig[0]), &pt)
-		norms.GetVec3(3*int(idxsOrig[0]), &norm)
-		uvs.GetVec2(2*int(idxsOrig[0]), &uv)
-		pos.AppendVec3(pt)
-		norms.AppendVec3(norm)
-		uvs.AppendVec2(uv)
-		idxsOrig = append(idxsOrig, uint32(nextidx))
-		nextidx++
-
-		// Appends copy of second vertex (v1) USING LAST UV2
-		pos.GetVec3(3*int(idxsOrig[1]), &pt)
-		norms.GetVec3(3*int(idxsOrig[1]), &norm)
-		pos.AppendVec3(pt)
-		norms.AppendVec3(norm)
-		uvs.AppendVec2(uv2)
-		idxsOrig = append(idxsOrig, uint32(nextidx))
-		nextidx++
-
-		// Appends faces idxsOrig
 		for x := 0; x < radialSegs; x++ {
-			pos := 2 * x
-			i1 := idxsOrig[pos]
-			i2 := idxsOrig[pos+3]
-			i3 := idxsOrig[pos+1]
-			idxs.Append(uint32(stidx+i1), uint32(stidx+i2), uint32(stidx+i3))
+			idxs.Append(stidx+uint32(ctridx), stidx+uint32(rimst+x+1), stidx+uint32(rimst+x))
 		}
 	}
 
@@ -0,0 +1,70 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"testing"
+
+	"github.com/goki/gi/mat32"
+)
+
+// TestCylinderOpenTube tests an open-ended tube (no caps): only the side
+// wall vertices should be generated.
+func TestCylinderOpenTube(t *testing.T) {
+	radialSegs := 8
+	heightSegs := 2
+	ms := &MeshBase{}
+	ms.Reset()
+	ms.AddCylinderSector(2, 1, 1, radialSegs, heightSegs, 0, 360, false, false, mat32.Vec3{})
+
+	nv := (heightSegs + 1) * (radialSegs + 1)
+	if ms.Vtx.Len() != 3*nv {
+		t.Errorf("open tube Vtx.Len() = %v, want %v", ms.Vtx.Len(), 3*nv)
+	}
+	if ms.Norm.Len() != ms.Vtx.Len() {
+		t.Errorf("open tube Norm.Len() = %v != Vtx.Len() = %v", ms.Norm.Len(), ms.Vtx.Len())
+	}
+	if ms.Tex.Len() != 2*nv {
+		t.Errorf("open tube Tex.Len() = %v, want %v", ms.Tex.Len(), 2*nv)
+	}
+	nidx := 6 * radialSegs * heightSegs
+	if ms.Idx.Len() != nidx {
+		t.Errorf("open tube Idx.Len() = %v, want %v", ms.Idx.Len(), nidx)
+	}
+}
+
+// TestConeCapped tests a cone (topRad = 0) with a bottom cap: the cap adds
+// a center vertex plus a rim ring, with outward -Y normals.
+func TestConeCapped(t *testing.T) {
+	radialSegs := 8
+	heightSegs := 2
+	ms := &MeshBase{}
+	ms.Reset()
+	ms.AddCylinderSector(2, 0, 1, radialSegs, heightSegs, 0, 360, false, true, mat32.Vec3{})
+
+	nwall := (heightSegs + 1) * (radialSegs + 1)
+	ncap := 1 + (radialSegs + 1) // center + rim ring
+	nv := nwall + ncap
+	if ms.Vtx.Len() != 3*nv {
+		t.Errorf("capped cone Vtx.Len() = %v, want %v", ms.Vtx.Len(), 3*nv)
+	}
+	if ms.Norm.Len() != ms.Vtx.Len() {
+		t.Errorf("capped cone Norm.Len() = %v != Vtx.Len() = %v", ms.Norm.Len(), ms.Vtx.Len())
+	}
+	if ms.Tex.Len() != 2*nv {
+		t.Errorf("capped cone Tex.Len() = %v, want %v", ms.Tex.Len(), 2*nv)
+	}
+	nidx := 6*radialSegs*heightSegs + 3*radialSegs
+	if ms.Idx.Len() != nidx {
+		t.Errorf("capped cone Idx.Len() = %v, want %v", ms.Idx.Len(), nidx)
+	}
+	var norm mat32.Vec3
+	for i := nwall; i < nv; i++ {
+		ms.Norm.GetVec3(3*i, &norm)
+		if norm != (mat32.Vec3{0, -1, 0}) {
+			t.Errorf("capped cone cap normal at %v = %v, want {0 -1 0}", i, norm)
+		}
+	}
+}
@@ -5,12 +5,12 @@
 package gi3d
 
 import (
-	"fmt"
 	"image"
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/mat32"
 	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
 	"github.com/goki/gi/oswin/mouse"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
@@ -23,7 +23,7 @@ import (
 // top of that by setting the Pose.Scale values as usual.
 type Embed2D struct {
 	Solid
-	Viewport *gi.Viewport2D `desc:"the viewport to display"`
+	Viewport *EmbedViewport `desc:"the viewport to display"`
 	Tex      *TextureBase   `view:"-" xml:"-" json:"-" desc:"texture object -- this is used directly instead of pointing to the Scene Texture resources"`
 }
 
@@ -33,8 +33,7 @@ var KiT_Embed2D = kit.Types.AddType(&Embed2D{}, Embed2DProps)
 func AddNewEmbed2D(sc *Scene, parent ki.Ki, name string, width, height int) *Embed2D {
 	em := parent.AddNewChild(KiT_Embed2D, name).(*Embed2D)
 	em.Defaults(sc)
-	em.Viewport = gi.NewViewport2D(width, height)
-	em.Viewport.InitName(em.Viewport, name)
+	em.Viewport = NewEmbedViewport(sc, em, name, width, height)
 	return em
 }
 
@@ -155,36 +154,171 @@ func (em *Embed2D) Project2D(sc *Scene, pt image.Point) (image.Point, bool) {
 	}
 	ppt.X = int((ispt.X + 0.5) * float32(sz.X))
 	ppt.Y = int((ispt.Y + 0.5) * float32(sz.Y))
-	fmt.Printf("ispt: %v   ppt: %v\n", ispt, ppt)
-	if ppt.X < 0 || ppt.Y < 0 {
+	if ppt.X < 0 || ppt.Y < 0 || ppt.X >= sz.X || ppt.Y >= sz.Y {
 		return ppt, false
 	}
 	return ppt, true
 }
 
+// DispatchEvent sends given event, with coordinates already projected into
+// the embedded viewport, through the viewport's own event manager, so the
+// embedded widgets respond to it (hover, press, focus, etc).
+func (em *Embed2D) DispatchEvent(sc *Scene, evi oswin.Event) {
+	vp := em.Viewport
+	vp.EventMgr.MouseEvents(evi)
+	vp.EventMgr.SendEventSignal(evi, true)
+	if mve, ok := evi.(*mouse.MoveEvent); ok {
+		vp.EventMgr.GenMouseFocusEvents(mve, true)
+	}
+	vp.EventMgr.MouseEventReset(evi)
+	em.UpdateView(sc)
+}
+
+// UpdateView re-renders the embedded viewport to the texture and triggers
+// an update of the containing scene, to reflect any changes arising from
+// dispatched events.
+func (em *Embed2D) UpdateView(sc *Scene) {
+	em.RenderView(sc)
+	sc.UpdateSig()
+}
+
 func (em *Embed2D) ConnectEvents3D(sc *Scene) {
 	em.ConnectEvent(sc.Win, oswin.MouseEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
-		if !sc.IsVisible() {
+		emm := recv.Embed(KiT_Embed2D).(*Embed2D)
+		if !sc.IsVisible() || emm.Viewport == nil {
 			return
 		}
 		me := d.(*mouse.Event)
-		emm := recv.Embed(KiT_Embed2D).(*Embed2D)
 		ppt, ok := emm.Project2D(sc, me.Where)
 		if !ok {
 			return
 		}
-		_ = ppt
+		md := &mouse.Event{}
+		*md = *me
+		md.Where = ppt
+		emm.DispatchEvent(sc, md)
+		if md.IsProcessed() {
+			me.SetProcessed()
+		}
 	})
-	em.ConnectEvent(sc.Win, oswin.MouseHoverEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
-		if !sc.IsVisible() {
+	em.ConnectEvent(sc.Win, oswin.MouseMoveEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		emm := recv.Embed(KiT_Embed2D).(*Embed2D)
+		if !sc.IsVisible() || emm.Viewport == nil {
 			return
 		}
-		me := d.(*mouse.HoverEvent)
+		me := d.(*mouse.MoveEvent)
+		ppt, ok := emm.Project2D(sc, me.Where)
+		if !ok {
+			return
+		}
+		md := &mouse.MoveEvent{}
+		*md = *me
+		del := ppt.Sub(me.Where)
+		md.Where = ppt
+		md.From = me.From.Add(del)
+		emm.DispatchEvent(sc, md)
+		if md.IsProcessed() {
+			me.SetProcessed()
+		}
+	})
+	em.ConnectEvent(sc.Win, oswin.MouseDragEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
 		emm := recv.Embed(KiT_Embed2D).(*Embed2D)
+		if !sc.IsVisible() || emm.Viewport == nil {
+			return
+		}
+		me := d.(*mouse.DragEvent)
 		ppt, ok := emm.Project2D(sc, me.Where)
 		if !ok {
 			return
 		}
-		_ = ppt
+		md := &mouse.DragEvent{}
+		*md = *me
+		del := ppt.Sub(me.Where)
+		md.Where = ppt
+		md.From = me.From.Add(del)
+		emm.DispatchEvent(sc, md)
+		if md.IsProcessed() {
+			me.SetProcessed()
+		}
+	})
+	em.ConnectEvent(sc.Win, oswin.KeyChordEvent, gi.RegPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		emm := recv.Embed(KiT_Embed2D).(*Embed2D)
+		if !sc.IsVisible() || emm.Viewport == nil {
+			return
+		}
+		ke := d.(*key.ChordEvent)
+		kd := &key.ChordEvent{}
+		*kd = *ke
+		emm.DispatchEvent(sc, kd)
+		if kd.IsProcessed() {
+			ke.SetProcessed()
+		}
 	})
 }
+
+///////////////////////////////////////////////////////////////////
+//  EmbedViewport
+
+// EmbedViewport is a gi.Viewport2D that serves as the 2D scenegraph for an
+// Embed2D -- it has its own event manager, so events projected from the 3D
+// scene are dispatched to the embedded widgets.
+type EmbedViewport struct {
+	gi.Viewport2D
+	Sc       *Scene      `json:"-" xml:"-" desc:"parent scene -- for triggering updates"`
+	EmbedPar *Embed2D    `json:"-" xml:"-" desc:"parent Embed2D -- for rendering updates"`
+	EventMgr gi.EventMgr `json:"-" xml:"-" desc:"event manager that handles dispersing events to nodes"`
+}
+
+var KiT_EmbedViewport = kit.Types.AddType(&EmbedViewport{}, nil)
+
+// NewEmbedViewport creates a new EmbedViewport with the given size, for
+// given scene and parent Embed2D
+func NewEmbedViewport(sc *Scene, em *Embed2D, name string, width, height int) *EmbedViewport {
+	sz := image.Point{width, height}
+	vp := &EmbedViewport{}
+	vp.Geom = gi.Geom2DInt{Size: sz}
+	vp.Pixels = image.NewRGBA(image.Rectangle{Max: sz})
+	vp.Render.Init(width, height, vp.Pixels)
+	vp.InitName(vp, name)
+	vp.Sc = sc
+	vp.EmbedPar = em
+	vp.EventMgr.Master = vp
+	return vp
+}
+
+func (vp *EmbedViewport) VpTop() gi.Viewport {
+	return vp.This().(gi.Viewport)
+}
+
+func (vp *EmbedViewport) VpTopNode() gi.Node {
+	return vp.This().(gi.Node)
+}
+
+func (vp *EmbedViewport) VpEventMgr() *gi.EventMgr {
+	return &vp.EventMgr
+}
+
+///////////////////////////////////////////////////////////////////
+//  EventMaster API
+
+func (vp *EmbedViewport) EventTopNode() ki.Ki {
+	return vp.This()
+}
+
+func (vp *EmbedViewport) IsInScope(node *gi.Node2DBase, popup bool) bool {
+	return true // no popups within embedded viewport, so everything is in scope
+}
+
+func (vp *EmbedViewport) CurPopupIsTooltip() bool {
+	return false
+}
+
+func (vp *EmbedViewport) DeleteTooltip() {
+}
+
+func (vp *EmbedViewport) IsFocusActive() bool {
+	return true
+}
+
+func (vp *EmbedViewport) SetFocusActiveState(active bool) {
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goki/gi/mat32"
+)
+
+// Environment is an equirectangular (latitude-longitude) environment map
+// used for image-based lighting (IBL): instead of a uniform AmbientLight,
+// ambient diffuse lighting varies with surface orientation according to the
+// surrounding environment, which is what makes materials sit believably in
+// a scene.  Load one with Scene.SetEnvironment, typically from a Radiance
+// .hdr file (see DecodeHDR) so bright sources (sun, windows) carry their
+// full dynamic range -- regular LDR images also work, converted from sRGB
+// to linear.  The diffuse irradiance is precomputed as a 9-coefficient
+// spherical-harmonic projection (CalcSH), following Ramamoorthi & Hanrahan
+// (2001), which the standard shaders evaluate per-fragment at negligible
+// cost.  Prefiltered specular reflection maps additionally require cubemap
+// and float-texture support in the gpu layer, and are not yet implemented.
+type Environment struct {
+	File   string        `desc:"file the environment was loaded from"`
+	Width  int           `desc:"width of the equirectangular image (typically 2 * Height)"`
+	Height int           `desc:"height of the equirectangular image"`
+	Pix    []float32     `view:"-" desc:"linear RGB radiance values, 3 floats per pixel, row-major with the first row at the top (+Y pole)"`
+	SH     [9]mat32.Vec3 `view:"-" desc:"spherical-harmonic projection of the environment radiance: 3 bands, 9 RGB coefficients, in the order L00, L1-1, L10, L11, L2-2, L2-1, L20, L21, L22 -- computed by CalcSH"`
+	Irrad  [3]mat32.Mat4 `view:"-" desc:"world-space irradiance matrices, one per color channel: diffuse light for normal n is dot(n1, M * n1) with n1 = (n, 1) -- computed by CalcSH from SH"`
+}
+
+// OpenEnvironment opens an equirectangular environment map from given file
+// and precomputes its irradiance (CalcSH).  Radiance .hdr / .pic files are
+// decoded at full dynamic range; any other supported image format is
+// converted from sRGB to linear.
+func OpenEnvironment(fname string) (*Environment, error) {
+	file, err := os.Open(fname)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	defer file.Close()
+	env := &Environment{File: fname}
+	ext := strings.ToLower(filepath.Ext(fname))
+	if ext == ".hdr" || ext == ".pic" {
+		env.Width, env.Height, env.Pix, err = DecodeHDR(file)
+	} else {
+		var img image.Image
+		img, _, err = image.Decode(file)
+		if err == nil {
+			env.SetFromImage(img)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("gi3d.OpenEnvironment: %v: %v", fname, err)
+		log.Println(err)
+		return nil, err
+	}
+	env.CalcSH()
+	return env, nil
+}
+
+// SetFromImage sets the environment pixels from given standard (LDR) image,
+// converting from sRGB to linear -- does not compute CalcSH.
+func (env *Environment) SetFromImage(img image.Image) {
+	sz := img.Bounds().Size()
+	env.Width = sz.X
+	env.Height = sz.Y
+	env.Pix = make([]float32, 3*sz.X*sz.Y)
+	idx := 0
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			env.Pix[idx] = srgbToLinear(float32(r) / 0xffff)
+			env.Pix[idx+1] = srgbToLinear(float32(g) / 0xffff)
+			env.Pix[idx+2] = srgbToLinear(float32(b) / 0xffff)
+			idx += 3
+		}
+	}
+}
+
+// srgbToLinear converts an sRGB-encoded color component in [0,1] to linear.
+func srgbToLinear(v float32) float32 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return mat32.Pow((v+0.055)/1.055, 2.4)
+}
+
+// PixAt returns the linear RGB radiance at given pixel coordinates,
+// which are clamped / wrapped to the image.
+func (env *Environment) PixAt(x, y int) mat32.Vec3 {
+	if y < 0 {
+		y = 0
+	} else if y >= env.Height {
+		y = env.Height - 1
+	}
+	x = ((x % env.Width) + env.Width) % env.Width // wraps in azimuth
+	idx := 3 * (y*env.Width + x)
+	return mat32.Vec3{env.Pix[idx], env.Pix[idx+1], env.Pix[idx+2]}
+}
+
+// Sample returns the bilinearly-interpolated radiance in given world
+// direction (need not be normalized).  The mapping puts the horizontal
+// center of the image at -Z (the default camera view direction), with the
+// top row at +Y.
+func (env *Environment) Sample(dir mat32.Vec3) mat32.Vec3 {
+	d := dir.Normal()
+	theta := mat32.Acos(mat32.Clamp(d.Y, -1, 1))
+	phi := mat32.Atan2(d.X, -d.Z)
+	fx := (phi/(2*mat32.Pi)+0.5)*float32(env.Width) - 0.5
+	fy := (theta/mat32.Pi)*float32(env.Height) - 0.5
+	x0 := mat32.Floor(fx)
+	y0 := mat32.Floor(fy)
+	dx := fx - x0
+	dy := fy - y0
+	c00 := env.PixAt(int(x0), int(y0))
+	c10 := env.PixAt(int(x0)+1, int(y0))
+	c01 := env.PixAt(int(x0), int(y0)+1)
+	c11 := env.PixAt(int(x0)+1, int(y0)+1)
+	top := c00.MulScalar(1 - dx).Add(c10.MulScalar(dx))
+	bot := c01.MulScalar(1 - dx).Add(c11.MulScalar(dx))
+	return top.MulScalar(1 - dy).Add(bot.MulScalar(dy))
+}
+
+// shDirAt returns the world direction and solid-angle weight for given
+// pixel in the equirectangular image, per the Sample mapping.
+func (env *Environment) shDirAt(x, y int) (dir mat32.Vec3, domega float32) {
+	theta := mat32.Pi * (float32(y) + 0.5) / float32(env.Height)
+	phi := 2*mat32.Pi*(float32(x)+0.5)/float32(env.Width) - mat32.Pi
+	st := mat32.Sin(theta)
+	dir = mat32.Vec3{st * mat32.Sin(phi), mat32.Cos(theta), -st * mat32.Cos(phi)}
+	domega = (2 * mat32.Pi / float32(env.Width)) * (mat32.Pi / float32(env.Height)) * st
+	return
+}
+
+// CalcSH computes the 9-coefficient spherical-harmonic projection of the
+// environment radiance (SH), and from it the per-channel irradiance
+// matrices (Irrad) with the cosine-lobe convolution constants from
+// Ramamoorthi & Hanrahan (2001), scaled by 1/Pi so they yield the diffuse
+// reflection factor directly.  Irradiance is very low frequency, so large
+// images are sampled at a stride for speed -- called automatically by
+// OpenEnvironment.
+func (env *Environment) CalcSH() {
+	for i := range env.SH {
+		env.SH[i] = mat32.Vec3{}
+	}
+	stride := env.Height / 256
+	if stride < 1 {
+		stride = 1
+	}
+	sw := float32(stride * stride)
+	for y := 0; y < env.Height; y += stride {
+		for x := 0; x < env.Width; x += stride {
+			dir, dom := env.shDirAt(x, y)
+			clr := env.PixAt(x, y).MulScalar(dom * sw)
+			px, py, pz := dir.X, dir.Y, dir.Z
+			env.SH[0].SetAdd(clr.MulScalar(0.282095))
+			env.SH[1].SetAdd(clr.MulScalar(0.488603 * py))
+			env.SH[2].SetAdd(clr.MulScalar(0.488603 * pz))
+			env.SH[3].SetAdd(clr.MulScalar(0.488603 * px))
+			env.SH[4].SetAdd(clr.MulScalar(1.092548 * px * py))
+			env.SH[5].SetAdd(clr.MulScalar(1.092548 * py * pz))
+			env.SH[6].SetAdd(clr.MulScalar(0.315392 * (3*pz*pz - 1)))
+			env.SH[7].SetAdd(clr.MulScalar(1.092548 * px * pz))
+			env.SH[8].SetAdd(clr.MulScalar(0.546274 * (px*px - py*py)))
+		}
+	}
+	const (
+		c1 = 0.429043
+		c2 = 0.511664
+		c3 = 0.743125
+		c4 = 0.886227
+		c5 = 0.247708
+	)
+	for c := 0; c < 3; c++ {
+		l := func(i int) float32 {
+			return env.SH[i].Dim(mat32.Dims(c))
+		}
+		env.Irrad[c].Set(
+			c1*l(8), c1*l(4), c1*l(7), c2*l(3),
+			c1*l(4), -c1*l(8), c1*l(5), c2*l(1),
+			c1*l(7), c1*l(5), c3*l(6), c2*l(2),
+			c2*l(3), c2*l(1), c2*l(2), c4*l(0)-c5*l(6))
+		env.Irrad[c].MulScalar(1 / mat32.Pi)
+	}
+}
+
+// Irradiance returns the diffuse reflection factor (irradiance / Pi) for a
+// surface with given world-space unit normal -- multiply by the material
+// diffuse color for the resulting ambient diffuse light -- CalcSH must have
+// been called.
+func (env *Environment) Irradiance(norm mat32.Vec3) mat32.Vec3 {
+	nh := mat32.Vec4{norm.X, norm.Y, norm.Z, 1}
+	var res mat32.Vec3
+	for c := 0; c < 3; c++ {
+		res.SetDim(mat32.Dims(c), nh.Dot(nh.MulMat4(&env.Irrad[c])))
+	}
+	return res
+}
+
+// IrradianceMats returns the irradiance matrices rotated into the frame of
+// a camera with given rotation, so they can be evaluated directly with
+// view-space normals in the shaders -- see Renderers.SetEnvIrrad.
+func (env *Environment) IrradianceMats(camQuat mat32.Quat) [3]mat32.Mat4 {
+	var rot mat32.Mat4
+	rot.SetRotationFromQuat(camQuat)
+	rt := rot.Transpose()
+	var mats [3]mat32.Mat4
+	for c := 0; c < 3; c++ {
+		var tmp mat32.Mat4
+		tmp.MulMatrices(&env.Irrad[c], &rot)
+		mats[c].MulMatrices(rt, &tmp)
+	}
+	return mats
+}
+
+// DecodeHDR decodes a Radiance RGBE (.hdr / .pic) high-dynamic-range image
+// from given reader, returning its size and linear RGB float values, 3 per
+// pixel, row-major from the top.  Both flat and run-length-encoded
+// scanlines are supported; only the standard -Y +X pixel order is.
+func DecodeHDR(r io.Reader) (width, height int, pix []float32, err error) {
+	br := bufio.NewReader(r)
+	line, err := br.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "#?") {
+		return 0, 0, nil, fmt.Errorf("not a Radiance HDR file")
+	}
+	for {
+		line, err = br.ReadString('\n')
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" { // blank line ends the header
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return 0, 0, nil, fmt.Errorf("unsupported HDR format: %v", line)
+		}
+	}
+	line, err = br.ReadString('\n')
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if _, err = fmt.Sscanf(line, "-Y %d +X %d", &height, &width); err != nil {
+		return 0, 0, nil, fmt.Errorf("unsupported HDR pixel order: %v", strings.TrimSpace(line))
+	}
+	pix = make([]float32, 3*width*height)
+	scan := make([]byte, 4*width) // rgbe components for one scanline
+	for y := 0; y < height; y++ {
+		var hdr [4]byte
+		if _, err = io.ReadFull(br, hdr[:]); err != nil {
+			return 0, 0, nil, err
+		}
+		if hdr[0] == 2 && hdr[1] == 2 && int(hdr[2])<<8|int(hdr[3]) == width && width >= 8 && width <= 0x7fff {
+			// adaptive RLE: each component of the scanline is run-length encoded separately
+			for c := 0; c < 4; c++ {
+				for x := 0; x < width; {
+					cnt, rerr := br.ReadByte()
+					if rerr != nil {
+						return 0, 0, nil, rerr
+					}
+					if cnt > 128 { // run of the next byte
+						val, rerr := br.ReadByte()
+						if rerr != nil {
+							return 0, 0, nil, rerr
+						}
+						n := int(cnt) - 128
+						if x+n > width {
+							return 0, 0, nil, fmt.Errorf("HDR scanline overrun")
+						}
+						for i := 0; i < n; i++ {
+							scan[4*x+c] = val
+							x++
+						}
+					} else { // literal bytes
+						n := int(cnt)
+						if n == 0 || x+n > width {
+							return 0, 0, nil, fmt.Errorf("HDR scanline overrun")
+						}
+						for i := 0; i < n; i++ {
+							val, rerr := br.ReadByte()
+							if rerr != nil {
+								return 0, 0, nil, rerr
+							}
+							scan[4*x+c] = val
+							x++
+						}
+					}
+				}
+			}
+		} else {
+			// flat (old-style) scanline: the 4 bytes already read are the first pixel
+			copy(scan[:4], hdr[:])
+			if _, err = io.ReadFull(br, scan[4:]); err != nil {
+				return 0, 0, nil, err
+			}
+		}
+		po := 3 * y * width
+		for x := 0; x < width; x++ {
+			e := scan[4*x+3]
+			if e == 0 {
+				continue // zeros from make
+			}
+			scale := mat32.Pow(2, float32(int(e)-136)) // 2^(e-128) / 256
+			pix[po+3*x] = float32(scan[4*x]) * scale
+			pix[po+3*x+1] = float32(scan[4*x+1]) * scale
+			pix[po+3*x+2] = float32(scan[4*x+2]) * scale
+		}
+	}
+	return width, height, pix, nil
+}
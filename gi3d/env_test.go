@@ -0,0 +1,74 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/goki/gi/mat32"
+)
+
+// a constant-radiance environment must produce that same constant as the
+// diffuse factor, in every direction (3-band SH is exact for a constant)
+func TestEnvConstantIrradiance(t *testing.T) {
+	env := &Environment{Width: 64, Height: 32}
+	env.Pix = make([]float32, 3*env.Width*env.Height)
+	for i := 0; i < len(env.Pix); i += 3 {
+		env.Pix[i] = 0.5
+		env.Pix[i+1] = 0.25
+		env.Pix[i+2] = 1.0
+	}
+	env.CalcSH()
+	dirs := []mat32.Vec3{{0, 1, 0}, {1, 0, 0}, {0, 0, -1}, {-1, -1, 1}}
+	for _, d := range dirs {
+		ir := env.Irradiance(d.Normal())
+		if mat32.Abs(ir.X-0.5) > .02 || mat32.Abs(ir.Y-0.25) > .02 || mat32.Abs(ir.Z-1.0) > .04 {
+			t.Errorf("constant env irradiance at %v: %v", d, ir)
+		}
+	}
+}
+
+func TestDecodeHDRFlat(t *testing.T) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 2 +X 4\n")
+	for i := 0; i < 8; i++ {
+		b.Write([]byte{128, 64, 32, 136}) // e=136 -> scale 1
+	}
+	w, h, pix, err := DecodeHDR(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != 4 || h != 2 || len(pix) != 3*4*2 {
+		t.Fatalf("size: %v x %v, %v pix", w, h, len(pix))
+	}
+	for i := 0; i < len(pix); i += 3 {
+		if pix[i] != 128 || pix[i+1] != 64 || pix[i+2] != 32 {
+			t.Errorf("pixel %v: %v %v %v", i/3, pix[i], pix[i+1], pix[i+2])
+		}
+	}
+}
+
+func TestDecodeHDRRLE(t *testing.T) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 1 +X 8\n")
+	b.Write([]byte{2, 2, 0, 8}) // adaptive RLE scanline header
+	for _, val := range []byte{10, 20, 30, 136} {
+		b.Write([]byte{136, val}) // run of 8 per component
+	}
+	w, h, pix, err := DecodeHDR(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != 8 || h != 1 {
+		t.Fatalf("size: %v x %v", w, h)
+	}
+	for i := 0; i < len(pix); i += 3 {
+		if pix[i] != 10 || pix[i+1] != 20 || pix[i+2] != 30 {
+			t.Errorf("pixel %v: %v %v %v", i/3, pix[i], pix[i+1], pix[i+2])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"github.com/goki/gi/mat32"
+	"github.com/goki/gi/oswin/gpu"
+)
+
+// RenderFXAA implements a post-process FXAA (fast approximate anti-aliasing)
+// pass: the rendered scene texture is drawn through an FXAA fragment shader
+// onto a fullscreen quad, into a second framebuffer, which then becomes the
+// displayed texture.  This is cheaper than MSAA and works uniformly for the
+// offscreen-to-UI composite -- enable via Scene.FXAA.
+type RenderFXAA struct {
+	RenderBase
+	Quad gpu.BufferMgr `desc:"fullscreen quad (2 triangles) used to drive the fragment shader"`
+}
+
+func (rb *RenderFXAA) Init(rn *Renderers) error {
+	rb.Nm = "RenderFXAA"
+	if rb.Pipe == nil {
+		rb.Pipe = gpu.TheGPU.NewPipeline(rb.Nm)
+		rb.Pipe.AddProgram("VtxFrag")
+	}
+	pl := rb.Pipe
+	pr := pl.ProgramByName("VtxFrag")
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx",
+		`
+layout(location = 0) in vec3 VtxPos;
+out vec2 TexCoord;
+
+void main() {
+	TexCoord = VtxPos.xy * 0.5 + 0.5;
+	gl_Position = vec4(VtxPos.xy, 0.0, 1.0);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.AddShader(gpu.FragmentShader, "Frag",
+		`
+uniform sampler2D Tex;
+uniform vec2 InvSize;
+in vec2 TexCoord;
+out vec4 outputColor;
+
+const float FXAA_REDUCE_MIN = 1.0 / 128.0;
+const float FXAA_REDUCE_MUL = 1.0 / 8.0;
+const float FXAA_SPAN_MAX = 8.0;
+
+float luma(vec3 c) {
+	return dot(c, vec3(0.299, 0.587, 0.114));
+}
+
+void main() {
+	vec3 rgbNW = texture(Tex, TexCoord + vec2(-1.0, -1.0) * InvSize).rgb;
+	vec3 rgbNE = texture(Tex, TexCoord + vec2(1.0, -1.0) * InvSize).rgb;
+	vec3 rgbSW = texture(Tex, TexCoord + vec2(-1.0, 1.0) * InvSize).rgb;
+	vec3 rgbSE = texture(Tex, TexCoord + vec2(1.0, 1.0) * InvSize).rgb;
+	vec3 rgbM = texture(Tex, TexCoord).rgb;
+	float lumaNW = luma(rgbNW);
+	float lumaNE = luma(rgbNE);
+	float lumaSW = luma(rgbSW);
+	float lumaSE = luma(rgbSE);
+	float lumaM = luma(rgbM);
+	float lumaMin = min(lumaM, min(min(lumaNW, lumaNE), min(lumaSW, lumaSE)));
+	float lumaMax = max(lumaM, max(max(lumaNW, lumaNE), max(lumaSW, lumaSE)));
+
+	vec2 dir;
+	dir.x = -((lumaNW + lumaNE) - (lumaSW + lumaSE));
+	dir.y = ((lumaNW + lumaSW) - (lumaNE + lumaSE));
+
+	float dirReduce = max((lumaNW + lumaNE + lumaSW + lumaSE) * 0.25 * FXAA_REDUCE_MUL, FXAA_REDUCE_MIN);
+	float rcpDirMin = 1.0 / (min(abs(dir.x), abs(dir.y)) + dirReduce);
+	dir = clamp(dir * rcpDirMin, vec2(-FXAA_SPAN_MAX), vec2(FXAA_SPAN_MAX)) * InvSize;
+
+	vec3 rgbA = 0.5 * (texture(Tex, TexCoord + dir * (1.0 / 3.0 - 0.5)).rgb +
+		texture(Tex, TexCoord + dir * (2.0 / 3.0 - 0.5)).rgb);
+	vec3 rgbB = rgbA * 0.5 + 0.25 * (texture(Tex, TexCoord + dir * -0.5).rgb +
+		texture(Tex, TexCoord + dir * 0.5).rgb);
+	float lumaB = luma(rgbB);
+	if (lumaB < lumaMin || lumaB > lumaMax) {
+		outputColor = vec4(rgbA, 1.0);
+	} else {
+		outputColor = vec4(rgbB, 1.0);
+	}
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	pr.AddUniform("Tex", gpu.IUniType, false, 0)
+	pr.AddUniform("InvSize", gpu.Vec2fUniType, false, 0)
+
+	pr.SetFragDataVar("outputColor")
+
+	return nil
+}
+
+// Activate activates the FXAA program -- unlike the material renders, it
+// does not bind the Camera / Lights uniforms, which it does not use.
+func (rb *RenderFXAA) Activate(rn *Renderers) {
+	pr := rb.VtxFragProg()
+	pr.Activate()
+	gpu.TheGPU.ErrCheck("fxaa prog activate")
+}
+
+// InitQuad initializes the fullscreen quad buffer if not already done.
+// Must be called with appropriate context activated, on main.
+func (rb *RenderFXAA) InitQuad(rn *Renderers) {
+	if rb.Quad != nil {
+		return
+	}
+	rb.Quad = gpu.TheGPU.NewBufferMgr()
+	vbuf := rb.Quad.AddVectorsBuffer(gpu.StaticDraw)
+	vbuf.AddVectors(rn.Vectors[InVtxPos], false)
+	vtxs := mat32.ArrayF32{
+		-1, -1, 0, 1, -1, 0, 1, 1, 0,
+		-1, -1, 0, 1, 1, 0, -1, 1, 0,
+	}
+	vbuf.SetLen(6)
+	vbuf.SetVecData(rn.Vectors[InVtxPos], vtxs)
+	rb.Quad.Activate()
+	rb.Quad.TransferAll()
+}
+
+func (rb *RenderFXAA) Delete(rn *Renderers) {
+	if rb.Quad != nil {
+		rb.Quad.Delete()
+		rb.Quad = nil
+	}
+	rb.RenderBase.Delete(rn)
+}
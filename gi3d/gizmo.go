@@ -0,0 +1,325 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"image"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/mat32"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/cursor"
+	"github.com/goki/gi/oswin/mouse"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// GizmoModes are the manipulation modes for the Gizmo
+type GizmoModes int32
+
+const (
+	// GizmoTranslate shows translate arrows along each axis -- dragging an
+	// arrow moves the target along that world axis
+	GizmoTranslate GizmoModes = iota
+
+	// GizmoRotate shows rotate rings around each axis -- dragging a ring
+	// rotates the target around that world axis
+	GizmoRotate
+
+	// GizmoScale shows scale handles (cubes) along each axis -- dragging a
+	// handle scales the target along that axis
+	GizmoScale
+
+	GizmoModesN
+)
+
+//go:generate stringer -type=GizmoModes
+
+var KiT_GizmoModes = kit.Enums.AddEnum(GizmoModesN, kit.NotBitFlag, nil)
+
+// GizmoName is the reserved top-level child name for the manipulation
+// gizmo -- see Scene.ShowGizmo
+const GizmoName = "Gizmo"
+
+// GizmoAxisColors are the handle colors for the X, Y, Z axes,
+// following the usual red, green, blue convention (see AddNewGrid).
+var GizmoAxisColors = [3]gi.Color{{220, 60, 60, 255}, {60, 200, 60, 255}, {60, 90, 230, 255}}
+
+// gizmoAxisNames are the axis name suffixes used in handle names
+var gizmoAxisNames = [3]string{"x", "y", "z"}
+
+// Gizmo is an on-screen manipulator attached to a target node, for
+// interactive 3D editing: depending on Mode it draws translate arrows,
+// rotate rings, or scale handles, axis-colored (X red, Y green, Z blue),
+// aligned with the *world* axes at the target's world position, and
+// rendered in the overlay layer so it is always visible on top of the
+// scene.  Dragging a handle updates the target's Pose along / around the
+// constrained axis.  Use Scene.ShowGizmo / HideGizmo to manage it.
+type Gizmo struct {
+	Group
+	Mode   GizmoModes `desc:"what kind of manipulation the gizmo performs: translate, rotate, or scale -- set via ShowGizmo, which rebuilds the handles"`
+	Size   float32    `desc:"overall size of the gizmo (arrow / ring extent) in world units -- 1 default"`
+	Target Node3D     `copy:"-" json:"-" xml:"-" view:"-" desc:"the node being manipulated"`
+}
+
+var KiT_Gizmo = kit.Types.AddType(&Gizmo{}, GizmoProps)
+
+var GizmoProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+}
+
+func (gz *Gizmo) Defaults() {
+	gz.Group.Defaults()
+	gz.Size = 1
+}
+
+// ShowGizmo attaches a manipulation gizmo of given mode to given node,
+// replacing any existing gizmo -- see Gizmo.  If nd is nil, just removes
+// the gizmo (see HideGizmo).
+func (sc *Scene) ShowGizmo(nd Node3D, mode GizmoModes) *Gizmo {
+	updt := sc.UpdateStart()
+	defer sc.UpdateEnd(updt)
+	sc.DeleteChildByName(GizmoName, true)
+	if nd == nil {
+		return nil
+	}
+	gz := sc.AddNewChild(KiT_Gizmo, GizmoName).(*Gizmo)
+	gz.Defaults()
+	gz.Mode = mode
+	gz.Target = nd
+	gz.Pose.Pos = nd.AsNode3D().Pose.WorldMatrix.Pos()
+	gz.Config(sc)
+	for _, nm := range gz.MeshNames() {
+		sc.InitMesh(nm)
+	}
+	return gz
+}
+
+// HideGizmo removes the manipulation gizmo, if present -- see ShowGizmo.
+func (sc *Scene) HideGizmo() {
+	updt := sc.UpdateStart()
+	sc.DeleteChildByName(GizmoName, true)
+	sc.UpdateEnd(updt)
+}
+
+// MeshNames returns the names of the (shared) meshes used by the current
+// Mode, e.g., for initialization in ShowGizmo.
+func (gz *Gizmo) MeshNames() []string {
+	switch gz.Mode {
+	case GizmoTranslate:
+		return []string{GizmoName + "-shaft", GizmoName + "-cone"}
+	case GizmoRotate:
+		return []string{GizmoName + "-ring"}
+	case GizmoScale:
+		return []string{GizmoName + "-shaft", GizmoName + "-cube"}
+	}
+	return nil
+}
+
+// Config rebuilds the handle solids for the current Mode, creating the
+// shared handle meshes in the scene if not already present.
+func (gz *Gizmo) Config(sc *Scene) {
+	gz.DeleteChildren(true)
+	sz := gz.Size
+
+	// orient the Y-axis-aligned shaft / cone meshes along given axis
+	orientY := func(sld *Solid, ax mat32.Dims) {
+		switch ax {
+		case mat32.X:
+			sld.Pose.SetAxisRotation(0, 0, 1, -90)
+		case mat32.Z:
+			sld.Pose.SetAxisRotation(1, 0, 0, 90)
+		}
+	}
+
+	switch gz.Mode {
+	case GizmoTranslate, GizmoScale:
+		shaftNm := GizmoName + "-shaft"
+		if sc.MeshByName(shaftNm) == nil {
+			AddNewCylinder(sc, shaftNm, 1, .02, 8, 1, true, true)
+		}
+		tipNm := GizmoName + "-cone"
+		if gz.Mode == GizmoScale {
+			tipNm = GizmoName + "-cube"
+			if sc.MeshByName(tipNm) == nil {
+				AddNewBox(sc, tipNm, .15, .15, .15)
+			}
+		} else if sc.MeshByName(tipNm) == nil {
+			AddNewCone(sc, tipNm, .2, .07, 16, 1, true)
+		}
+		for ax := mat32.X; ax <= mat32.Z; ax++ {
+			var av mat32.Vec3
+			av.SetDim(ax, 1)
+			clr := GizmoAxisColors[ax]
+			sh := AddNewGizmoHandle(sc, gz, GizmoName+"-shaft-"+gizmoAxisNames[ax], shaftNm, ax, clr)
+			sh.Pose.Pos = av.MulScalar(sz / 2)
+			sh.Pose.Scale.Y = sz
+			orientY(&sh.Solid, ax)
+			tip := AddNewGizmoHandle(sc, gz, GizmoName+"-tip-"+gizmoAxisNames[ax], tipNm, ax, clr)
+			tip.Pose.Pos = av.MulScalar(sz)
+			orientY(&tip.Solid, ax)
+		}
+	case GizmoRotate:
+		ringNm := GizmoName + "-ring"
+		if sc.MeshByName(ringNm) == nil {
+			AddNewTorus(sc, ringNm, 1, .02, 32)
+		}
+		for ax := mat32.X; ax <= mat32.Z; ax++ { // ring mesh is in the XY plane (normal Z)
+			rg := AddNewGizmoHandle(sc, gz, GizmoName+"-ring-"+gizmoAxisNames[ax], ringNm, ax, GizmoAxisColors[ax])
+			rg.Pose.Scale.SetScalar(sz)
+			switch ax {
+			case mat32.X:
+				rg.Pose.SetAxisRotation(0, 1, 0, 90)
+			case mat32.Y:
+				rg.Pose.SetAxisRotation(1, 0, 0, 90)
+			}
+		}
+	}
+}
+
+// UpdateNode3D keeps the gizmo at the target's world position during render
+// (the gizmo itself stays world-axis aligned, unaffected by the target's
+// rotation).
+func (gz *Gizmo) UpdateNode3D(sc *Scene) {
+	if gz.Target == nil {
+		return
+	}
+	gz.Pose.Pos = gz.Target.AsNode3D().Pose.WorldMatrix.Pos()
+}
+
+// gizmoWinPos projects given world coordinate point into 2D scene pixel
+// coordinates, for relating mouse motion to the gizmo axes.
+func gizmoWinPos(sc *Scene, wpos mat32.Vec3) mat32.Vec2 {
+	var proj mat32.Mat4
+	proj.MulMatrices(&sc.Camera.PrjnMatrix, &sc.Camera.ViewMatrix)
+	ndc := wpos.MulProjection(&proj)
+	sz := sc.Geom.Size
+	w := ndc.NDCToWindow(mat32.Vec2{float32(sz.X), float32(sz.Y)}, mat32.Vec2{}, 0, 1, true)
+	return mat32.Vec2{w.X, w.Y}
+}
+
+// DragHandle applies the mouse drag increment from window point `from` to
+// `where` (as in mouse.DragEvent) on the handle for given axis to the
+// Target's Pose, according to the current Mode -- this is what the handle
+// drag events call.
+func (gz *Gizmo) DragHandle(sc *Scene, axis mat32.Dims, from, where image.Point) {
+	if gz.Target == nil {
+		return
+	}
+	tn := gz.Target.AsNode3D()
+	ctr := gz.Pose.Pos // world position -- gizmo is at top level
+	var av mat32.Vec3
+	av.SetDim(axis, 1)
+	switch gz.Mode {
+	case GizmoTranslate, GizmoScale:
+		// project the axis into screen space and take the drag motion along it
+		c2 := gizmoWinPos(sc, ctr)
+		a2 := gizmoWinPos(sc, ctr.Add(av))
+		dir := a2.Sub(c2)
+		lsq := dir.LengthSq()
+		if lsq < 1.0e-6 { // axis is pointing at the camera -- no motion possible
+			return
+		}
+		del := where.Sub(from)
+		amt := (float32(del.X)*dir.X + float32(del.Y)*dir.Y) / lsq // world units along axis
+		if gz.Mode == GizmoTranslate {
+			wdel := av.MulScalar(amt)
+			inv, _ := tn.Pose.ParMatrix.Inverse() // undo parent's transform
+			tn.Pose.Pos.SetAdd(wdel.MulMat4AsVec4(inv, 0))
+			gz.Pose.Pos.SetAdd(wdel)
+		} else {
+			nsc := tn.Pose.Scale.Dim(axis) * (1 + amt/gz.Size)
+			if nsc > 1.0e-4 {
+				tn.Pose.Scale.SetDim(axis, nsc)
+			}
+		}
+	case GizmoRotate:
+		// signed angle swept around the projected gizmo center
+		c2 := gizmoWinPos(sc, ctr)
+		off := sc.ObjBBox.Min // mouse coords are window-relative -- see RayPick
+		f := mat32.Vec2{float32(from.X - off.X), float32(from.Y - off.Y)}.Sub(c2)
+		w := mat32.Vec2{float32(where.X - off.X), float32(where.Y - off.Y)}.Sub(c2)
+		if f.LengthSq() < 1.0e-6 || w.LengthSq() < 1.0e-6 {
+			return
+		}
+		ang := mat32.Atan2(w.Y, w.X) - mat32.Atan2(f.Y, f.X)
+		if av.Dot(sc.Camera.Pose.Pos.Sub(ctr)) > 0 { // axis toward camera: screen Y-down flips sense
+			ang = -ang
+		}
+		inv, _ := tn.Pose.WorldMatrix.Inverse() // world axis -> local, undoing full transform
+		mvec := av.MulMat4AsVec4(inv, 0)
+		tn.Pose.RotateOnAxis(mvec.X, mvec.Y, mvec.Z, mat32.RadToDeg(ang))
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////
+//  GizmoHandle
+
+// GizmoHandle is one draggable handle solid of a Gizmo (arrow shaft / tip,
+// rotate ring, or scale cube), constrained to one axis -- dragging it calls
+// Gizmo.DragHandle.  Handles render in the overlay layer, always on top.
+type GizmoHandle struct {
+	Solid
+	Axis mat32.Dims `desc:"the world axis this handle manipulates"`
+}
+
+var KiT_GizmoHandle = kit.Types.AddType(&GizmoHandle{}, GizmoHandleProps)
+
+var GizmoHandleProps = ki.Props{
+	"EnumType:Flag": gi.KiT_NodeFlags,
+}
+
+// AddNewGizmoHandle adds a new gizmo handle of given name to given parent
+// (typically the Gizmo), using given mesh, axis, and color.
+func AddNewGizmoHandle(sc *Scene, parent ki.Ki, name string, meshName string, axis mat32.Dims, clr gi.Color) *GizmoHandle {
+	gh := parent.AddNewChild(KiT_GizmoHandle, name).(*GizmoHandle)
+	gh.SetMeshName(sc, meshName)
+	gh.Defaults()
+	gh.Axis = axis
+	gh.Mat.Color = clr
+	gh.Mat.Unlit = true // constant axis color regardless of lighting
+	gh.Layer = LayerOverlay
+	return gh
+}
+
+// GizmoHandle gets drag events to manipulate the gizmo target
+func (gh *GizmoHandle) ConnectEvents3D(sc *Scene) {
+	gh.ConnectEvent(sc.Win, oswin.MouseEvent, gi.HiPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.Event)
+		if me.Action != mouse.Press || !sc.IsVisible() {
+			return
+		}
+		me.SetProcessed() // claim the press so dragging is ours, not selection / camera
+	})
+	gh.ConnectEvent(sc.Win, oswin.MouseDragEvent, gi.HiPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.DragEvent)
+		me.SetProcessed()
+		ghh := recv.Embed(KiT_GizmoHandle).(*GizmoHandle)
+		gzi, err := ghh.ParentByTypeTry(KiT_Gizmo, false)
+		if err != nil {
+			return
+		}
+		gz := gzi.Embed(KiT_Gizmo).(*Gizmo)
+		sci, err := ghh.ParentByTypeTry(KiT_Scene, false)
+		if err != nil {
+			return
+		}
+		ssc := sci.Embed(KiT_Scene).(*Scene)
+		if !ghh.IsDragging() {
+			if ssc.SetDragCursor {
+				oswin.TheApp.Cursor(ssc.Viewport.Win.OSWin).Pop()
+				ssc.SetDragCursor = false
+			}
+			return
+		}
+		if !ssc.SetDragCursor {
+			oswin.TheApp.Cursor(ssc.Viewport.Win.OSWin).Push(cursor.HandOpen)
+			ssc.SetDragCursor = true
+		}
+		updt := ssc.UpdateStart()
+		gz.DragHandle(ssc, ghh.Axis, me.From, me.Where)
+		ssc.UpdateEnd(updt)
+	})
+}
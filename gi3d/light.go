@@ -99,12 +99,15 @@ func (dl *DirLight) ViewDir(viewMat *mat32.Mat4) mat32.Vec3 {
 
 // PointLight is an omnidirectional light with a position
 // and associated decay factors, which divide the light intensity as a function of
-// linear and quadratic distance.  The quadratic factor dominates at longer distances.
+// constant, linear, and quadratic distance terms:
+// intensity = 1 / (ConstDecay + LinDecay * d + QuadDecay * d^2)
+// The quadratic factor dominates at longer distances.
 type PointLight struct {
 	LightBase
-	Pos       mat32.Vec3 `desc:"position of light in world coordinates"`
-	LinDecay  float32    `desc:"Distance linear decay factor -- defaults to .1"`
-	QuadDecay float32    `desc:"Distance quadratic decay factor -- defaults to .01 -- this is "`
+	Pos        mat32.Vec3 `desc:"position of light in world coordinates"`
+	ConstDecay float32    `desc:"Constant decay term -- defaults to 1 -- must be at least this large to prevent saturation at short distances"`
+	LinDecay   float32    `desc:"Distance linear decay factor -- defaults to .1"`
+	QuadDecay  float32    `desc:"Distance quadratic decay factor -- defaults to .01 -- this is the dominant factor at longer distances"`
 }
 
 var KiT_PointLight = kit.Types.AddType(&PointLight{}, nil)
@@ -117,6 +120,7 @@ func AddNewPointLight(sc *Scene, name string, lumens float32, color LightColors)
 	lt.On = true
 	lt.Clr = LightColorMap[color]
 	lt.Lumns = lumens
+	lt.ConstDecay = 1
 	lt.LinDecay = .1
 	lt.QuadDecay = .01
 	lt.Pos.Set(0, 5, 5)
@@ -130,15 +134,18 @@ func (pl *PointLight) ViewPos(viewMat *mat32.Mat4) mat32.Vec3 {
 }
 
 // Spotlight is a light with a position and direction and associated decay factors and angles.
-// which divide the light intensity as a function of linear and quadratic distance.
+// which divide the light intensity as a function of constant, linear, and quadratic distance:
+// intensity = 1 / (ConstDecay + LinDecay * d + QuadDecay * d^2)
+// modulated by the angular falloff given by CutoffAngle and AngDecay.
 // The quadratic factor dominates at longer distances.
 type SpotLight struct {
 	LightBase
 	Pose        Pose    // position and orientation
-	AngDecay    float32 `desc:"Angular decay factor -- defaults to 15"`
-	CutoffAngle float32 `max:"90" min:"1" desc:"Cut off angle (in degrees) -- defaults to 45 -- max of 90"`
-	LinDecay    float32 `desc:"Distance linear decay factor -- defaults to 1"`
-	QuadDecay   float32 `desc:"Distance quadratic decay factor -- defaults to 1"`
+	AngDecay    float32 `desc:"Angular decay factor within the cone -- higher values focus the light more tightly toward the center -- defaults to 15"`
+	CutoffAngle float32 `max:"90" min:"1" desc:"Cut off angle (in degrees) of the cone, beyond which the light does not illuminate -- defaults to 45 -- max of 90"`
+	ConstDecay  float32 `desc:"Constant decay term -- defaults to 1 -- must be at least this large to prevent saturation at short distances"`
+	LinDecay    float32 `desc:"Distance linear decay factor -- defaults to .01"`
+	QuadDecay   float32 `desc:"Distance quadratic decay factor -- defaults to .001"`
 }
 
 var KiT_SpotLight = kit.Types.AddType(&SpotLight{}, nil)
@@ -155,6 +162,7 @@ func AddNewSpotLight(sc *Scene, name string, lumens float32, color LightColors)
 	lt.Lumns = lumens
 	lt.AngDecay = 15
 	lt.CutoffAngle = 45
+	lt.ConstDecay = 1
 	lt.LinDecay = .01
 	lt.QuadDecay = .001
 	lt.Pose.Defaults()
@@ -214,13 +222,13 @@ func (rn *Renderers) SetLightsUnis(sc *Scene) {
 		case *PointLight:
 			points = append(points, clr)
 			points = append(points, l.ViewPos(&sc.Camera.ViewMatrix))
-			points = append(points, mat32.Vec3{l.LinDecay, l.QuadDecay, 0})
+			points = append(points, mat32.Vec3{l.LinDecay, l.QuadDecay, l.ConstDecay})
 		case *SpotLight:
 			spots = append(spots, clr)
 			spots = append(spots, l.ViewPos(&sc.Camera.ViewMatrix))
 			spots = append(spots, l.ViewDir(&sc.Camera.ViewMatrix))
 			spots = append(spots, mat32.Vec3{l.AngDecay, l.CutoffAngle, l.LinDecay})
-			spots = append(spots, mat32.Vec3{l.QuadDecay, 0, 0})
+			spots = append(spots, mat32.Vec3{l.QuadDecay, l.ConstDecay, 0})
 		}
 	}
 
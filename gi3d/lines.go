@@ -5,6 +5,7 @@
 package gi3d
 
 import (
+	"fmt"
 	"log"
 	"math"
 
@@ -162,6 +163,45 @@ func AddNewLineBox(sc *Scene, parent ki.Ki, meshNm, boxNm string, bbox mat32.Box
 	return bgp
 }
 
+// AddNewGrid adds a new Group with line Solid's rendering a reference grid
+// on the XZ (ground) plane, centered at the origin, of given overall size
+// and line spacing, in given color.  The major X and Z axis lines through
+// the center are emphasized in the standard axis colors (red = X, blue = Z).
+// Everything is flagged inactive (not selectable), as a pure backdrop.
+// The line meshes are named name+"-line" etc, and reused if already made,
+// so ensure that they are likely to be correct.
+func AddNewGrid(sc *Scene, parent ki.Ki, name string, size float32, spacing float32, clr gi.Color) *Group {
+	if size <= 0 || spacing <= 0 {
+		log.Printf("gi3d.AddNewGrid: %v size and spacing must be > 0\n", name)
+		return nil
+	}
+	hsz := size / 2
+	wd := .01 * spacing
+	xclr := gi.Color{255, 0, 0, 255} // standard axis colors
+	zclr := gi.Color{0, 0, 255, 255}
+
+	gp := AddNewGroup(sc, parent, name)
+	n := int(mat32.Round(size / spacing))
+	for i := 0; i <= n; i++ {
+		p := -hsz + float32(i)*spacing
+		onAxis := mat32.Abs(p) < .001*spacing
+		zc := clr
+		if onAxis {
+			zc = zclr
+		}
+		zl := AddNewLine(sc, gp, name+"-line", fmt.Sprintf("%v-z%v", name, i), mat32.Vec3{p, 0, -hsz}, mat32.Vec3{p, 0, hsz}, wd, zc)
+		zl.SetInactive()
+		xc := clr
+		if onAxis {
+			xc = xclr
+		}
+		xl := AddNewLine(sc, gp, name+"-line", fmt.Sprintf("%v-x%v", name, i), mat32.Vec3{-hsz, 0, p}, mat32.Vec3{hsz, 0, p}, wd, xc)
+		xl.SetInactive()
+	}
+	gp.SetInactive()
+	return gp
+}
+
 func MiterPts(ax, ay, bx, by, cx, cy, w2 float32) mat32.Vec2 {
 	ppd := mat32.Vec2{ax - bx, ay - by}
 	ppu := ppd.Normal()
@@ -10,12 +10,40 @@ import (
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/mat32"
+	"github.com/goki/gi/oswin/gpu"
+	"github.com/goki/ki/kit"
 )
 
+// CullModes specifies which faces (if any) are culled when rendering
+// a material -- see Material.SetCullMode.
+type CullModes int
+
+const (
+	// CullNone renders both sides (double-sided) -- use for thin geometry
+	// such as planes and lines that must remain visible from behind.
+	// The lighting model flips the normal for back-facing fragments, so
+	// both sides are shaded correctly.
+	CullNone CullModes = iota
+
+	// CullBack culls the back-facing surfaces (the default) -- best
+	// performance for closed solids.
+	CullBack
+
+	// CullFront culls the front-facing surfaces.
+	CullFront
+
+	CullModesN
+)
+
+//go:generate stringer -type=CullModes
+
+var KiT_CullModes = kit.Enums.AddEnum(CullModesN, kit.NotBitFlag, nil)
+
 // Tiling are the texture tiling parameters
 type Tiling struct {
 	Repeat mat32.Vec2 `desc:"how often to repeat the texture in each direction"`
 	Off    mat32.Vec2 `desc:"offset for when to start the texure in each direction"`
+	Rot    float32    `desc:"rotation of the texture in degrees, about the center of the texture coordinates -- applied before Repeat and Off, so the texture can be spun in place -- animate Off / Rot for scrolling / rotating textures"`
 }
 
 // Defaults sets default tiling params if not yet initialized
@@ -25,6 +53,31 @@ func (tl *Tiling) Defaults() {
 	}
 }
 
+// PolygonOffset specifies a depth offset applied while rasterizing filled
+// polygons (maps to glPolygonOffset) -- the standard fix for z-fighting
+// between coplanar surfaces: set small negative values (e.g., -1, -1) on the
+// material of a decal, line, or text element to pull it cleanly in front of
+// the surface it sits on, without manual position nudging.
+type PolygonOffset struct {
+	Factor float32 `desc:"offset scaled by the polygon's depth slope"`
+	Units  float32 `desc:"offset in minimum resolvable depth units"`
+}
+
+// On returns true if a non-zero polygon offset is set
+func (po *PolygonOffset) On() bool {
+	return po.Factor != 0 || po.Units != 0
+}
+
+// Set sets the offset factor and units
+func (po *PolygonOffset) Set(factor, units float32) {
+	po.Factor = factor
+	po.Units = units
+}
+
+// MatName is a material name -- references a shared material in the
+// Scene materials library (see Scene.AddMaterial).
+type MatName string
+
 // Material describes the material properties of a surface (colors, shininess, texture)
 // i.e., phong lighting parameters.
 // Main color is used for both ambient and diffuse color, and alpha component
@@ -32,16 +85,22 @@ func (tl *Tiling) Defaults() {
 // The Specular color is always white (multiplied by light color).
 // Textures are stored on the Scene and accessed by name
 type Material struct {
-	Color     gi.Color `xml:"color" desc:"prop: color = main color of surface, used for both ambient and diffuse color in standard Phong model -- alpha component determines transparency -- note that transparent objects require more complex rendering"`
-	Emissive  gi.Color `xml:"emissive" desc:"prop: emissive = color that surface emits independent of any lighting -- i.e., glow -- can be used for marking lights with an object"`
-	Specular  gi.Color `xml:"specular" desc:"prop: specular = shiny reflective color of surface -- set to white for shiny objects and to Color for non-shiny objects"`
-	Shiny     float32  `xml:"shiny" desc:"prop: shiny = specular shininess factor -- how focally the surface shines back directional light -- this is an exponential factor, with 0 = very broad diffuse reflection, and higher values (typically max of 128 or so but can go higher) having a smaller more focal specular reflection.  Also set Specular color to affect overall shininess effect."`
-	Bright    float32  `xml:"bright" desc:"prop: bright = overall multiplier on final computed color value -- can be used to tune the overall brightness of various surfaces relative to each other for a given set of lighting parameters"`
-	Texture   TexName  `xml:"texture" desc:"prop: texture = texture to provide color for the surface"`
-	Tiling    Tiling   `view:"inline" viewif:"Texture!=''" desc:"texture tiling parameters -- repeat and offset"`
-	CullBack  bool     `xml:"cull-back" desc:"prop: cull-back = cull the back-facing surfaces"`
-	CullFront bool     `xml:"cull-front" desc:"prop: cull-front = cull the front-facing surfaces"`
-	TexPtr    Texture  `view:"-" desc:"pointer to texture"`
+	Color      gi.Color       `xml:"color" desc:"prop: color = main color of surface, used for both ambient and diffuse color in standard Phong model -- alpha component determines transparency -- note that transparent objects require more complex rendering"`
+	Emissive   gi.Color       `xml:"emissive" desc:"prop: emissive = color that surface emits independent of any lighting -- i.e., glow -- can be used for marking lights with an object"`
+	Specular   gi.Color       `xml:"specular" desc:"prop: specular = shiny reflective color of surface -- set to white for shiny objects and to Color for non-shiny objects"`
+	Shiny      float32        `xml:"shiny" desc:"prop: shiny = specular shininess factor -- how focally the surface shines back directional light -- this is an exponential factor, with 0 = very broad diffuse reflection, and higher values (typically max of 128 or so but can go higher) having a smaller more focal specular reflection.  Also set Specular color to affect overall shininess effect."`
+	Bright     float32        `xml:"bright" desc:"prop: bright = overall multiplier on final computed color value -- can be used to tune the overall brightness of various surfaces relative to each other for a given set of lighting parameters"`
+	Unlit      bool           `xml:"unlit" desc:"prop: unlit = ignore all scene lighting and render the flat color / texture (plus Emissive) exactly as given -- for HUD elements, labels, and other UI-like content whose brightness should not depend on the lights"`
+	Texture    TexName        `xml:"texture" desc:"prop: texture = texture to provide color for the surface"`
+	Texture2   TexName        `xml:"texture2" desc:"prop: texture2 = optional secondary texture (e.g., a lightmap), sampled with the second set of texture coordinates (Tex2) on the mesh, and modulating the primary texture color -- requires Texture to also be set"`
+	Tiling     Tiling         `view:"inline" viewif:"Texture!=''" desc:"texture tiling parameters -- repeat, offset, and rotation"`
+	CullBack   bool           `xml:"cull-back" desc:"prop: cull-back = cull the back-facing surfaces"`
+	CullFront  bool           `xml:"cull-front" desc:"prop: cull-front = cull the front-facing surfaces"`
+	PolyOffset PolygonOffset  `view:"inline" desc:"polygon depth offset (factor, units) for rendering coplanar geometry (decals, outlines) cleanly over a surface without z-fighting -- (0, 0) = off -- see SetPolygonOffset"`
+	DepthFunc  gpu.DepthFuncs `desc:"depth comparison function used when rendering this material -- the default DepthLEqual is right for normal geometry -- e.g., DepthAlways makes an overlay that draws regardless of depth"`
+	Custom     Render         `copy:"-" json:"-" xml:"-" view:"-" desc:"custom user-supplied shader program to use instead of the built-in lighting shaders -- see SetShaders"`
+	TexPtr     Texture        `copy:"-" json:"-" xml:"-" view:"-" desc:"pointer to texture"`
+	Tex2Ptr    Texture        `copy:"-" json:"-" xml:"-" view:"-" desc:"pointer to second texture"`
 }
 
 // Defaults sets default surface parameters
@@ -69,6 +128,34 @@ func (mt *Material) NoTexture() {
 	mt.TexPtr = nil
 }
 
+// SetPolygonOffset sets the polygon depth offset factor and units -- the
+// standard fix for z-fighting between coplanar surfaces: use small negative
+// values (e.g., -1, -1) to pull this material's geometry in front of the
+// surface it sits on -- (0, 0) turns the offset off.
+func (mt *Material) SetPolygonOffset(factor, units float32) {
+	mt.PolyOffset.Set(factor, units)
+}
+
+// SetCullMode sets the face culling mode, as expressed in the CullBack /
+// CullFront flags: CullBack is the default, and CullNone makes the
+// material double-sided (no culling).
+func (mt *Material) SetCullMode(mode CullModes) {
+	mt.CullBack = mode == CullBack
+	mt.CullFront = mode == CullFront
+}
+
+// CullMode returns the face culling mode corresponding to the current
+// CullBack / CullFront flags (CullFront takes precedence if both are set).
+func (mt *Material) CullMode() CullModes {
+	switch {
+	case mt.CullFront:
+		return CullFront
+	case mt.CullBack:
+		return CullBack
+	}
+	return CullNone
+}
+
 // SetTextureName sets material to use given texture name
 // (textures are accessed by name on Scene).
 // If name is empty, then texture is reset
@@ -98,6 +185,36 @@ func (mt *Material) SetTexture(sc *Scene, tex Texture) {
 	}
 }
 
+// SetTexture2Name sets the secondary texture (e.g., lightmap) to given
+// texture name -- sampled with the second set of texture coordinates (Tex2)
+// on the mesh.  If name is empty, then the secondary texture is reset.
+func (mt *Material) SetTexture2Name(sc *Scene, texName string) error {
+	if texName == "" {
+		mt.Texture2 = ""
+		mt.Tex2Ptr = nil
+		return nil
+	}
+	tx, ok := sc.Textures[texName]
+	if !ok {
+		err := fmt.Errorf("gi3d.Material in Scene: %s SetTexture2 name: %s not found in scene", sc.PathUnique(), texName)
+		log.Println(err)
+		return err
+	}
+	mt.Texture2 = TexName(texName)
+	mt.Tex2Ptr = tx
+	return nil
+}
+
+// SetTexture2 sets material to use given secondary texture (e.g., lightmap)
+func (mt *Material) SetTexture2(sc *Scene, tex Texture) {
+	mt.Tex2Ptr = tex
+	if mt.Tex2Ptr != nil {
+		mt.Texture2 = TexName(mt.Tex2Ptr.Name())
+	} else {
+		mt.Texture2 = ""
+	}
+}
+
 // Validate does overall material validation, including checking that material
 // texture is valid if set
 func (mt *Material) Validate(sc *Scene) error {
@@ -113,5 +230,13 @@ func (mt *Material) Validate(sc *Scene) error {
 			return err
 		}
 	}
+	if mt.Texture2 == "" {
+		mt.Tex2Ptr = nil
+	} else if mt.Tex2Ptr == nil || mt.Tex2Ptr.Name() != string(mt.Texture2) {
+		err := mt.SetTexture2Name(sc, string(mt.Texture2))
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
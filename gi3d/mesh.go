@@ -20,12 +20,16 @@ import (
 type MeshName string
 
 // Mesh holds the mesh-based shape used for rendering a Solid.
-// Only indexed triangle meshes are supported.
+// Indexed triangle meshes are the standard case, but the Prim field
+// selects other primitive types (triangle strips / fans, lines, points)
+// for the draw call -- see gpu.Primitives.
 // All Mesh's must define Vtx and Norm -- Tex is optional --
 // all are stored interleaved.  The Idx component points into
 // these elements as used in modern indexed VBO rendering.
 // Per-vertex Color is optional, and is appended to the vertex
 // buffer non-interleaved if present.
+// Per-vertex Joints / Weights are optional, for skinned (rigged)
+// meshes driven by a Skeleton.
 type Mesh interface {
 	// Name returns name of the mesh
 	Name() string
@@ -56,6 +60,14 @@ type Mesh interface {
 	// ComputeNorms automatically computes the normals from existing vertex data
 	ComputeNorms()
 
+	// RecomputeNormals recomputes the per-vertex normals from the current
+	// vertex and triangle index data -- e.g., for imported meshes with
+	// missing or unwanted normals.  smooth selects smoothed (area-weighted
+	// average across shared vertices) vs. flat (per-face) normals, and
+	// weldEps is the distance threshold within which duplicated vertices
+	// are treated as shared for smoothing -- see MeshBase for details.
+	RecomputeNormals(smooth bool, weldEps float32)
+
 	// Alloc allocates given number of vertex and index values, optionally
 	// including colors.   More efficient if number of such is known in advance.
 	Alloc(vtxs, idxs int, color bool)
@@ -103,6 +115,10 @@ type Mesh interface {
 	// HasColor returns true if this mesh has vertex-specific colors available
 	HasColor() bool
 
+	// HasSkin returns true if this mesh has per-vertex joint indices and
+	// weights for skeletal animation (skinning) -- see Skeleton
+	HasSkin() bool
+
 	// IsTransparent returns true if this mesh has vertex-specific colors available
 	// and at least some are transparent.
 	IsTransparent() bool
@@ -132,9 +148,10 @@ type Mesh interface {
 	// Activate must have just been called
 	TransferIndexes()
 
-	// Render3D calls gpu.TrianglesIndexed to render the mesh.
+	// Render3D renders the mesh from its index buffer, using the primitive
+	// type in the MeshBase Prim field (gpu.PrimTriangles by default).
 	// Must be called in context on main thread -- does activate,
-	// then draw triangles indexed.
+	// then draw primitives indexed.
 	Render3D(sc *Scene)
 
 	// SetVtxData sets the (updated) Vtx data into the overall vector that
@@ -153,6 +170,14 @@ type Mesh interface {
 	// has no constraints on where called.
 	SetNormData(sc *Scene)
 
+	// SetTexData sets the (updated) Tex UV data into the overall vector that
+	// will be transfered using the next TransferVectors call.
+	// It is essential that the length has not changed -- if length is changing
+	// then you must update everything and call MakeVectors.
+	// Use this for dynamically updating texture coordinate data (only if in use!)
+	// has no constraints on where called.
+	SetTexData(sc *Scene)
+
 	// SetColorData sets the (updated) Color data into the overall vector that
 	// will be transfered using the next TransferVectors call.
 	// It is essential that the length has not changed -- if length is changing
@@ -160,19 +185,58 @@ type Mesh interface {
 	// Use this for dynamically updating color data (only use if vertex color in use!)
 	// has no constraints on where called.
 	SetColorData(sc *Scene)
+
+	// Vertices returns a copy of the vertex positions, as one Vec3 per
+	// vertex -- see meshedit.go for the full mesh editing API.
+	Vertices() []mat32.Vec3
+
+	// SetVertices sets the vertex positions from given list, and flags the
+	// mesh as Edited so the next Update re-uploads to the GPU.
+	// Recomputes the bounding box.  See meshedit.go.
+	SetVertices(vs []mat32.Vec3)
+
+	// Normals returns a copy of the vertex normals, as one Vec3 per vertex.
+	Normals() []mat32.Vec3
+
+	// SetNormals sets the vertex normals from given list, and flags the
+	// mesh as Edited -- see also RecomputeNormals to derive them from the
+	// vertex and face data.
+	SetNormals(ns []mat32.Vec3)
+
+	// TexCoords returns a copy of the texture UV coordinates, as one Vec2
+	// per vertex.
+	TexCoords() []mat32.Vec2
+
+	// SetTexCoords sets the texture UV coordinates from given list, and
+	// flags the mesh as Edited.
+	SetTexCoords(uvs []mat32.Vec2)
+
+	// Indexes returns a copy of the triangle face index buffer (3 sequential
+	// vertex indexes per face).
+	Indexes() []uint32
+
+	// SetIndexes sets the triangle face index buffer from given list, and
+	// flags the mesh as Edited -- returns error (logged) if any index is out
+	// of range of the current vertices, or the length is not a multiple of 3.
+	SetIndexes(idxs []uint32) error
 }
 
 // MeshBase provides the core implementation of Mesh interface
 type MeshBase struct {
 	Nm      string         `desc:"name of mesh -- meshes are linked to Solids by name so this matters"`
 	Dynamic bool           `desc:"if true, this mesh changes frequently -- otherwise considered to be static"`
+	Prim    gpu.Primitives `desc:"primitive type rendered from the index buffer: independent triangles (the default), triangle strips / fans, lines, or points -- strips are far more compact for terrain grids and ribbons -- the index buffer must match (see ValidatePrim)"`
 	Trans   bool           `desc:"set to true if color has transparency -- not worth checking manually"`
 	Vtx     mat32.ArrayF32 `desc:"verticies for triangle shapes that make up the mesh -- all mesh structures must use indexed triangle meshes"`
 	Norm    mat32.ArrayF32 `desc:"computed normals for each vertex"`
 	Tex     mat32.ArrayF32 `desc:"texture U,V coordinates for mapping textures onto vertexes"`
+	Tex2    mat32.ArrayF32 `desc:"optional second set of texture U,V coordinates, e.g., for lightmaps -- sampled by the Material Texture2 -- only used if Tex is also set"`
 	Idx     mat32.ArrayU32 `desc:"indexes that sequentially in groups of 3 define the actual triangle faces"`
 	Color   mat32.ArrayF32 `desc:"if per-vertex color material type is used for this mesh, then these are the per-vertex colors -- may not be defined in which case per-vertex materials are not possible for such meshes"`
+	Joints  mat32.ArrayF32 `desc:"optional per-vertex joint indices (4 per vertex, as float32) into the Skeleton driving this mesh -- only for skinned (rigged) meshes -- must be set along with Weights"`
+	Weights mat32.ArrayF32 `desc:"optional per-vertex joint weights (4 per vertex, should sum to 1) corresponding to Joints -- only for skinned (rigged) meshes"`
 	BBox    BBox           `desc:"computed bounding-box and other gross solid properties"`
+	Edited  bool           `view:"-" desc:"set when the mesh data has been modified via the editing API (SetVertices etc -- see meshedit.go) -- causes Update to re-upload the buffers to the GPU, and is then cleared"`
 	Buff    gpu.BufferMgr  `view:"-" desc:"buffer holding computed verticies, normals, indices, etc for rendering"`
 }
 
@@ -190,10 +254,18 @@ func (ms *MeshBase) HasTex() bool {
 	return len(ms.Tex) > 0
 }
 
+func (ms *MeshBase) HasTex2() bool {
+	return len(ms.Tex2) > 0
+}
+
 func (ms *MeshBase) HasColor() bool {
 	return len(ms.Color) > 0
 }
 
+func (ms *MeshBase) HasSkin() bool {
+	return len(ms.Joints) > 0 && len(ms.Weights) > 0
+}
+
 func (ms *MeshBase) IsTransparent() bool {
 	if !ms.HasColor() {
 		return false
@@ -201,13 +273,118 @@ func (ms *MeshBase) IsTransparent() bool {
 	return ms.Trans
 }
 
+// Update re-uploads the mesh data to the GPU if it has been modified via
+// the editing API (SetVertices etc -- see meshedit.go and the Edited flag)
+// -- otherwise a nop for this default static mesh case (dynamic mesh types
+// such as ParticlesMesh define their own Update).  If buffer lengths are
+// unchanged only the vertex data is transferred, otherwise the full buffers
+// are remade.  Called with context active on main thread, e.g., via
+// Scene.UpdateMeshes.
 func (ms *MeshBase) Update(sc *Scene) {
-	// nop: default mesh is static, not dynamic
+	if !ms.Edited || ms.Buff == nil {
+		return
+	}
+	ms.Edited = false
+	vbuf := ms.Buff.VectorsBuffer()
+	ibuf := ms.Buff.IndexesBuffer()
+	if vbuf.Len() != len(ms.Vtx)/3 || ibuf.Len() != len(ms.Idx) {
+		ms.MakeVectors(sc)
+		ms.Activate(sc)
+		ms.TransferAll()
+		return
+	}
+	ms.SetVtxData(sc)
+	ms.SetNormData(sc)
+	if ms.HasTex() {
+		ms.SetTexData(sc)
+	}
+	if ms.HasColor() {
+		ms.SetColorData(sc)
+	}
+	ibuf.Set(ms.Idx)
+	ms.Activate(sc)
+	ms.TransferVectors()
+	ms.TransferIndexes()
 }
 
 func (ms *MeshBase) ComputeNorms() {
 }
 
+// RecomputeNormals recomputes the per-vertex normals from the current vertex
+// and triangle index data, e.g., for imported meshes with missing or
+// unwanted normals.  If smooth, each normal is the area-weighted average of
+// all faces sharing that vertex, with vertices closer than weldEps treated
+// as shared (welded, using a grid of that resolution) so that duplicated
+// vertices still shade smoothly -- weldEps <= 0 only averages across indexed
+// sharing.  If not smooth, each vertex gets the flat normal of the (last)
+// face referencing it -- for fully faceted shading, vertices must not be
+// shared across faces.  Does not re-upload to the GPU: call SetNormData
+// (with context active on main thread) or re-initialize the mesh after.
+func (ms *MeshBase) RecomputeNormals(smooth bool, weldEps float32) {
+	nv := len(ms.Vtx) / 3
+	nf := len(ms.Idx) / 3
+	if nv == 0 || nf == 0 {
+		return
+	}
+	if len(ms.Norm) != 3*nv {
+		ms.Norm = mat32.NewArrayF32(3*nv, 3*nv)
+	}
+	var va, vb, vc mat32.Vec3
+	if !smooth {
+		for f := 0; f < nf; f++ {
+			ia := int(ms.Idx[3*f])
+			ib := int(ms.Idx[3*f+1])
+			ic := int(ms.Idx[3*f+2])
+			ms.Vtx.GetVec3(3*ia, &va)
+			ms.Vtx.GetVec3(3*ib, &vb)
+			ms.Vtx.GetVec3(3*ic, &vc)
+			norm := mat32.Normal(va, vb, vc)
+			ms.Norm.SetVec3(3*ia, norm)
+			ms.Norm.SetVec3(3*ib, norm)
+			ms.Norm.SetVec3(3*ic, norm)
+		}
+		return
+	}
+	weld := make([]int, nv) // canonical vertex index for each vertex
+	for i := range weld {
+		weld[i] = i
+	}
+	if weldEps > 0 {
+		grid := make(map[[3]int]int, nv) // first vertex per quantized grid cell
+		for i := 0; i < nv; i++ {
+			ms.Vtx.GetVec3(3*i, &va)
+			key := [3]int{int(mat32.Round(va.X / weldEps)), int(mat32.Round(va.Y / weldEps)), int(mat32.Round(va.Z / weldEps))}
+			if c, ok := grid[key]; ok {
+				weld[i] = c
+			} else {
+				grid[key] = i
+			}
+		}
+	}
+	acc := make([]mat32.Vec3, nv) // accumulated normals, on canonical vertices
+	for f := 0; f < nf; f++ {
+		ia := int(ms.Idx[3*f])
+		ib := int(ms.Idx[3*f+1])
+		ic := int(ms.Idx[3*f+2])
+		ms.Vtx.GetVec3(3*ia, &va)
+		ms.Vtx.GetVec3(3*ib, &vb)
+		ms.Vtx.GetVec3(3*ic, &vc)
+		fn := vb.Sub(va).Cross(vc.Sub(va)) // length = 2 * face area: area weighting
+		acc[weld[ia]] = acc[weld[ia]].Add(fn)
+		acc[weld[ib]] = acc[weld[ib]].Add(fn)
+		acc[weld[ic]] = acc[weld[ic]].Add(fn)
+	}
+	for i := 0; i < nv; i++ {
+		n := acc[weld[i]]
+		if n.LengthSq() > 0 {
+			n = n.Normal()
+		} else {
+			n = mat32.Vec3{X: 0, Y: 0, Z: 1} // degenerate: default to +Z
+		}
+		ms.Norm.SetVec3(3*i, n)
+	}
+}
+
 // AsMeshBase returns the MeshBase for this Mesh
 func (ms *MeshBase) AsMeshBase() *MeshBase {
 	return ms
@@ -228,8 +405,11 @@ func (ms *MeshBase) Reset() {
 	ms.Vtx = nil
 	ms.Norm = nil
 	ms.Tex = nil
+	ms.Tex2 = nil
 	ms.Idx = nil
 	ms.Color = nil
+	ms.Joints = nil
+	ms.Weights = nil
 	ms.BBox.BBox.SetEmpty()
 }
 
@@ -254,13 +434,57 @@ func (ms *MeshBase) Validate() error {
 		log.Println(err)
 		return err
 	}
+	t2ln := len(ms.Tex2) / 2
+	if t2ln != 0 && t2ln != vln {
+		err := fmt.Errorf("gi3d.Mesh: %v number of Tex2: %d != Vtx: %d", ms.Nm, t2ln, vln)
+		log.Println(err)
+		return err
+	}
 	cln := len(ms.Color) / 4
 	if cln != 0 && cln != vln {
 		err := fmt.Errorf("gi3d.Mesh: %v number of Colors: %d != Vtx: %d", ms.Nm, cln, vln)
 		log.Println(err)
 		return err
 	}
-	return nil
+	jln := len(ms.Joints) / 4
+	wln := len(ms.Weights) / 4
+	if jln != wln {
+		err := fmt.Errorf("gi3d.Mesh: %v number of Joints: %d != Weights: %d", ms.Nm, jln, wln)
+		log.Println(err)
+		return err
+	}
+	if jln != 0 && jln != vln {
+		err := fmt.Errorf("gi3d.Mesh: %v number of Joints: %d != Vtx: %d", ms.Nm, jln, vln)
+		log.Println(err)
+		return err
+	}
+	return ms.ValidatePrim(len(ms.Idx))
+}
+
+// ValidatePrim checks that the given index buffer length is valid for the
+// Prim primitive type: a multiple of 3 for PrimTriangles, at least 3 for
+// strips and fans, a multiple of 2 for PrimLines (no constraint for
+// PrimPoints).  Any errors are logged.  Called by Validate.
+func (ms *MeshBase) ValidatePrim(iln int) error {
+	var err error
+	switch ms.Prim {
+	case gpu.PrimTriangles:
+		if iln%3 != 0 {
+			err = fmt.Errorf("gi3d.Mesh: %v number of Idx: %d must be a multiple of 3 for PrimTriangles", ms.Nm, iln)
+		}
+	case gpu.PrimTriangleStrip, gpu.PrimTriangleFan:
+		if iln > 0 && iln < 3 {
+			err = fmt.Errorf("gi3d.Mesh: %v number of Idx: %d must be at least 3 for %v", ms.Nm, iln, ms.Prim)
+		}
+	case gpu.PrimLines:
+		if iln%2 != 0 {
+			err = fmt.Errorf("gi3d.Mesh: %v number of Idx: %d must be a multiple of 2 for PrimLines", ms.Nm, iln)
+		}
+	}
+	if err != nil {
+		log.Println(err)
+	}
+	return err
 }
 
 // MakeVectors compiles the existing mesh data into the Vectors for GPU rendering
@@ -289,14 +513,25 @@ func (ms *MeshBase) MakeVectors(sc *Scene) error {
 	if hasTex {
 		nvec++
 	}
+	hasTex2 := hasTex && ms.HasTex2()
+	if hasTex2 {
+		nvec++
+	}
 	hasColor := ms.HasColor()
 	if hasColor {
 		nvec++
 	}
+	hasSkin := ms.HasSkin()
+	if hasSkin {
+		nvec += 2
+	}
 	vtx := sc.Renders.Vectors[InVtxPos]
 	nrm := sc.Renders.Vectors[InVtxNorm]
 	tex := sc.Renders.Vectors[InVtxTex]
+	tex2 := sc.Renders.Vectors[InVtxTex2]
 	clr := sc.Renders.Vectors[InVtxColor]
+	jnt := sc.Renders.Vectors[InVtxJoints]
+	wgt := sc.Renders.Vectors[InVtxWeights]
 	if vbuf.NumVectors() != nvec {
 		vbuf.DeleteAllVectors()
 		vbuf.AddVectors(vtx, true) // interleave
@@ -304,6 +539,13 @@ func (ms *MeshBase) MakeVectors(sc *Scene) error {
 		if hasTex {
 			vbuf.AddVectors(tex, true) // interleave
 		}
+		if hasTex2 {
+			vbuf.AddVectors(tex2, true) // interleave
+		}
+		if hasSkin {
+			vbuf.AddVectors(jnt, true) // interleave
+			vbuf.AddVectors(wgt, true) // interleave
+		}
 		if hasColor {
 			vbuf.AddVectors(clr, false) // NO interleave
 		}
@@ -315,6 +557,13 @@ func (ms *MeshBase) MakeVectors(sc *Scene) error {
 	if hasTex {
 		vbuf.SetVecData(tex, ms.Tex)
 	}
+	if hasTex2 {
+		vbuf.SetVecData(tex2, ms.Tex2)
+	}
+	if hasSkin {
+		vbuf.SetVecData(jnt, ms.Joints)
+		vbuf.SetVecData(wgt, ms.Weights)
+	}
 	if hasColor {
 		vbuf.SetVecData(clr, ms.Color)
 	}
@@ -350,6 +599,18 @@ func (ms *MeshBase) SetNormData(sc *Scene) {
 	vbuf.SetVecData(nrm, ms.Norm)
 }
 
+// SetTexData sets the (updated) Tex UV data into the overall vector that
+// will be transfered using the next TransferVectors call.
+// It is essential that the length has not changed -- if length is changing
+// then you must update everything and call MakeVectors.
+// Use this for dynamically updating texture coordinate data (only if in use!)
+// has no constraints on where called.
+func (ms *MeshBase) SetTexData(sc *Scene) {
+	vbuf := ms.Buff.VectorsBuffer()
+	tex := sc.Renders.Vectors[InVtxTex]
+	vbuf.SetVecData(tex, ms.Tex)
+}
+
 // SetColorData sets the (updated) Color data into the overall vector that
 // will be transfered using the next TransferVectors call.
 // It is essential that the length has not changed -- if length is changing
@@ -401,7 +662,8 @@ func (ms *MeshBase) TransferIndexes() {
 	ms.Buff.TransferIndexes()
 }
 
-// Render3D calls gpu.TrianglesIndexed to render the mesh
+// Render3D renders the mesh from its index buffer, using the Prim primitive
+// type (gpu.PrimTriangles by default).
 // Activate must have just been called, assumed to be on main with context
 func (ms *MeshBase) Render3D(sc *Scene) {
 	ok := ms.Activate(sc)
@@ -409,7 +671,7 @@ func (ms *MeshBase) Render3D(sc *Scene) {
 		return
 	}
 	ibuf := ms.Buff.IndexesBuffer()
-	gpu.Draw.TrianglesIndexed(0, ibuf.Len())
+	gpu.Draw.PrimitivesIndexed(ms.Prim, 0, ibuf.Len())
 }
 
 /////////////////////////////////////////////////////////////////////
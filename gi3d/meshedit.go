@@ -0,0 +1,248 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/goki/gi/mat32"
+)
+
+// This file provides the mesh editing API: read / write access to a mesh's
+// vertex positions, normals, texture UV coordinates, and triangle face
+// index buffer, for procedural deformation, morphing, terrain editing, etc.
+// All Set* methods flag the mesh as Edited, so the next Update (e.g., via
+// Scene.UpdateMeshes, with context active on main thread) re-uploads the
+// modified buffers to the GPU -- if the buffer lengths changed, the full
+// buffers are remade.  Normals are not automatically recomputed -- call
+// RecomputeNormals if vertex edits should be reflected in the shading.
+// Note: shape meshes (Sphere, Box etc) regenerate from their parameters in
+// Make, so edits to those are lost on re-initialization -- use GenMesh for
+// fully mutable meshes.
+
+// NumVerts returns the number of vertices in the mesh
+func (ms *MeshBase) NumVerts() int {
+	return len(ms.Vtx) / 3
+}
+
+// NumFaces returns the number of triangle faces in the mesh
+func (ms *MeshBase) NumFaces() int {
+	return len(ms.Idx) / 3
+}
+
+// Vertices returns a copy of the vertex positions, as one Vec3 per vertex.
+func (ms *MeshBase) Vertices() []mat32.Vec3 {
+	nv := ms.NumVerts()
+	vs := make([]mat32.Vec3, nv)
+	for i := range vs {
+		ms.Vtx.GetVec3(3*i, &vs[i])
+	}
+	return vs
+}
+
+// SetVertices sets the vertex positions from given list, and flags the mesh
+// as Edited so the next Update re-uploads to the GPU.  Recomputes the
+// bounding box.  If the length differs from the current number of vertices,
+// the other per-vertex data and indexes must be updated to match as well.
+func (ms *MeshBase) SetVertices(vs []mat32.Vec3) {
+	if len(ms.Vtx) != 3*len(vs) {
+		ms.Vtx = mat32.NewArrayF32(3*len(vs), 3*len(vs))
+	}
+	for i := range vs {
+		ms.Vtx.SetVec3(3*i, vs[i])
+	}
+	ms.ComputeBBox()
+	ms.Edited = true
+}
+
+// Vertex returns the position of given vertex -- returns error (logged)
+// if index is out of range.
+func (ms *MeshBase) Vertex(idx int) (mat32.Vec3, error) {
+	var v mat32.Vec3
+	if err := ms.vtxIdxTry(idx); err != nil {
+		return v, err
+	}
+	ms.Vtx.GetVec3(3*idx, &v)
+	return v, nil
+}
+
+// SetVertex sets the position of given vertex, and flags the mesh as Edited
+// -- returns error (logged) if index is out of range.  Expands the bounding
+// box to include the new position -- call ComputeBBox after a batch of edits
+// for exact bounds (e.g., if vertices moved inward).
+func (ms *MeshBase) SetVertex(idx int, v mat32.Vec3) error {
+	if err := ms.vtxIdxTry(idx); err != nil {
+		return err
+	}
+	ms.Vtx.SetVec3(3*idx, v)
+	ms.BBox.BBox.ExpandByPoint(v)
+	ms.Edited = true
+	return nil
+}
+
+// Normals returns a copy of the vertex normals, as one Vec3 per vertex.
+func (ms *MeshBase) Normals() []mat32.Vec3 {
+	nn := len(ms.Norm) / 3
+	ns := make([]mat32.Vec3, nn)
+	for i := range ns {
+		ms.Norm.GetVec3(3*i, &ns[i])
+	}
+	return ns
+}
+
+// SetNormals sets the vertex normals from given list, and flags the mesh
+// as Edited -- see also RecomputeNormals to derive them from the vertex
+// and face data instead.
+func (ms *MeshBase) SetNormals(ns []mat32.Vec3) {
+	if len(ms.Norm) != 3*len(ns) {
+		ms.Norm = mat32.NewArrayF32(3*len(ns), 3*len(ns))
+	}
+	for i := range ns {
+		ms.Norm.SetVec3(3*i, ns[i])
+	}
+	ms.Edited = true
+}
+
+// Normal returns the normal of given vertex -- returns error (logged)
+// if index is out of range.
+func (ms *MeshBase) Normal(idx int) (mat32.Vec3, error) {
+	var n mat32.Vec3
+	if idx < 0 || 3*idx >= len(ms.Norm) {
+		err := fmt.Errorf("gi3d.Mesh: %v normal index %d out of range: %d", ms.Nm, idx, len(ms.Norm)/3)
+		log.Println(err)
+		return n, err
+	}
+	ms.Norm.GetVec3(3*idx, &n)
+	return n, nil
+}
+
+// SetNormal sets the normal of given vertex, and flags the mesh as Edited
+// -- returns error (logged) if index is out of range.
+func (ms *MeshBase) SetNormal(idx int, n mat32.Vec3) error {
+	if idx < 0 || 3*idx >= len(ms.Norm) {
+		err := fmt.Errorf("gi3d.Mesh: %v normal index %d out of range: %d", ms.Nm, idx, len(ms.Norm)/3)
+		log.Println(err)
+		return err
+	}
+	ms.Norm.SetVec3(3*idx, n)
+	ms.Edited = true
+	return nil
+}
+
+// TexCoords returns a copy of the texture UV coordinates, as one Vec2 per
+// vertex -- empty if the mesh has no texture coordinates.
+func (ms *MeshBase) TexCoords() []mat32.Vec2 {
+	nt := len(ms.Tex) / 2
+	uvs := make([]mat32.Vec2, nt)
+	for i := range uvs {
+		ms.Tex.GetVec2(2*i, &uvs[i])
+	}
+	return uvs
+}
+
+// SetTexCoords sets the texture UV coordinates from given list, and flags
+// the mesh as Edited.
+func (ms *MeshBase) SetTexCoords(uvs []mat32.Vec2) {
+	if len(ms.Tex) != 2*len(uvs) {
+		ms.Tex = mat32.NewArrayF32(2*len(uvs), 2*len(uvs))
+	}
+	for i := range uvs {
+		ms.Tex.SetVec2(2*i, uvs[i])
+	}
+	ms.Edited = true
+}
+
+// Indexes returns a copy of the triangle face index buffer (3 sequential
+// vertex indexes per face).
+func (ms *MeshBase) Indexes() []uint32 {
+	idxs := make([]uint32, len(ms.Idx))
+	copy(idxs, ms.Idx)
+	return idxs
+}
+
+// SetIndexes sets the triangle face index buffer from given list, and flags
+// the mesh as Edited -- returns error (logged) if any index is out of range
+// of the current vertices, or the length does not match the Prim primitive
+// type (e.g., a multiple of 3 for PrimTriangles -- see ValidatePrim).
+func (ms *MeshBase) SetIndexes(idxs []uint32) error {
+	if err := ms.ValidatePrim(len(idxs)); err != nil {
+		return err
+	}
+	nv := ms.NumVerts()
+	for i, ix := range idxs {
+		if int(ix) >= nv {
+			err := fmt.Errorf("gi3d.Mesh: %v SetIndexes index %d at position %d out of range of %d vertices", ms.Nm, ix, i, nv)
+			log.Println(err)
+			return err
+		}
+	}
+	if len(ms.Idx) != len(idxs) {
+		ms.Idx = mat32.NewArrayU32(len(idxs), len(idxs))
+	}
+	copy(ms.Idx, idxs)
+	ms.Edited = true
+	return nil
+}
+
+// Face returns the 3 vertex indexes of given triangle face -- returns error
+// (logged) if face index is out of range.
+func (ms *MeshBase) Face(fidx int) (a, b, c uint32, err error) {
+	if fidx < 0 || 3*fidx >= len(ms.Idx) {
+		err = fmt.Errorf("gi3d.Mesh: %v face index %d out of range: %d", ms.Nm, fidx, ms.NumFaces())
+		log.Println(err)
+		return
+	}
+	a, b, c = ms.Idx[3*fidx], ms.Idx[3*fidx+1], ms.Idx[3*fidx+2]
+	return
+}
+
+// SetFace sets the 3 vertex indexes of given triangle face, and flags the
+// mesh as Edited -- returns error (logged) if the face index or any vertex
+// index is out of range.
+func (ms *MeshBase) SetFace(fidx int, a, b, c uint32) error {
+	if fidx < 0 || 3*fidx >= len(ms.Idx) {
+		err := fmt.Errorf("gi3d.Mesh: %v face index %d out of range: %d", ms.Nm, fidx, ms.NumFaces())
+		log.Println(err)
+		return err
+	}
+	nv := ms.NumVerts()
+	if int(a) >= nv || int(b) >= nv || int(c) >= nv {
+		err := fmt.Errorf("gi3d.Mesh: %v SetFace %d indexes %d %d %d out of range of %d vertices", ms.Nm, fidx, a, b, c, nv)
+		log.Println(err)
+		return err
+	}
+	ms.Idx.Set(3*fidx, a, b, c)
+	ms.Edited = true
+	return nil
+}
+
+// ComputeBBox recomputes the bounding box from the current vertex positions
+// -- called automatically by SetVertices.
+func (ms *MeshBase) ComputeBBox() {
+	nv := ms.NumVerts()
+	if nv == 0 {
+		ms.BBox.BBox.SetEmpty()
+		return
+	}
+	bb := mat32.Box3{}
+	bb.SetEmpty()
+	var v mat32.Vec3
+	for i := 0; i < nv; i++ {
+		ms.Vtx.GetVec3(3*i, &v)
+		bb.ExpandByPoint(v)
+	}
+	ms.BBox.SetBounds(bb.Min, bb.Max)
+}
+
+// vtxIdxTry returns an error (logged) if given vertex index is out of range
+func (ms *MeshBase) vtxIdxTry(idx int) error {
+	if idx < 0 || idx >= ms.NumVerts() {
+		err := fmt.Errorf("gi3d.Mesh: %v vertex index %d out of range: %d", ms.Nm, idx, ms.NumVerts())
+		log.Println(err)
+		return err
+	}
+	return nil
+}
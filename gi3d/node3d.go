@@ -269,12 +269,24 @@ func (nb *Node3DBase) RayPick(pos image.Point, sc *Scene) mat32.Ray {
 	ndc := fpos.WindowToNDC(size, mat32.Vec2{}, true) // flipY
 	var err error
 	ndc.Z = -1 // at closest point
-	cdir := mat32.NewVec4FromVec3(ndc, 1).MulMat4(&sc.Camera.InvPrjnMatrix)
-	cdir.Z = -1
-	cdir.W = 0 // vec
-	// get world position / transform of camera: matrix is inverse of ViewMatrix
-	wdir := cdir.MulMat4(&sc.Camera.Pose.Matrix)
-	wpos := sc.Camera.Pose.Matrix.Pos()
+	var wpos mat32.Vec3
+	var wdir mat32.Vec4
+	if sc.Camera.Ortho {
+		// rays are parallel to the view direction, starting from the
+		// unprojected point on the near plane
+		cpos := mat32.NewVec4FromVec3(ndc, 1).MulMat4(&sc.Camera.InvPrjnMatrix)
+		cpos.W = 1
+		wpos4 := cpos.MulMat4(&sc.Camera.Pose.Matrix)
+		wpos = mat32.Vec3{wpos4.X, wpos4.Y, wpos4.Z}
+		wdir = mat32.Vec4{0, 0, -1, 0}.MulMat4(&sc.Camera.Pose.Matrix)
+	} else {
+		cdir := mat32.NewVec4FromVec3(ndc, 1).MulMat4(&sc.Camera.InvPrjnMatrix)
+		cdir.Z = -1
+		cdir.W = 0 // vec
+		// get world position / transform of camera: matrix is inverse of ViewMatrix
+		wdir = cdir.MulMat4(&sc.Camera.Pose.Matrix)
+		wpos = sc.Camera.Pose.Matrix.Pos()
+	}
 	invM, err := nb.Pose.WorldMatrix.Inverse()
 	if err != nil {
 		log.Println(err)
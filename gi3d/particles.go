@@ -0,0 +1,225 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"math/rand"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/mat32"
+	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
+)
+
+// Particles is a Solid rendering a simple CPU-simulated particle system,
+// for effects like smoke, sparks, or swarms of small elements.
+// Each particle is a camera-facing quad (billboard) with its own position,
+// velocity, color, and size, all packed into a single dynamic ParticlesMesh
+// so the whole system renders in one draw call.  Particles are emitted at
+// the node's local origin at EmitRate per second, with initial velocity
+// InitVel plus a uniform random offset within +/- VelSpread per component,
+// and live for Lifetime seconds (+/- LifetimeVar proportion), interpolating
+// from StartColor / StartSize to EndColor / EndSize over their life.
+// Call Step from your app's tick loop (like Scene.AnimStep) to advance the
+// simulation, then Scene.UpdateMeshes and a re-render to display it.
+// A texture (e.g., a smoke sprite) can be set in the Material as usual.
+// Note: the camera-facing quads are computed in the node's local space, so
+// the node should be at top level or in groups without rotations for the
+// billboarding to be accurate (same caveat as Billboard).
+type Particles struct {
+	Solid
+	MaxParticles int        `desc:"maximum number of live particles -- sets the size of the mesh buffers, so changing it after creation requires a re-init of the mesh (e.g., Scene.InitMesh)"`
+	EmitRate     float32    `desc:"number of new particles emitted per second -- set to 0 to stop emitting and let existing particles die off"`
+	Lifetime     float32    `desc:"lifetime of each particle in seconds"`
+	LifetimeVar  float32    `desc:"proportional random variation in lifetime (0-1) -- actual lifetime is Lifetime * (1 +/- LifetimeVar)"`
+	InitVel      mat32.Vec3 `desc:"initial velocity of emitted particles, in local units per second"`
+	VelSpread    mat32.Vec3 `desc:"uniform random variation in initial velocity -- each component is offset within +/- this amount"`
+	Gravity      mat32.Vec3 `desc:"constant acceleration applied to all particles, e.g., gravity or buoyancy, in local units per second^2"`
+	StartColor   gi.Color   `desc:"particle color at emission -- interpolates to EndColor over the particle's life"`
+	EndColor     gi.Color   `desc:"particle color at end of life -- typically transparent so particles fade out"`
+	StartSize    float32    `desc:"quad edge size at emission, in local units -- interpolates to EndSize"`
+	EndSize      float32    `desc:"quad edge size at end of life, in local units"`
+	Data         []Particle `view:"-" desc:"current live particles -- updated by Step"`
+	EmitResid    float32    `view:"-" desc:"residual fractional emission carried over between Step calls"`
+}
+
+var KiT_Particles = kit.Types.AddType(&Particles{}, nil)
+
+// Particle is the state of one live particle in a Particles system.
+type Particle struct {
+	Pos  mat32.Vec3 `desc:"current position in the node's local space"`
+	Vel  mat32.Vec3 `desc:"current velocity in local units per second"`
+	Age  float32    `desc:"age in seconds since emission"`
+	Life float32    `desc:"total lifetime in seconds -- particle dies when Age reaches this"`
+}
+
+// AddNewParticles adds a new particle system of given name to given parent,
+// with given maximum number of particles (256 if <= 0), creating its
+// dynamic mesh (named name + "-mesh") in the scene.
+func AddNewParticles(sc *Scene, parent ki.Ki, name string, maxParticles int) *Particles {
+	ps := parent.AddNewChild(KiT_Particles, name).(*Particles)
+	ps.Defaults()
+	if maxParticles > 0 {
+		ps.MaxParticles = maxParticles
+	}
+	pm := &ParticlesMesh{Part: ps}
+	pm.Nm = name + "-mesh"
+	pm.Dynamic = true
+	pm.Trans = true // colors virtually always fade out -- sort with transparent
+	sc.AddMesh(pm)
+	ps.SetMesh(sc, pm)
+	return ps
+}
+
+func (ps *Particles) Defaults() {
+	ps.Solid.Defaults()
+	ps.MaxParticles = 256
+	ps.EmitRate = 20
+	ps.Lifetime = 2
+	ps.LifetimeVar = 0.25
+	ps.InitVel.Set(0, 1, 0)
+	ps.VelSpread.Set(0.25, 0.25, 0.25)
+	ps.StartColor = gi.Color{255, 255, 255, 255}
+	ps.EndColor = gi.Color{255, 255, 255, 0}
+	ps.StartSize = 0.1
+	ps.EndSize = 0.1
+	ps.Mat.Unlit = true // particle color typically should not depend on lighting
+}
+
+// Step advances the particle simulation by dt seconds: ages and integrates
+// existing particles (removing dead ones) and emits new ones per EmitRate.
+// Returns true if any particles remain live (e.g., to keep ticking after
+// EmitRate is set to 0).  Call Scene.UpdateMeshes and re-render after to
+// display the updated state -- this only updates the CPU-side Data.
+func (ps *Particles) Step(dt float32) bool {
+	for i := len(ps.Data) - 1; i >= 0; i-- {
+		p := &ps.Data[i]
+		p.Age += dt
+		if p.Age >= p.Life {
+			ps.Data[i] = ps.Data[len(ps.Data)-1]
+			ps.Data = ps.Data[:len(ps.Data)-1]
+			continue
+		}
+		p.Vel.SetAdd(ps.Gravity.MulScalar(dt))
+		p.Pos.SetAdd(p.Vel.MulScalar(dt))
+	}
+	ps.EmitResid += ps.EmitRate * dt
+	ne := int(ps.EmitResid)
+	ps.EmitResid -= float32(ne)
+	for ; ne > 0 && len(ps.Data) < ps.MaxParticles; ne-- {
+		ps.Emit()
+	}
+	return len(ps.Data) > 0
+}
+
+// Emit emits one new particle at the local origin, with randomized initial
+// velocity and lifetime per the emission parameters.  Called by Step --
+// can also be called directly, e.g., for an initial burst.
+func (ps *Particles) Emit() {
+	p := Particle{}
+	p.Vel = ps.InitVel.Add(mat32.Vec3{randSpread(ps.VelSpread.X), randSpread(ps.VelSpread.Y), randSpread(ps.VelSpread.Z)})
+	p.Life = ps.Lifetime * (1 + randSpread(ps.LifetimeVar))
+	if p.Life <= 0 {
+		p.Life = 0.01
+	}
+	ps.Data = append(ps.Data, p)
+}
+
+// randSpread returns a uniform random value in +/- sp
+func randSpread(sp float32) float32 {
+	return sp * (2*rand.Float32() - 1)
+}
+
+///////////////////////////////////////////////////////////////////////////
+//  ParticlesMesh
+
+// ParticlesMesh is the dynamic Mesh for a Particles system: one
+// camera-facing quad per particle slot (MaxParticles total), with
+// per-vertex colors, rebuilt from the particle Data on each Update.
+// Dead slots are collapsed to degenerate (zero-area) quads so the buffer
+// length never changes.
+type ParticlesMesh struct {
+	MeshBase
+	Part *Particles `desc:"the particle system that this mesh renders"`
+}
+
+var KiT_ParticlesMesh = kit.Types.AddType(&ParticlesMesh{}, nil)
+
+func (pm *ParticlesMesh) Make(sc *Scene) {
+	n := pm.Part.MaxParticles
+	pm.Alloc(n*4, n*6, true)
+	tex := mat32.Vec2{}
+	texs := [4]mat32.Vec2{{0, 1}, {1, 1}, {1, 0}, {0, 0}} // bl, br, tr, tl
+	for i := 0; i < n; i++ {
+		vi := i * 4
+		for j := 0; j < 4; j++ {
+			tex = texs[j]
+			tex.ToArray(pm.Tex, (vi+j)*2)
+		}
+		pm.Idx.Set(i*6, uint32(vi), uint32(vi+1), uint32(vi+2),
+			uint32(vi), uint32(vi+2), uint32(vi+3))
+	}
+	pm.SetFromParticles(sc)
+}
+
+// SetFromParticles sets the quad vertex, normal, and color data from the
+// current particle Data, facing the camera.  Does not touch the GPU --
+// called by Make and Update.
+func (pm *ParticlesMesh) SetFromParticles(sc *Scene) {
+	ps := pm.Part
+	n := ps.MaxParticles
+	right := mat32.Vec3{X: 1}.MulQuat(sc.Camera.Pose.Quat)
+	up := mat32.Vec3{Y: 1}.MulQuat(sc.Camera.Pose.Quat)
+	norm := mat32.Vec3{Z: 1}.MulQuat(sc.Camera.Pose.Quat) // toward camera
+	bb := mat32.Box3{}
+	bb.SetEmpty()
+	bb.ExpandByPoint(mat32.Vec3{}) // always include the emitter origin
+	for i := 0; i < n; i++ {
+		vi := i * 12
+		ci := i * 16
+		if i >= len(ps.Data) { // dead slot: degenerate quad, renders nothing
+			for j := 0; j < 12; j++ {
+				pm.Vtx[vi+j] = 0
+			}
+			continue
+		}
+		p := &ps.Data[i]
+		amt := float32(0)
+		if p.Life > 0 {
+			amt = p.Age / p.Life
+		}
+		clr := ps.StartColor.Blend(100*amt, ps.EndColor)
+		clrv := ColorToVec4f(clr)
+		hs := 0.5 * (ps.StartSize + (ps.EndSize-ps.StartSize)*amt)
+		r := right.MulScalar(hs)
+		u := up.MulScalar(hs)
+		bl := p.Pos.Sub(r).Sub(u)
+		br := p.Pos.Add(r).Sub(u)
+		tr := p.Pos.Add(r).Add(u)
+		tl := p.Pos.Sub(r).Add(u)
+		bl.ToArray(pm.Vtx, vi)
+		br.ToArray(pm.Vtx, vi+3)
+		tr.ToArray(pm.Vtx, vi+6)
+		tl.ToArray(pm.Vtx, vi+9)
+		for j := 0; j < 4; j++ {
+			norm.ToArray(pm.Norm, vi+j*3)
+			clrv.ToArray(pm.Color, ci+j*4)
+		}
+		bb.ExpandByPoints([]mat32.Vec3{bl, tr})
+	}
+	pm.BBox.SetBounds(bb.Min, bb.Max)
+}
+
+func (pm *ParticlesMesh) Update(sc *Scene) {
+	if pm.Buff == nil { // not yet initialized
+		return
+	}
+	pm.SetFromParticles(sc)
+	pm.SetVtxData(sc)
+	pm.SetNormData(sc)
+	pm.SetColorData(sc)
+	pm.Activate(sc)
+	pm.TransferVectors()
+}
@@ -5,6 +5,8 @@
 package gi3d
 
 import (
+	"log"
+
 	"github.com/goki/gi/mat32"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
@@ -160,11 +162,33 @@ func (ps *Pose) SetMatrix(m *mat32.Mat4) {
 	ps.Pos, ps.Quat, ps.Scale = ps.Matrix.Decompose()
 }
 
-// LookAt points the element at given target location using given up direction.
+// LookAt points the element at given target location using given up direction,
+// so that the element's forward axis (negative Z, as for the camera) points
+// at the target.  The coordinates are relative to the parent (as for Pos) --
+// see LookAtWorld for orienting at a world-coordinate target from within a
+// transformed group.
 func (ps *Pose) LookAt(target, upDir mat32.Vec3) {
 	ps.Quat.SetFromRotationMatrix(mat32.NewLookAt(ps.Pos, target, upDir))
 }
 
+// LookAtWorld points the element at given target location in world
+// coordinates, using given up direction (also world coordinates), composing
+// correctly with the parent transform by converting into parent-relative
+// coordinates first -- useful for turrets, spotlights, labels etc that must
+// track a world-space point.  The cached parent world matrix must be up to
+// date (see UpdateWorldMatrix -- done during scene updates).
+func (ps *Pose) LookAtWorld(target, upDir mat32.Vec3) {
+	ip, err := ps.ParMatrix.Inverse()
+	if err != nil {
+		log.Println(err)
+		ps.LookAt(target, upDir)
+		return
+	}
+	ltarg := target.MulMat4AsVec4(ip, 1)
+	lup := upDir.MulMat4AsVec4(ip, 0).Normal()
+	ps.Quat.SetFromRotationMatrix(mat32.NewLookAt(ps.Pos, ltarg, lup))
+}
+
 ///////////////////////////////////////////////////////
 // 		World values
 
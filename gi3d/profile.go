@@ -0,0 +1,217 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"github.com/chewxy/math32"
+	"github.com/goki/gi/mat32"
+	"github.com/goki/ki/kit"
+)
+
+// Revolve is a mesh defined by lathing a 2D profile around the Y axis.
+// Profile points are in the XY plane, with X = radius and Y = height,
+// and should proceed from bottom to top for outward-facing normals.
+type Revolve struct {
+	MeshBase
+	Profile  []mat32.Vec2 `desc:"2D profile points that are revolved around the Y axis: X = radius, Y = height"`
+	Segs     int          `min:"1" desc:"number of segments around the Y axis (32 is reasonable default for a smooth surface)"`
+	AngStart float32      `min:"0" max:"360" step:"5" desc:"starting angle in degrees relative to 1,0,0 starting point"`
+	AngLen   float32      `min:"0" max:"360" step:"5" desc:"total angle to generate in degrees (max = 360)"`
+}
+
+var KiT_Revolve = kit.Types.AddType(&Revolve{}, nil)
+
+// AddNewRevolve creates a revolved (lathed) mesh from given 2D profile
+// (X = radius, Y = height) rotated around the Y axis, with given number
+// of segments (resolution).
+func AddNewRevolve(sc *Scene, name string, profile []mat32.Vec2, segs int) *Revolve {
+	rv := &Revolve{}
+	rv.Nm = name
+	rv.Profile = profile
+	rv.Segs = segs
+	rv.AngStart = 0
+	rv.AngLen = 360
+	sc.AddMesh(rv)
+	return rv
+}
+
+func (rv *Revolve) Make(sc *Scene) {
+	rv.Reset()
+	rv.AddRevolveSector(rv.Profile, rv.Segs, rv.AngStart, rv.AngLen, mat32.Vec3{})
+	rv.BBox.UpdateFmBBox()
+}
+
+// Extrude is a mesh defined by sweeping a 2D profile along a 3D path.
+// The profile is positioned in the plane perpendicular to the path at
+// each path point, and should proceed counter-clockwise for
+// outward-facing normals.
+type Extrude struct {
+	MeshBase
+	Profile []mat32.Vec2 `desc:"2D profile points that are swept along the path, in the plane perpendicular to it"`
+	Path    []mat32.Vec3 `desc:"3D path points that the profile is swept along"`
+}
+
+var KiT_Extrude = kit.Types.AddType(&Extrude{}, nil)
+
+// AddNewExtrude creates an extruded mesh from given 2D profile swept along
+// given 3D path.
+func AddNewExtrude(sc *Scene, name string, profile []mat32.Vec2, path []mat32.Vec3) *Extrude {
+	ex := &Extrude{}
+	ex.Nm = name
+	ex.Profile = profile
+	ex.Path = path
+	sc.AddMesh(ex)
+	return ex
+}
+
+func (ex *Extrude) Make(sc *Scene) {
+	ex.Reset()
+	ex.AddExtrude(ex.Profile, ex.Path, mat32.Vec3{})
+	ex.BBox.UpdateFmBBox()
+}
+
+// ProfileNorms returns the per-point 2D normals of given profile polyline,
+// averaging the normals of adjacent segments at interior points.
+func ProfileNorms(profile []mat32.Vec2) []mat32.Vec2 {
+	np := len(profile)
+	nrms := make([]mat32.Vec2, np)
+	for i := 0; i < np-1; i++ {
+		d := profile[i+1].Sub(profile[i])
+		sn := mat32.Vec2{d.Y, -d.X}.Normal()
+		nrms[i] = nrms[i].Add(sn)
+		nrms[i+1] = nrms[i+1].Add(sn)
+	}
+	for i := range nrms {
+		nrms[i] = nrms[i].Normal()
+	}
+	return nrms
+}
+
+// AddRevolveSector adds vertex data for given 2D profile (X = radius,
+// Y = height) revolved around the Y axis, with given number of segments,
+// start angle and length in degrees (0 - 360), and center offset.
+func (ms *MeshBase) AddRevolveSector(profile []mat32.Vec2, segs int, angStart, angLen float32, offset mat32.Vec3) {
+	np := len(profile)
+	if np < 2 || segs < 1 {
+		return
+	}
+	angStRad := mat32.DegToRad(angStart)
+	angLenRad := mat32.DegToRad(angLen)
+
+	pos := mat32.NewArrayF32(0, 0)
+	norms := mat32.NewArrayF32(0, 0)
+	uvs := mat32.NewArrayF32(0, 0)
+	idxs := mat32.NewArrayU32(0, 0)
+	stidx := uint32(ms.Vtx.Len() / 3)
+
+	bb := mat32.Box3{}
+	bb.SetEmpty()
+
+	pnrm := ProfileNorms(profile)
+	for j := 0; j <= segs; j++ {
+		u := angStRad + float32(j)/float32(segs)*angLenRad
+		cu := math32.Cos(u)
+		su := math32.Sin(u)
+		for i, p := range profile {
+			var pt mat32.Vec3
+			pt.X = p.X * cu
+			pt.Y = p.Y
+			pt.Z = p.X * su
+			pt.SetAdd(offset)
+			pos.AppendVec3(pt)
+			bb.ExpandByPoint(pt)
+
+			uvs.Append(float32(j)/float32(segs), float32(i)/float32(np-1))
+			norms.AppendVec3(mat32.Vec3{pnrm[i].X * cu, pnrm[i].Y, pnrm[i].X * su})
+		}
+	}
+
+	for j := 1; j <= segs; j++ {
+		for i := 1; i < np; i++ {
+			a := np*j + i - 1
+			b := np*(j-1) + i - 1
+			c := np*(j-1) + i
+			d := np*j + i
+			idxs.Append(stidx+uint32(a), stidx+uint32(b), stidx+uint32(d), stidx+uint32(b), stidx+uint32(c), stidx+uint32(d))
+		}
+	}
+
+	ms.Vtx = append(ms.Vtx, pos...)
+	ms.Idx = append(ms.Idx, idxs...)
+	ms.Norm = append(ms.Norm, norms...)
+	ms.Tex = append(ms.Tex, uvs...)
+
+	ms.BBox.BBox.Expand(bb)
+}
+
+// AddExtrude adds vertex data for given 2D profile swept along given 3D
+// path, with given center offset.  The profile lies in the plane
+// perpendicular to the path at each point, using a sliding frame that
+// minimizes twist between successive path points.
+func (ms *MeshBase) AddExtrude(profile []mat32.Vec2, path []mat32.Vec3, offset mat32.Vec3) {
+	np := len(profile)
+	npth := len(path)
+	if np < 2 || npth < 2 {
+		return
+	}
+	pos := mat32.NewArrayF32(0, 0)
+	norms := mat32.NewArrayF32(0, 0)
+	uvs := mat32.NewArrayF32(0, 0)
+	idxs := mat32.NewArrayU32(0, 0)
+	stidx := uint32(ms.Vtx.Len() / 3)
+
+	bb := mat32.Box3{}
+	bb.SetEmpty()
+
+	tans := make([]mat32.Vec3, npth)
+	for j := range path {
+		switch {
+		case j == 0:
+			tans[j] = path[1].Sub(path[0])
+		case j == npth-1:
+			tans[j] = path[j].Sub(path[j-1])
+		default:
+			tans[j] = path[j+1].Sub(path[j-1])
+		}
+		tans[j] = tans[j].Normal()
+	}
+
+	pnrm := ProfileNorms(profile)
+	up := mat32.Vec3{0, 1, 0}
+	if math32.Abs(tans[0].Y) > 0.99 { // path starts along Y -- need a different up
+		up = mat32.Vec3{1, 0, 0}
+	}
+	for j, pp := range path {
+		tan := tans[j]
+		right := up.Cross(tan).Normal()
+		up = tan.Cross(right).Normal() // carry forward to minimize twist
+		for i, p := range profile {
+			pt := pp.Add(right.MulScalar(p.X)).Add(up.MulScalar(p.Y))
+			pt.SetAdd(offset)
+			pos.AppendVec3(pt)
+			bb.ExpandByPoint(pt)
+
+			uvs.Append(float32(i)/float32(np-1), float32(j)/float32(npth-1))
+			norms.AppendVec3(right.MulScalar(pnrm[i].X).Add(up.MulScalar(pnrm[i].Y)))
+		}
+	}
+
+	for j := 1; j < npth; j++ {
+		for i := 1; i < np; i++ {
+			a := np*j + i - 1
+			b := np*(j-1) + i - 1
+			c := np*(j-1) + i
+			d := np*j + i
+			idxs.Append(stidx+uint32(a), stidx+uint32(b), stidx+uint32(d), stidx+uint32(b), stidx+uint32(c), stidx+uint32(d))
+		}
+	}
+
+	ms.Vtx = append(ms.Vtx, pos...)
+	ms.Idx = append(ms.Idx, idxs...)
+	ms.Norm = append(ms.Norm, norms...)
+	ms.Tex = append(ms.Tex, uvs...)
+
+	ms.BBox.BBox.Expand(bb)
+}
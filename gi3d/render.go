@@ -12,6 +12,7 @@ import (
 	"github.com/goki/gi/mat32"
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/oswin/gpu"
+	"github.com/goki/ki/kit"
 )
 
 // https://learnopengl.com/Lighting/Basic-Lighting
@@ -27,6 +28,9 @@ const (
 	InVtxNorm
 	InVtxTex
 	InVtxColor
+	InVtxTex2
+	InVtxJoints
+	InVtxWeights
 	RenderInputsN
 )
 
@@ -44,21 +48,118 @@ const (
 	RenderClassesN
 )
 
+// RenderLayers define explicit rendering layers for Solids, rendered in
+// the order: Background, Default, ViewModel, Overlay (see RenderLayerOrder)
+// -- within each layer, the standard opaque / transparent RenderClasses
+// sorting applies.  Background and Overlay are rendered with depth testing
+// disabled, so Background content (e.g., a skybox) is always behind the
+// Default scene content, and Overlay content (e.g., HUD elements) is always
+// on top, regardless of position.  ViewModel is rendered after the Default
+// content with the depth buffer cleared and a separate, tighter near plane
+// (Camera.ViewModelNear), so first-person objects (weapons, tools) draw over
+// the scene and never clip through the main near plane, while still
+// depth-testing against themselves.
+type RenderLayers int32
+
+const (
+	// LayerDefault is the normal scene content -- the default (zero) layer.
+	LayerDefault RenderLayers = iota
+
+	// LayerBackground is rendered first, with depth testing disabled,
+	// so all other content renders in front of it (e.g., a skybox).
+	LayerBackground
+
+	// LayerOverlay is rendered last, with depth testing disabled, so it
+	// is always visible on top of the rest of the scene (e.g., HUD elements).
+	LayerOverlay
+
+	// LayerViewModel is rendered after the Default content, with the depth
+	// buffer cleared and projected using the tighter Camera.ViewModelNear
+	// near plane, for first-person objects (weapons, tools) that must never
+	// clip through the main near plane -- typically used on solids in a
+	// TrackCamera group.
+	LayerViewModel
+
+	RenderLayersN
+)
+
+//go:generate stringer -type=RenderLayers
+
+var KiT_RenderLayers = kit.Enums.AddEnum(RenderLayersN, kit.NotBitFlag, nil)
+
+// RenderLayerOrder is the order in which the RenderLayers are rendered.
+var RenderLayerOrder = [RenderLayersN]RenderLayers{LayerBackground, LayerDefault, LayerViewModel, LayerOverlay}
+
+// OpaqueSorts are the draw-order options for opaque solids within a render
+// layer -- see Scene.OpaqueSort.  Transparent solids are always sorted
+// back-to-front, which is required for correct alpha compositing.
+type OpaqueSorts int32
+
+const (
+	// OpaqueSortFrontToBack sorts opaque solids nearest-first (by NDC
+	// bounding box), so occluded fragments are rejected by the early depth
+	// test instead of being shaded -- the default, and a significant win in
+	// fill-rate-bound scenes with heavy overdraw (e.g., a camera looking
+	// down a row of large overlapping solids shades each covered pixel only
+	// ~once instead of once per solid).  Costs a sort per render class.
+	OpaqueSortFrontToBack OpaqueSorts = iota
+
+	// OpaqueSortNone draws opaque solids in scene-tree traversal order --
+	// skips the per-frame sort, for scenes with little overdraw or where a
+	// specific draw order is desired.  Rendering remains correct either way
+	// because opaque solids depth-test against each other.
+	OpaqueSortNone
+
+	OpaqueSortsN
+)
+
+//go:generate stringer -type=OpaqueSorts
+
+var KiT_OpaqueSorts = kit.Enums.AddEnum(OpaqueSortsN, kit.NotBitFlag, nil)
+
 // Renderers is the container for all GPU rendering Programs
 // Each scene requires its own version of these because
 // the programs need to be recompiled for each specific set
 // of lights.
 type Renderers struct {
-	Unis    map[string]gpu.Uniforms `desc:"uniforms shared across code"`
-	Vectors []gpu.Vectors           `desc:"input vectors shared across code, indexed by RenderInputs"`
-	Renders map[string]Render       `desc:"collection of Render items"`
-	NLights int                     `view:"-" desc:"the number of lights when the rendering programs were last compiled -- need to recompile when number of lights change"`
+	Unis     map[string]gpu.Uniforms `desc:"uniforms shared across code"`
+	Vectors  []gpu.Vectors           `desc:"input vectors shared across code, indexed by RenderInputs"`
+	Renders  map[string]Render       `desc:"collection of Render items"`
+	NLights  [4]int                  `view:"-" desc:"the number of lights of each type (ambient, dir, point, spot) when the rendering programs were last compiled -- need to recompile when any of these change, because the uniform array lengths are baked into the shader source"`
+	EnvIrrad [3]mat32.Mat4           `view:"-" desc:"view-space environment irradiance matrices for image-based ambient diffuse lighting, one per color channel -- computed per-frame by SetEnvIrrad from Scene.Env"`
+	EnvOn    bool                    `view:"-" desc:"environment irradiance lighting is active -- set by SetEnvIrrad from Scene.Env"`
+}
+
+// LightCounts returns the number of lights of each type (ambient, dir,
+// point, spot) currently in the scene.
+func LightCounts(sc *Scene) [4]int {
+	var n [4]int
+	for _, lt := range sc.Lights {
+		switch lt.(type) {
+		case *AmbientLight:
+			n[0]++
+		case *DirLight:
+			n[1]++
+		case *PointLight:
+			n[2]++
+		case *SpotLight:
+			n[3]++
+		}
+	}
+	return n
 }
 
-// SetLights sets the lights and recompiles the programs accordingly
+// SetLights sets the lights and recompiles the programs if the number of
+// lights of any type has changed since the last compile, so lights can be
+// added and removed dynamically while the scene is running (e.g., via
+// AddNewPointLight, Scene.DeleteLight).  There is no hard-coded maximum
+// number of lights -- the uniform arrays are sized to the current counts --
+// but the GPU's maximum uniform block size (minimum 16kb in OpenGL 3.3)
+// imposes a practical limit on the order of 100 lights.
 // Must be called with proper context activated
 func (rn *Renderers) SetLights(sc *Scene) {
-	if rn.NLights == len(sc.Lights) {
+	nl := LightCounts(sc)
+	if rn.NLights == nl {
 		return
 	}
 	oswin.TheApp.RunOnMain(func() {
@@ -67,7 +168,21 @@ func (rn *Renderers) SetLights(sc *Scene) {
 			rd.Compile(rn)
 		}
 	})
-	rn.NLights = len(sc.Lights)
+	rn.NLights = nl
+}
+
+// SetEnvIrrad computes the view-space environment irradiance matrices from
+// the scene Env environment map and current camera rotation, for the
+// image-based ambient diffuse lighting in the standard solid shaders (see
+// RenderBase.SetEnv) -- call once per frame, before rendering -- a nil Env
+// turns environment lighting off.
+func (rn *Renderers) SetEnvIrrad(sc *Scene) {
+	if sc.Env == nil {
+		rn.EnvOn = false
+		return
+	}
+	rn.EnvIrrad = sc.Env.IrradianceMats(sc.Camera.Pose.Quat)
+	rn.EnvOn = true
 }
 
 // SetMatrix sets the view etc matrix uniforms
@@ -84,6 +199,25 @@ func (rn *Renderers) SetMatrix(pose *Pose) {
 	// fmt.Printf("mv matrix:\n%v\nnorm matrix:\n%v\n", pose.MVMatrix, pose.NormMatrix)
 }
 
+// SetClipPlane sets the user-defined clipping plane uniform from the scene
+// ClipPlane (world coordinates, transformed here to camera coordinates)
+// and enables / disables GL_CLIP_DISTANCE0 per scene ClipPlaneOn.
+// Must be called with appropriate context (window) activated and already on main.
+func (rn *Renderers) SetClipPlane(sc *Scene) {
+	gpu.Draw.ClipPlane(sc.ClipPlaneOn)
+	if !sc.ClipPlaneOn {
+		return
+	}
+	// plane transforms by the inverse transpose of the view matrix,
+	// and the view matrix is the inverse of Pose.Matrix
+	trn := sc.Camera.Pose.Matrix.Transpose()
+	cpl := sc.ClipPlane.MulMat4(trn)
+	cu := rn.Unis["Camera"]
+	cu.Activate()
+	cpu := cu.UniformByName("ClipPlane")
+	cpu.SetValue(cpl)
+}
+
 // Init initializes the Render programs.
 // Must be called with appropriate context (window) activated.
 // Returns true if wasn't already initialized, and error
@@ -114,6 +248,9 @@ func (rn *Renderers) InitVectors() {
 	rn.Vectors[InVtxNorm] = gpu.TheGPU.NewInputVectors("InVtxNorm", int(InVtxNorm), gpu.Vec3fVecType, gpu.VertexNormal)
 	rn.Vectors[InVtxTex] = gpu.TheGPU.NewInputVectors("InVtxTex", int(InVtxTex), gpu.Vec2fVecType, gpu.VertexTexcoord)
 	rn.Vectors[InVtxColor] = gpu.TheGPU.NewInputVectors("InVtxColor", int(InVtxColor), gpu.Vec4fVecType, gpu.VertexColor)
+	rn.Vectors[InVtxTex2] = gpu.TheGPU.NewInputVectors("InVtxTex2", int(InVtxTex2), gpu.Vec2fVecType, gpu.VertexTexcoord)
+	rn.Vectors[InVtxJoints] = gpu.TheGPU.NewInputVectors("InVtxJoints", int(InVtxJoints), gpu.Vec4fVecType, gpu.SkinIndex)
+	rn.Vectors[InVtxWeights] = gpu.TheGPU.NewInputVectors("InVtxWeights", int(InVtxWeights), gpu.Vec4fVecType, gpu.SkinWeight)
 }
 
 func (rn *Renderers) InitUnis() error {
@@ -123,6 +260,7 @@ func (rn *Renderers) InitUnis() error {
 	camera.AddUniform("MVMatrix", gpu.Mat4fUniType, false, 0)
 	camera.AddUniform("MVPMatrix", gpu.Mat4fUniType, false, 0)
 	camera.AddUniform("NormMatrix", gpu.Mat3fUniType, false, 0)
+	camera.AddUniform("ClipPlane", gpu.Vec4fUniType, false, 0)
 	camera.Activate()
 	gpu.TheGPU.ErrCheck("camera unis activate")
 	rn.Unis[camera.Name()] = camera
@@ -144,6 +282,12 @@ func (rn *Renderers) InitRenders() error {
 	rn.AddNewRender(&RenderUniformColor{}, &errs)
 	rn.AddNewRender(&RenderVertexColor{}, &errs)
 	rn.AddNewRender(&RenderTexture{}, &errs)
+	rn.AddNewRender(&RenderFXAA{}, &errs)
+	rn.AddNewRender(&RenderDepthNorm{}, &errs)
+	rn.AddNewRender(&RenderSSAO{}, &errs)
+	rn.AddNewRender(&RenderSSAOBlur{}, &errs)
+	rn.AddNewRender(&RenderToneMap{}, &errs)
+	rn.AddNewRender(&RenderBackground{}, &errs)
 
 	var erstr string
 	for _, er := range errs {
@@ -271,6 +415,42 @@ func (rb *RenderBase) Delete(rn *Renderers) {
 	rb.Pipe.Delete()
 }
 
+// SetSkin sets the skinning uniforms from given skeleton for rendering a
+// skinned (rigged) mesh -- nil turns skinning off.  The program must
+// already be activated.  Only valid for the standard solid renderers
+// (uniform / vertex color, texture), which have the skinning uniforms.
+func (rb *RenderBase) SetSkin(skel *Skeleton) {
+	pr := rb.VtxFragProg()
+	usu := pr.UniformByName("UseSkin")
+	if skel == nil {
+		usu.SetValue(false)
+		return
+	}
+	if len(skel.SkinMats) != MaxSkinJoints {
+		skel.UpdateMats()
+	}
+	usu.SetValue(true)
+	smu := pr.UniformByName("SkinMats")
+	smu.SetValue(skel.SkinMats)
+}
+
+// SetEnv sets the environment irradiance uniforms for image-based ambient
+// diffuse lighting, from the per-frame view-space matrices computed by
+// Renderers.SetEnvIrrad.  The program must already be activated.  Only
+// valid for the standard solid renderers (uniform / vertex color, texture),
+// which have the environment uniforms.
+func (rb *RenderBase) SetEnv(rn *Renderers) {
+	pr := rb.VtxFragProg()
+	ueu := pr.UniformByName("UseEnv")
+	if !rn.EnvOn {
+		ueu.SetValue(false)
+		return
+	}
+	ueu.SetValue(true)
+	eiu := pr.UniformByName("EnvIrrad")
+	eiu.SetValue(rn.EnvIrrad[:])
+}
+
 //////////////////////////////////////////////////////////////////////////
 //    RenderUniformColor
 
@@ -289,7 +469,7 @@ func (rb *RenderUniformColor) Init(rn *Renderers) error {
 	}
 	pl := rb.Pipe
 	pr := pl.ProgramByName("VtxFrag")
-	_, err := pr.AddShader(gpu.VertexShader, "Vtx", RenderUniCamera+
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx", RenderUniCamera+RenderSkin+
 		`
 layout(location = 0) in vec3 VtxPos;
 layout(location = 1) in vec3 VtxNorm;
@@ -301,10 +481,13 @@ out vec3 CamDir;
 
 void main() {
 	vec4 vPos = vec4(VtxPos, 1.0);
+	vec3 vNorm = VtxNorm;
+	skinPosNorm(vPos, vNorm);
 	Pos = MVMatrix * vPos;
-	Norm = normalize(NormMatrix * VtxNorm);
+	Norm = normalize(NormMatrix * vNorm);
 	CamDir = normalize(-Pos.xyz);
-	
+	gl_ClipDistance[0] = dot(Pos.xyz, ClipPlane.xyz) + ClipPlane.w;
+
 	gl_Position = MVPMatrix * vPos;
 }
 `+"\x00")
@@ -322,6 +505,7 @@ uniform vec3 Emissive;
 uniform vec3 Specular;
 uniform float Shiny;
 uniform float Bright;
+uniform bool Unlit;
 in vec4 Pos;
 in vec3 Norm;
 in vec3 CamDir;
@@ -335,7 +519,12 @@ void main() {
 	
 	// Calculates the Ambient+Diffuse and Specular colors for this fragment using the Phong model.
 	vec3 Ambdiff, Spec;
-	phongModel(Pos, Norm, CamDir, clr, clr, Specular, Shiny, Ambdiff, Spec);
+	if (Unlit) { // flat color, ignoring all lighting
+		Ambdiff = clr + Emissive;
+		Spec = vec3(0.0);
+	} else {
+		phongModel(Pos, Norm, CamDir, clr, clr, Specular, Shiny, Ambdiff, Spec);
+	}
 
 	// Final fragment color
 	outputColor = min(vec4(Bright * Ambdiff + Spec, opacity), vec4(1.0));
@@ -353,12 +542,36 @@ void main() {
 	pr.AddUniform("Specular", gpu.Vec3fUniType, false, 0)
 	pr.AddUniform("Shiny", gpu.FUniType, false, 0)
 	pr.AddUniform("Bright", gpu.FUniType, false, 0)
+	pr.AddUniform("Unlit", gpu.BUniType, false, 0)
+	pr.AddUniform("SkinMats", gpu.Mat4fUniType, true, MaxSkinJoints)
+	pr.AddUniform("UseSkin", gpu.BUniType, false, 0)
+	pr.AddUniform("EnvIrrad", gpu.Mat4fUniType, true, 3)
+	pr.AddUniform("UseEnv", gpu.BUniType, false, 0)
 
 	pr.SetFragDataVar("outputColor")
 
 	return nil
 }
 
+// SetColor sets just the color uniforms for rendering a flat uniform color
+// (e.g., the solid outline pass) -- Unlit is set so the result is not
+// affected by lighting.
+func (rb *RenderUniformColor) SetColor(clr gi.Color) {
+	pr := rb.VtxFragProg()
+	clru := pr.UniformByName("Color")
+	clru.SetValue(ColorToVec4f(clr))
+	emsu := pr.UniformByName("Emissive")
+	emsu.SetValue(mat32.Vec3{})
+	spcu := pr.UniformByName("Specular")
+	spcu.SetValue(mat32.Vec3{})
+	shu := pr.UniformByName("Shiny")
+	shu.SetValue(float32(1))
+	btu := pr.UniformByName("Bright")
+	btu.SetValue(float32(1))
+	unu := pr.UniformByName("Unlit")
+	unu.SetValue(true)
+}
+
 func (rb *RenderUniformColor) SetMat(mat *Material, sc *Scene) error {
 	pr := rb.VtxFragProg()
 	clru := pr.UniformByName("Color")
@@ -374,7 +587,11 @@ func (rb *RenderUniformColor) SetMat(mat *Material, sc *Scene) error {
 	shu.SetValue(mat.Shiny)
 	btu := pr.UniformByName("Bright")
 	btu.SetValue(mat.Bright)
+	unu := pr.UniformByName("Unlit")
+	unu.SetValue(mat.Unlit)
 	gpu.Draw.CullFace(mat.CullFront, mat.CullBack, true) // back face culling, std CCW ordering
+	gpu.Draw.PolygonOffset(mat.PolyOffset.Factor, mat.PolyOffset.Units)
+	gpu.Draw.DepthFunc(mat.DepthFunc)
 	return nil
 }
 
@@ -396,7 +613,7 @@ func (rb *RenderVertexColor) Init(rn *Renderers) error {
 	}
 	pl := rb.Pipe
 	pr := pl.ProgramByName("VtxFrag")
-	_, err := pr.AddShader(gpu.VertexShader, "Vtx", RenderUniCamera+
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx", RenderUniCamera+RenderSkin+
 		`
 layout(location = 0) in vec3 VtxPos;
 layout(location = 1) in vec3 VtxNorm;
@@ -409,11 +626,14 @@ out vec4 Color;
 
 void main() {
 	vec4 vPos = vec4(VtxPos, 1.0);
+	vec3 vNorm = VtxNorm;
+	skinPosNorm(vPos, vNorm);
 	Pos = MVMatrix * vPos;
-	Norm = normalize(NormMatrix * VtxNorm);
+	Norm = normalize(NormMatrix * vNorm);
 	CamDir = normalize(-Pos.xyz);
 	Color = VtxColor;
-	
+	gl_ClipDistance[0] = dot(Pos.xyz, ClipPlane.xyz) + ClipPlane.w;
+
 	gl_Position = MVPMatrix * vPos;
 }
 `+"\x00")
@@ -430,6 +650,7 @@ uniform vec3 Emissive;
 uniform vec3 Specular;
 uniform float Shiny;
 uniform float Bright;
+uniform bool Unlit;
 in vec4 Pos;
 in vec3 Norm;
 in vec3 CamDir;
@@ -444,7 +665,12 @@ void main() {
 	
 	// Calculates the Ambient+Diffuse and Specular colors for this fragment using the Phong model.
 	vec3 Ambdiff, Spec;
-	phongModel(Pos, Norm, CamDir, clr, clr, Specular, Shiny, Ambdiff, Spec);
+	if (Unlit) { // flat color, ignoring all lighting
+		Ambdiff = clr + Emissive;
+		Spec = vec3(0.0);
+	} else {
+		phongModel(Pos, Norm, CamDir, clr, clr, Specular, Shiny, Ambdiff, Spec);
+	}
 
 	// Final fragment color
 	outputColor = min(vec4(Bright * Ambdiff + Spec, opacity), vec4(1.0));
@@ -460,6 +686,11 @@ void main() {
 	pr.AddUniform("Specular", gpu.Vec3fUniType, false, 0)
 	pr.AddUniform("Shiny", gpu.FUniType, false, 0)
 	pr.AddUniform("Bright", gpu.FUniType, false, 0)
+	pr.AddUniform("Unlit", gpu.BUniType, false, 0)
+	pr.AddUniform("SkinMats", gpu.Mat4fUniType, true, MaxSkinJoints)
+	pr.AddUniform("UseSkin", gpu.BUniType, false, 0)
+	pr.AddUniform("EnvIrrad", gpu.Mat4fUniType, true, 3)
+	pr.AddUniform("UseEnv", gpu.BUniType, false, 0)
 
 	pr.SetFragDataVar("outputColor")
 
@@ -468,6 +699,8 @@ void main() {
 
 func (rb *RenderVertexColor) SetMat(mat *Material, sc *Scene) error {
 	gpu.Draw.CullFace(mat.CullFront, mat.CullBack, true) // back face culling, std CCW ordering
+	gpu.Draw.PolygonOffset(mat.PolyOffset.Factor, mat.PolyOffset.Units)
+	gpu.Draw.DepthFunc(mat.DepthFunc)
 	pr := rb.VtxFragProg()
 	emsu := pr.UniformByName("Emissive")
 	emsv := ColorToVec3f(mat.Emissive)
@@ -479,6 +712,8 @@ func (rb *RenderVertexColor) SetMat(mat *Material, sc *Scene) error {
 	shu.SetValue(mat.Shiny)
 	btu := pr.UniformByName("Bright")
 	btu.SetValue(mat.Bright)
+	unu := pr.UniformByName("Unlit")
+	unu.SetValue(mat.Unlit)
 	return nil
 }
 
@@ -498,28 +733,35 @@ func (rb *RenderTexture) Init(rn *Renderers) error {
 	}
 	pl := rb.Pipe
 	pr := pl.ProgramByName("VtxFrag")
-	_, err := pr.AddShader(gpu.VertexShader, "Vtx", RenderUniCamera+
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx", RenderUniCamera+RenderSkin+
 		`
 layout(location = 0) in vec3 VtxPos;
 layout(location = 1) in vec3 VtxNorm;
 layout(location = 2) in vec2 VtxTex;
 // layout(location = 3) in vec4 VtxColor;
+layout(location = 4) in vec2 VtxTex2;
 uniform bool FlipY;
 out vec4 Pos;
 out vec3 Norm;
 out vec3 CamDir;
 out vec2 TexCoord;
+out vec2 TexCoord2;
 
 void main() {
 	vec4 vPos = vec4(VtxPos, 1.0);
+	vec3 vNorm = VtxNorm;
+	skinPosNorm(vPos, vNorm);
 	Pos = MVMatrix * vPos;
-	Norm = normalize(NormMatrix * VtxNorm);
+	Norm = normalize(NormMatrix * vNorm);
 	CamDir = normalize(-Pos.xyz);
 	TexCoord = VtxTex;
+	TexCoord2 = VtxTex2;
 	if(FlipY) {
 		TexCoord.y = 1 - TexCoord.y;
+		TexCoord2.y = 1 - TexCoord2.y;
 	}
-	
+	gl_ClipDistance[0] = dot(Pos.xyz, ClipPlane.xyz) + ClipPlane.w;
+
 	gl_Position = MVPMatrix * vPos;
 }
 `+"\x00")
@@ -536,25 +778,42 @@ uniform vec3 Emissive;
 uniform vec3 Specular;
 uniform float Shiny;
 uniform float Bright;
+uniform bool Unlit;
 uniform sampler2D Tex;
 uniform vec2 TexRepeat;
 uniform vec2 TexOff;
+uniform float TexRot;
+uniform sampler2D Tex2;
+uniform bool UseTex2;
 in vec4 Pos;
 in vec3 Norm;
 in vec3 CamDir;
 in vec2 TexCoord;
+in vec2 TexCoord2;
 out vec4 outputColor;
 `+RenderPhong+
 			`
 			
 void main() {
-	vec4 Color = texture(Tex, TexCoord * TexRepeat + TexOff);
+	float cr = cos(TexRot);
+	float sr = sin(TexRot);
+	vec2 tc = TexCoord - vec2(0.5);
+	tc = vec2(cr * tc.x - sr * tc.y, sr * tc.x + cr * tc.y) + vec2(0.5);
+	vec4 Color = texture(Tex, tc * TexRepeat + TexOff);
+	if(UseTex2) {
+		Color.rgb *= texture(Tex2, TexCoord2).rgb;
+	}
 	float opacity = Color.a;
 	vec3 clr = Color.rgb;	
 	
 	// Calculates the Ambient+Diffuse and Specular colors for this fragment using the Phong model.
 	vec3 Ambdiff, Spec;
-	phongModel(Pos, Norm, CamDir, clr, clr, Specular, Shiny, Ambdiff, Spec);
+	if (Unlit) { // flat color, ignoring all lighting
+		Ambdiff = clr + Emissive;
+		Spec = vec3(0.0);
+	} else {
+		phongModel(Pos, Norm, CamDir, clr, clr, Specular, Shiny, Ambdiff, Spec);
+	}
 
 	// Final fragment color
 	outputColor = min(vec4(Bright * Ambdiff + Spec, opacity), vec4(1.0));
@@ -570,10 +829,18 @@ void main() {
 	pr.AddUniform("Specular", gpu.Vec3fUniType, false, 0)
 	pr.AddUniform("Shiny", gpu.FUniType, false, 0)
 	pr.AddUniform("Bright", gpu.FUniType, false, 0)
+	pr.AddUniform("Unlit", gpu.BUniType, false, 0)
+	pr.AddUniform("SkinMats", gpu.Mat4fUniType, true, MaxSkinJoints)
+	pr.AddUniform("UseSkin", gpu.BUniType, false, 0)
+	pr.AddUniform("EnvIrrad", gpu.Mat4fUniType, true, 3)
+	pr.AddUniform("UseEnv", gpu.BUniType, false, 0)
 	pr.AddUniform("FlipY", gpu.BUniType, false, 0)
 	pr.AddUniform("Tex", gpu.IUniType, false, 0)
 	pr.AddUniform("TexRepeat", gpu.Vec2fUniType, false, 0)
 	pr.AddUniform("TexOff", gpu.Vec2fUniType, false, 0)
+	pr.AddUniform("TexRot", gpu.FUniType, false, 0)
+	pr.AddUniform("Tex2", gpu.IUniType, false, 0)
+	pr.AddUniform("UseTex2", gpu.BUniType, false, 0)
 
 	pr.SetFragDataVar("outputColor")
 
@@ -595,6 +862,8 @@ func (rb *RenderTexture) SetMat(mat *Material, sc *Scene) error {
 	shu.SetValue(mat.Shiny)
 	btu := pr.UniformByName("Bright")
 	btu.SetValue(mat.Bright)
+	unu := pr.UniformByName("Unlit")
+	unu.SetValue(mat.Unlit)
 	flu := pr.UniformByName("FlipY")
 	flu.SetValue(!mat.TexPtr.BotZero()) // flip if not botzero..
 	txu := pr.UniformByName("Tex")
@@ -603,7 +872,19 @@ func (rb *RenderTexture) SetMat(mat *Material, sc *Scene) error {
 	rpu.SetValue(mat.Tiling.Repeat)
 	ofu := pr.UniformByName("TexOff")
 	ofu.SetValue(mat.Tiling.Off)
+	rtu := pr.UniformByName("TexRot")
+	rtu.SetValue(mat32.DegToRad(mat.Tiling.Rot))
+	t2u := pr.UniformByName("Tex2")
+	t2u.SetValue(1)
+	ut2 := pr.UniformByName("UseTex2")
+	useTex2 := mat.Tex2Ptr != nil
+	ut2.SetValue(useTex2)
+	if useTex2 {
+		mat.Tex2Ptr.Activate(sc, 1)
+	}
 	gpu.Draw.CullFace(mat.CullFront, mat.CullBack, true) // back face culling, std CCW ordering
+	gpu.Draw.PolygonOffset(mat.PolyOffset.Factor, mat.PolyOffset.Units)
+	gpu.Draw.DepthFunc(mat.DepthFunc)
 	return nil
 }
 
@@ -616,9 +897,37 @@ layout (std140) uniform Camera
     mat4 MVMatrix;
     mat4 MVPMatrix;
     mat3 NormMatrix;
+    vec4 ClipPlane;
 };
 `
 
+// RenderSkin is the vertex shader chunk for skeletal animation (skinning):
+// the per-vertex joint indices and weights blend the skinning matrix
+// palette uploaded from the Skeleton (see RenderBase.SetSkin) to deform
+// the position and normal in mesh space, before the usual camera
+// transforms.  SKINMATS_LEN is #define'd from the SkinMats uniform array
+// length (MaxSkinJoints) during program compilation.
+var RenderSkin = `
+layout(location = 5) in vec4 VtxJoints;
+layout(location = 6) in vec4 VtxWeights;
+uniform mat4 SkinMats[SKINMATS_LEN];
+uniform bool UseSkin;
+
+// skinPosNorm deforms the mesh-space position and normal by the weighted
+// blend of the skinning joint matrices -- no-op if skinning is off.
+void skinPosNorm(inout vec4 pos, inout vec3 norm) {
+	if (!UseSkin) {
+		return;
+	}
+	mat4 sm = VtxWeights.x * SkinMats[int(VtxJoints.x)] +
+		VtxWeights.y * SkinMats[int(VtxJoints.y)] +
+		VtxWeights.z * SkinMats[int(VtxJoints.z)] +
+		VtxWeights.w * SkinMats[int(VtxJoints.w)];
+	pos = sm * pos;
+	norm = normalize(mat3(sm) * norm);
+}
+`
+
 var RenderUniLights = `
 layout (std140) uniform Lights
 {
@@ -636,6 +945,7 @@ layout (std140) uniform Lights
 	#define PointLightPos(a)       PointLights[3*a+1]
 	#define PointLightLinDecay(a)	  PointLights[3*a+2].x
 	#define PointLightQuadDecay(a)	 PointLights[3*a+2].y
+	#define PointLightConstDecay(a)	 PointLights[3*a+2].z
 #endif
 #if SPOTLIGHTS_LEN>0
 	vec3 SpotLights[SPOTLIGHTS_LEN];
@@ -646,16 +956,28 @@ layout (std140) uniform Lights
 	#define SpotLightCutAngle(a)  SpotLights[5*a+3].y
 	#define SpotLightLinDecay(a)  SpotLights[5*a+3].z
 	#define SpotLightQuadDecay(a) 	SpotLights[5*a+4].x
+	#define SpotLightConstDecay(a) 	SpotLights[5*a+4].y
 #endif
 };
 `
 
 var RenderPhong = `
+uniform mat4 EnvIrrad[ENVIRRAD_LEN];
+uniform bool UseEnv;
+
 // debugVec3 renders vector to color for debugging values
 void debugVec3(vec3 val, out vec4 clr) {
 	clr = vec4(0.5 + 0.5 * val, 1.0);
 }
 
+// envIrradiance returns the environment (image-based) diffuse light factor
+// for given view-space normal, from the spherical-harmonic irradiance
+// matrices -- see gi3d.Environment.
+vec3 envIrradiance(vec3 norm) {
+	vec4 nh = vec4(norm, 1.0);
+	return vec3(dot(nh, EnvIrrad[0] * nh), dot(nh, EnvIrrad[1] * nh), dot(nh, EnvIrrad[2] * nh));
+}
+
 
 void phongModel(vec4 pos, vec3 norm, vec3 camDir, vec3 matAmbient, vec3 matDiffuse, vec3 matSpecular, float shiny, out vec3 ambdiff, out vec3 spec) {
 
@@ -683,6 +1005,10 @@ void phongModel(vec4 pos, vec3 norm, vec3 camDir, vec3 matAmbient, vec3 matDiffu
 	}
 #endif
 
+	if (UseEnv) { // image-based ambient diffuse from the environment map
+		ambientTotal += max(envIrradiance(norm), vec3(0.0)) * matAmbient;
+	}
+
 #if DIRLIGHTS_LEN>0
 	int ndir = DIRLIGHTS_LEN / 2;
 	for (int i = 0; i < ndir; i++) {
@@ -711,7 +1037,7 @@ void phongModel(vec4 pos, vec3 norm, vec3 camDir, vec3 matAmbient, vec3 matDiffu
 		// Diffuse reflection
 		float dotNormal = dot(lightDir, norm);
 		if (dotNormal > EPS) {
-			float attenuation = 1.0 / (1.0 + lightDist * (PointLightLinDecay(i) +
+			float attenuation = 1.0 / (max(PointLightConstDecay(i), EPS) + lightDist * (PointLightLinDecay(i) +
 				PointLightQuadDecay(i) * lightDist));
 			vec3 attenColor = PointLightColor(i) * attenuation;
 			diffuseTotal += attenColor * matDiffuse * dotNormal;
@@ -741,7 +1067,7 @@ void phongModel(vec4 pos, vec3 norm, vec3 camDir, vec3 matAmbient, vec3 matDiffu
 			float dotNormal = dot(lightDir, norm);
 			if (dotNormal > EPS) {
 				// Calculates the attenuation due to the distance of the light
-				float attenuation = 1.0 / (1.0 + lightDist * (SpotLightLinDecay(i) +
+				float attenuation = 1.0 / (max(SpotLightConstDecay(i), EPS) + lightDist * (SpotLightLinDecay(i) +
 					SpotLightQuadDecay(i) * lightDist));
 				float spotFactor = pow(dot(-lightDir, SpotLightDir(i)), SpotLightAngDecay(i));
 				vec3 attenColor = SpotLightColor(i) * attenuation * spotFactor;
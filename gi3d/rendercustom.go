@@ -0,0 +1,113 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/gpu"
+)
+
+// RenderCustom is a render program compiled from user-supplied GLSL shader
+// sources, for special effects (toon shading, dissolve, holograms, etc) --
+// see Material.SetShaders.  The standard Camera and Lights uniform blocks
+// and the standard material uniforms (Color, Emissive, Specular, Shiny,
+// Bright, Unlit) are provided by convention and set per-solid, so custom
+// shaders can build on the same inputs as the built-in renders -- the
+// shader sources should declare whichever of these they use (the exported
+// RenderUniCamera, RenderUniLights, and RenderPhong source strings can be
+// concatenated in, exactly as the built-in shaders do).
+type RenderCustom struct {
+	RenderBase
+	VtxSrc  string `desc:"GLSL vertex shader source -- #version header is added automatically"`
+	FragSrc string `desc:"GLSL fragment shader source -- must write to outputColor"`
+}
+
+// renderCustomCount provides unique names for custom renders
+var renderCustomCount int64
+
+func (rb *RenderCustom) Init(rn *Renderers) error {
+	if rb.Nm == "" {
+		rb.Nm = fmt.Sprintf("RenderCustom%d", atomic.AddInt64(&renderCustomCount, 1))
+	}
+	if rb.Pipe == nil {
+		rb.Pipe = gpu.TheGPU.NewPipeline(rb.Nm)
+		rb.Pipe.AddProgram("VtxFrag")
+	}
+	pl := rb.Pipe
+	pr := pl.ProgramByName("VtxFrag")
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx", rb.VtxSrc+"\x00")
+	if err != nil {
+		return err
+	}
+	_, err = pr.AddShader(gpu.FragmentShader, "Frag", rb.FragSrc+"\x00")
+	if err != nil {
+		return err
+	}
+
+	pr.AddUniforms(rn.Unis["Camera"])
+	pr.AddUniforms(rn.Unis["Lights"])
+	pr.AddUniform("Color", gpu.Vec4fUniType, false, 0)
+	pr.AddUniform("Emissive", gpu.Vec3fUniType, false, 0)
+	pr.AddUniform("Specular", gpu.Vec3fUniType, false, 0)
+	pr.AddUniform("Shiny", gpu.FUniType, false, 0)
+	pr.AddUniform("Bright", gpu.FUniType, false, 0)
+	pr.AddUniform("Unlit", gpu.BUniType, false, 0)
+
+	pr.SetFragDataVar("outputColor")
+
+	return nil
+}
+
+// SetMat sets the standard material uniforms for the custom program --
+// uniforms not declared in the shader source are ignored by the GPU.
+func (rb *RenderCustom) SetMat(mat *Material, sc *Scene) error {
+	pr := rb.VtxFragProg()
+	clru := pr.UniformByName("Color")
+	clru.SetValue(ColorToVec4f(mat.Color))
+	emsu := pr.UniformByName("Emissive")
+	emsu.SetValue(ColorToVec3f(mat.Emissive))
+	spcu := pr.UniformByName("Specular")
+	spcu.SetValue(ColorToVec3f(mat.Specular))
+	shu := pr.UniformByName("Shiny")
+	shu.SetValue(mat.Shiny)
+	btu := pr.UniformByName("Bright")
+	btu.SetValue(mat.Bright)
+	unu := pr.UniformByName("Unlit")
+	unu.SetValue(mat.Unlit)
+	gpu.Draw.CullFace(mat.CullFront, mat.CullBack, true) // back face culling, std CCW ordering
+	return nil
+}
+
+// SetShaders compiles a custom render program from the given GLSL vertex and
+// fragment shader sources and sets this material to use it for rendering,
+// instead of the built-in lighting shaders -- see RenderCustom for the
+// uniform conventions.  If compilation fails, the error is logged and
+// returned, and the material falls back on the standard shaders.
+// The program is registered in the scene Renders, so it is automatically
+// recompiled when lights are added or removed.
+// Must be called with proper context activated.
+func (mt *Material) SetShaders(sc *Scene, vert, frag string) error {
+	rc := &RenderCustom{VtxSrc: vert, FragSrc: frag}
+	var err error
+	oswin.TheApp.RunOnMain(func() {
+		err = rc.Init(&sc.Renders)
+		if err == nil {
+			err = rc.Compile(&sc.Renders)
+		}
+	})
+	if err != nil {
+		err = fmt.Errorf("gi3d.Material SetShaders: custom shader failed to compile, falling back on standard: %v", err)
+		log.Println(err)
+		mt.Custom = nil
+		return err
+	}
+	mt.Custom = rc
+	sc.Renders.Renders[rc.Name()] = rc
+	return nil
+}
@@ -9,8 +9,11 @@ import (
 	"image"
 	"image/draw"
 	"log"
+	"os"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/mat32"
@@ -45,6 +48,28 @@ const (
 // Set Update3DTrace to true to get a trace of 3D updating
 var Update3DTrace = false
 
+// StereoModes are the stereo rendering modes for Scene -- see Scene.StereoMode
+type StereoModes int32
+
+const (
+	// StereoOff is normal single (mono) rendering (the default)
+	StereoOff StereoModes = iota
+
+	// StereoAnaglyph renders the left eye into the red channel and the right
+	// eye into the green / blue channels, for red / cyan anaglyph glasses
+	StereoAnaglyph
+
+	// StereoSideBySide renders the left and right eyes into the left and
+	// right halves of the scene texture
+	StereoSideBySide
+
+	StereoModesN
+)
+
+//go:generate stringer -type=StereoModes
+
+var KiT_StereoModes = kit.Enums.AddEnum(StereoModesN, kit.NotBitFlag, nil)
+
 // Scene is the overall scenegraph containing nodes as children.
 // It renders to its own Framebuffer, the Texture of which is then drawn
 // directly onto the window WinTex using the DirectWinUpload protocol.
@@ -61,25 +86,55 @@ var Update3DTrace = false
 // "first person" effects.
 type Scene struct {
 	gi.WidgetBase
-	Geom          gi.Geom2DInt       `desc:"Viewport-level viewbox within any parent Viewport2D"`
-	Camera        Camera             `desc:"camera determines view onto scene"`
-	BgColor       gi.Color           `desc:"background color"`
-	Wireframe     bool               `desc:"if true, render as wireframe instead of filled"`
-	Lights        map[string]Light   `desc:"all lights used in the scene"`
-	Meshes        map[string]Mesh    `desc:"all meshes used in the scene"`
-	Textures      map[string]Texture `desc:"all textures used in the scene"`
-	Library       map[string]*Group  `desc:"library of objects that can be used in the scene"`
-	NoNav         bool               `desc:"don't activate the standard navigation keyboard and mouse event processing to move around the camera in the scene"`
-	SavedCams     map[string]Camera  `desc:"saved cameras -- can Save and Set these to view the scene from different angles"`
-	Win           *gi.Window         `copy:"-" json:"-" xml:"-" desc:"our parent window that we render into"`
-	Renders       Renderers          `view:"-" desc:"rendering programs"`
-	Frame         gpu.Framebuffer    `view:"-" desc:"direct render target for scene"`
-	Tex           gpu.Texture2D      `view:"-" desc:"the texture that the framebuffer returns, which should be rendered into the window"`
-	SetDragCursor bool               `view:"-" desc:"has dragging cursor been set yet?"`
-	SelMode       SelModes           `desc:"how to deal with selection / manipulation events"`
-	CurSel        Node3D             `copy:"-" json:"-" xml:"-" view:"-" desc:"currently selected node"`
-	CurManipPt    *ManipPt           `copy:"-" json:"-" xml:"-" view:"-" desc:"currently selected manipulation control point"`
-	SelParams     SelParams          `view:"inline" desc:"parameters for selection / manipulation box"`
+	Geom                gi.Geom2DInt         `desc:"Viewport-level viewbox within any parent Viewport2D"`
+	Camera              Camera               `desc:"camera determines view onto scene"`
+	BgColor             gi.Color             `desc:"background color -- also the top color of the gradient if BgGradient is set"`
+	BgGradient          bool                 `desc:"render the background as a vertical two-color gradient, from BgColor at the top to BgEndColor at the bottom -- a cheap alternative to a full skybox"`
+	BgEndColor          gi.Color             `viewif:"BgGradient" desc:"bottom color of the background gradient -- see BgGradient"`
+	BgImage             TexName              `desc:"optional name of a texture (see Textures) to draw as a fullscreen background image behind all 3D content, instead of the flat BgColor or gradient"`
+	Wireframe           bool                 `desc:"if true, render as wireframe instead of filled"`
+	OpaqueSort          OpaqueSorts          `desc:"draw-order for opaque solids: front-to-back (the default) reduces overdraw via early depth rejection in fill-rate-bound scenes, none skips the per-frame sort -- transparent solids are always drawn back-to-front after the opaque pass -- see OpaqueSorts"`
+	MultiSample         int                  `desc:"number of samples for multi-sample anti-aliasing of the scene framebuffer -- 4 is the default set by Defaults and produces much better-looking edges when the scene is composited into the 2D UI -- 0 turns multi-sampling off -- ignored (treated as 0) if the Smooth3D preference is off"`
+	FXAA                bool                 `desc:"apply a post-process FXAA (fast approximate anti-aliasing) pass to the rendered scene texture -- a cheaper alternative to MultiSample that works uniformly for the offscreen-to-UI composite -- can be combined with it"`
+	SSAO                bool                 `desc:"apply a post-process SSAO (screen-space ambient occlusion) pass: darkens corners, creases and contact points where nearby geometry occludes ambient light -- see SSAOParams for radius / intensity"`
+	SSAOParams          SSAOParams           `viewif:"SSAO" view:"inline" desc:"parameters for the SSAO pass -- see SSAO"`
+	SRGBOut             bool                 `desc:"render with sRGB output gamma: enables sRGB framebuffer conversion while rendering the 3D scene, so linear lighting results are gamma-encoded correctly -- pair with sRGB color textures (see Texture SetSRGB) -- data textures such as normal maps must remain linear"`
+	ToneMap             ToneMapModes         `desc:"tone mapping operator applied when resolving the rendered scene into the display texture: compresses bright color values into display range with pleasing highlight rolloff -- see also Exposure"`
+	Exposure            float32              `viewif:"ToneMap" desc:"exposure multiplier applied to the scene color before tone mapping -- 1 = unchanged (set by Defaults) -- see ToneMap"`
+	StereoMode          StereoModes          `desc:"stereo rendering mode: the scene is rendered twice with the camera offset by the IPD eye separation, composited as a red / cyan anaglyph or as two side-by-side half-width views -- see IPD"`
+	IPD                 float32              `viewif:"StereoMode" desc:"inter-pupillary distance: eye separation in world units for stereo rendering -- the camera is offset by +/- IPD/2 along its local X axis for the two eyes -- default 0.064 (human average, for meter-scale scenes)"`
+	ClipPlane           mat32.Vec4           `desc:"user-defined clipping plane for cross-section views, in world coordinates: (X,Y,Z) is the plane unit normal and W the offset -- geometry on the negative side (dot(normal, pos) + W < 0) is clipped -- only active when ClipPlaneOn is set -- see SetClipPlane"`
+	ClipPlaneOn         bool                 `desc:"activates the user-defined ClipPlane during rendering"`
+	Env                 *Environment         `json:"-" xml:"-" desc:"optional environment map for image-based lighting: ambient diffuse light varies with surface orientation according to the surrounding environment, instead of (or in addition to) a uniform AmbientLight -- set with SetEnvironment -- see Environment"`
+	Lights              map[string]Light     `json:"-" xml:"-" desc:"all lights used in the scene"`
+	Meshes              map[string]Mesh      `json:"-" xml:"-" desc:"all meshes used in the scene"`
+	Textures            map[string]Texture   `json:"-" xml:"-" desc:"all textures used in the scene"`
+	Mats                map[string]*Material `json:"-" xml:"-" desc:"shared materials that solids can reference by name -- see AddMaterial, Solid.SetMaterialByName"`
+	Library             map[string]*Group    `json:"-" xml:"-" desc:"library of objects that can be used in the scene"`
+	Anims               map[string]*AnimClip `json:"-" xml:"-" desc:"library of animation clips for skinned (rigged) models -- see AddAnim, PlayAnim"`
+	AnimPlays           []*AnimPlay          `copy:"-" json:"-" xml:"-" view:"-" desc:"currently active animation playbacks -- see PlayAnim, AnimStep"`
+	NoNav               bool                 `desc:"don't activate the standard navigation keyboard and mouse event processing to move around the camera in the scene"`
+	ShowAxisIndicator   bool                 `desc:"show a small screen-space axis indicator (world X/Y/Z arrows, red/green/blue) in a corner of the view, rotating with the camera so the current orientation is visible at a glance -- see AxisIndicatorCorner and AxisIndicator"`
+	AxisIndicatorCorner ScreenCorners        `viewif:"ShowAxisIndicator" desc:"which corner of the view the axis indicator is drawn in -- default lower-left"`
+	SavedCams           map[string]Camera    `desc:"saved cameras -- can Save and Set these to view the scene from different angles"`
+	Win                 *gi.Window           `copy:"-" json:"-" xml:"-" desc:"our parent window that we render into"`
+	Renders             Renderers            `copy:"-" json:"-" xml:"-" view:"-" desc:"rendering programs"`
+	Frame               gpu.Framebuffer      `copy:"-" json:"-" xml:"-" view:"-" desc:"direct render target for scene"`
+	FxaaFrame           gpu.Framebuffer      `copy:"-" json:"-" xml:"-" view:"-" desc:"framebuffer for the post-process FXAA pass -- only allocated when FXAA is on"`
+	DepthNormFrame      gpu.Framebuffer      `copy:"-" json:"-" xml:"-" view:"-" desc:"framebuffer holding the view-space depth / normal texture for the SSAO pass -- only allocated when SSAO is on"`
+	AOFrame             gpu.Framebuffer      `copy:"-" json:"-" xml:"-" view:"-" desc:"framebuffer holding the raw (unblurred) occlusion texture for the SSAO pass -- only allocated when SSAO is on"`
+	SSAOFrame           gpu.Framebuffer      `copy:"-" json:"-" xml:"-" view:"-" desc:"framebuffer for the final SSAO blur / composite pass -- only allocated when SSAO is on"`
+	ToneMapFrame        gpu.Framebuffer      `copy:"-" json:"-" xml:"-" view:"-" desc:"framebuffer for the post-process tone mapping pass -- only allocated when ToneMap is on"`
+	Tex                 gpu.Texture2D        `copy:"-" json:"-" xml:"-" view:"-" desc:"the texture that the framebuffer returns, which should be rendered into the window"`
+	SetDragCursor       bool                 `copy:"-" json:"-" xml:"-" view:"-" desc:"has dragging cursor been set yet?"`
+	TexWatchInt         time.Duration        `desc:"polling interval for the texture file watcher -- 0 = default of 1 second -- see WatchTextures"`
+	texWatchStop        chan struct{}        // stops the texture watcher goroutine -- nil when not watching
+	texWatchMu          sync.Mutex           // protects texWatchStop, texReloads, and Textures map writes vs. the watcher goroutine
+	texReloads          []string             // texture names with changed files, pending reload on render
+	SelMode             SelModes             `desc:"how to deal with selection / manipulation events"`
+	CurSel              Node3D               `copy:"-" json:"-" xml:"-" view:"-" desc:"currently selected node"`
+	CurManipPt          *ManipPt             `copy:"-" json:"-" xml:"-" view:"-" desc:"currently selected manipulation control point"`
+	SelParams           SelParams            `view:"inline" desc:"parameters for selection / manipulation box"`
 }
 
 var KiT_Scene = kit.Types.AddType(&Scene{}, SceneProps)
@@ -93,6 +148,10 @@ func AddNewScene(parent ki.Ki, name string) *Scene {
 
 // Defaults sets default scene params (camera, bg = white)
 func (sc *Scene) Defaults() {
+	sc.MultiSample = 4
+	sc.IPD = 0.064
+	sc.SSAOParams.Defaults()
+	sc.Exposure = 1
 	sc.Camera.Defaults()
 	sc.BgColor.SetUInt8(255, 255, 255, 255)
 	sc.SelParams.Defaults()
@@ -231,9 +290,18 @@ func (sc *Scene) AddLight(lt Light) {
 	sc.Lights[lt.Name()] = lt
 }
 
+// DeleteLight removes the light of the given name from the scene -- the
+// change takes effect on the next render, when the shaders are recompiled
+// for the new set of lights (see Renderers.SetLights).
+func (sc *Scene) DeleteLight(name string) {
+	delete(sc.Lights, name)
+}
+
 // AddTexture adds given texture to texture collection
 // see AddNewTextureFile to add a texture that loads from file
 func (sc *Scene) AddTexture(tx Texture) {
+	sc.texWatchMu.Lock()
+	defer sc.texWatchMu.Unlock()
 	if sc.Textures == nil {
 		sc.Textures = make(map[string]Texture)
 	}
@@ -257,12 +325,16 @@ func (sc *Scene) TextureList() []string {
 
 // DeleteTexture deletes texture of given name -- returns error if not found
 func (sc *Scene) DeleteTexture(nm string) error {
+	sc.texWatchMu.Lock()
 	tx, ok := sc.Textures[nm]
+	if ok {
+		delete(sc.Textures, nm)
+	}
+	sc.texWatchMu.Unlock()
 	if ok {
 		oswin.TheApp.RunOnMain(func() {
 			tx.Delete(sc)
 		})
-		delete(sc.Textures, nm)
 		return nil
 	}
 	return fmt.Errorf("Texture named: %v not found in Scene: %v", nm, sc.Nm)
@@ -270,12 +342,164 @@ func (sc *Scene) DeleteTexture(nm string) error {
 
 // DeleteTextures removes all textures
 func (sc *Scene) DeleteTextures() {
+	sc.texWatchMu.Lock()
+	txs := sc.Textures
+	sc.Textures = make(map[string]Texture)
+	sc.texWatchMu.Unlock()
 	oswin.TheApp.RunOnMain(func() {
-		for _, tx := range sc.Textures {
+		for _, tx := range txs {
 			tx.Delete(sc)
 		}
 	})
-	sc.Textures = make(map[string]Texture)
+}
+
+// AddMaterial adds given material to the shared materials library under
+// given name.  Solids can then reference it via SetMaterialByName, so that
+// editing the one shared material updates all of them (on the next render).
+func (sc *Scene) AddMaterial(name string, mat *Material) {
+	if sc.Mats == nil {
+		sc.Mats = make(map[string]*Material)
+	}
+	sc.Mats[name] = mat
+}
+
+// MaterialByName looks for shared material by name -- returns nil if not found
+func (sc *Scene) MaterialByName(nm string) *Material {
+	if sc.Mats == nil {
+		sc.Mats = make(map[string]*Material)
+	}
+	mt, ok := sc.Mats[nm]
+	if ok {
+		return mt
+	}
+	return nil
+}
+
+// MaterialByNameTry looks for shared material by name -- returns error if not found
+func (sc *Scene) MaterialByNameTry(nm string) (*Material, error) {
+	if sc.Mats == nil {
+		sc.Mats = make(map[string]*Material)
+	}
+	mt, ok := sc.Mats[nm]
+	if ok {
+		return mt, nil
+	}
+	return nil, fmt.Errorf("Material named: %v not found in Scene: %v", nm, sc.Nm)
+}
+
+// MaterialList returns a list of available shared materials (e.g., for chooser)
+func (sc *Scene) MaterialList() []string {
+	sz := len(sc.Mats)
+	if sz == 0 {
+		return nil
+	}
+	sl := make([]string, sz)
+	ctr := 0
+	for k := range sc.Mats {
+		sl[ctr] = k
+		ctr++
+	}
+	return sl
+}
+
+// DeleteMaterial deletes shared material of given name -- returns error if
+// not found.  Solids referencing it retain their current copy of its values.
+func (sc *Scene) DeleteMaterial(nm string) error {
+	_, ok := sc.Mats[nm]
+	if ok {
+		delete(sc.Mats, nm)
+		return nil
+	}
+	return fmt.Errorf("Material named: %v not found in Scene: %v", nm, sc.Nm)
+}
+
+// WatchTextures turns on / off a file watcher that polls the modification
+// times of all TextureFile files in the scene and automatically reloads any
+// that change, so texture art can be iterated without restarting the app.
+// The reload itself happens on the render thread at the start of the next
+// render.  Poll interval is TexWatchInt (default 1 second).  Safe to toggle
+// at runtime.
+func (sc *Scene) WatchTextures(on bool) {
+	sc.texWatchMu.Lock()
+	defer sc.texWatchMu.Unlock()
+	if !on {
+		if sc.texWatchStop != nil {
+			close(sc.texWatchStop)
+			sc.texWatchStop = nil
+		}
+		return
+	}
+	if sc.texWatchStop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	sc.texWatchStop = stop
+	go sc.texWatchLoop(stop)
+}
+
+// texWatchLoop is the texture file watcher goroutine -- polls mtimes of
+// all TextureFile textures and queues reloads for changed files.
+func (sc *Scene) texWatchLoop(stop chan struct{}) {
+	intv := sc.TexWatchInt
+	if intv <= 0 {
+		intv = time.Second
+	}
+	mtimes := map[string]time.Time{}
+	tick := time.NewTicker(intv)
+	defer tick.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-tick.C:
+		}
+		// snapshot the file textures under the mutex -- the app goroutine
+		// can add / delete textures while we poll (see AddTexture etc)
+		sc.texWatchMu.Lock()
+		files := make(map[string]string, len(sc.Textures))
+		for nm, tex := range sc.Textures {
+			if tf, ok := tex.(*TextureFile); ok && tf.File != "" {
+				files[nm] = string(tf.File)
+			}
+		}
+		sc.texWatchMu.Unlock()
+		chg := false
+		for nm, fnm := range files {
+			fi, err := os.Stat(fnm)
+			if err != nil {
+				continue
+			}
+			mt := fi.ModTime()
+			lmt, has := mtimes[nm]
+			mtimes[nm] = mt
+			if has && mt.After(lmt) {
+				sc.texWatchMu.Lock()
+				sc.texReloads = append(sc.texReloads, nm)
+				sc.texWatchMu.Unlock()
+				chg = true
+			}
+		}
+		if chg {
+			sc.UpdateSig() // triggers a render, which does the reloads
+		}
+	}
+}
+
+// ReloadPendingTextures reloads any texture files that the watcher found
+// changed -- called at the start of rendering.
+// Must be called in context on main thread
+func (sc *Scene) ReloadPendingTextures() {
+	sc.texWatchMu.Lock()
+	rels := sc.texReloads
+	sc.texReloads = nil
+	sc.texWatchMu.Unlock()
+	for _, nm := range rels {
+		if tex, has := sc.Textures[nm]; has {
+			if tf, ok := tex.(*TextureFile); ok {
+				tf.Reload(sc)
+			}
+		}
+	}
 }
 
 // SaveCamera saves the current camera with given name -- can be restored later with SetCamera.
@@ -303,6 +527,37 @@ func (sc *Scene) SetCamera(name string) error {
 	return nil
 }
 
+// SetClipPlane sets the user-defined clipping plane from given unit normal
+// and offset, and activates it for subsequent renders (cross-section views).
+// Points where dot(norm, pos) + off < 0 are clipped.
+func (sc *Scene) SetClipPlane(norm mat32.Vec3, off float32) {
+	sc.ClipPlane.Set(norm.X, norm.Y, norm.Z, off)
+	sc.ClipPlaneOn = true
+}
+
+// ClipPlaneOff deactivates the user-defined clipping plane.
+func (sc *Scene) ClipPlaneOff() {
+	sc.ClipPlaneOn = false
+}
+
+// SetEnvironment loads an equirectangular environment map from given file
+// for image-based lighting, replacing any existing one -- typically a
+// Radiance .hdr file, so bright sources carry their full dynamic range (see
+// OpenEnvironment) -- takes effect on the next render.  An empty path
+// removes the environment, turning environment lighting off.
+func (sc *Scene) SetEnvironment(hdrPath string) error {
+	if hdrPath == "" {
+		sc.Env = nil
+		return nil
+	}
+	env, err := OpenEnvironment(hdrPath)
+	if err != nil {
+		return err
+	}
+	sc.Env = env
+	return nil
+}
+
 // DeleteUnusedMeshes deletes all unused meshes
 func (sc *Scene) DeleteUnusedMeshes() {
 	// used := make(map[string]struct{})
@@ -340,6 +595,29 @@ func (sc *Scene) Validate() error {
 	return nil
 }
 
+// SaveJSON saves the scene node tree (Pose, Material, mesh and texture
+// references by name) and camera state to a JSON-encoded file.
+// The Meshes, Textures, and Lights libraries themselves are not saved --
+// they must be reconstructed programmatically, and references are
+// re-linked by name in OpenJSON.
+func (sc *Scene) SaveJSON(path string) error {
+	return sc.WidgetBase.SaveJSON(path)
+}
+
+// OpenJSON opens a scene node tree and camera state from a JSON-encoded
+// file, as saved by SaveJSON, re-linking mesh and texture references by
+// name into the scene's current Meshes / Textures libraries (which must
+// be populated first).  Unresolved references are logged as warnings but
+// do not prevent the rest of the scene from loading.
+func (sc *Scene) OpenJSON(path string) error {
+	err := sc.WidgetBase.OpenJSON(path)
+	if err != nil {
+		return err
+	}
+	sc.Validate() // re-links mesh / texture refs -- logs any unresolved
+	return nil
+}
+
 /////////////////////////////////////////////////////////////////////////////////////
 //  Library
 
@@ -609,8 +887,13 @@ func (sc *Scene) NavEvents() {
 		switch {
 		case key.HasAllModifierBits(me.Modifiers, key.Alt):
 			ssc.Camera.PanTarget(0, 0, zoom*zoomDel)
-		default:
+		case key.HasAllModifierBits(me.Modifiers, key.Control):
+			// trackpad pinch arrives as control+scroll where the OS reports it
 			ssc.Camera.Zoom(zoomPct * zoom)
+		default:
+			// dolly toward the point under the cursor, not just the view axis
+			relpos := me.Where.Sub(ssc.ObjBBox.Min)
+			ssc.Camera.ZoomTo(relpos, ssc.Geom.Size, zoomPct*zoom)
 		}
 		ssc.UpdateSig()
 	})
@@ -760,13 +1043,14 @@ func (sc *Scene) ActivateFrame() bool {
 		return false
 	}
 	oswin.TheApp.RunOnMain(func() {
+		msamp := sc.MultiSample
+		if !gi.Prefs.Smooth3D {
+			msamp = 0
+		}
 		if sc.Frame == nil {
-			msamp := 4
-			if !gi.Prefs.Smooth3D {
-				msamp = 0
-			}
 			sc.Frame = gpu.TheGPU.NewFramebuffer(sc.Nm+"-frame", sc.Geom.Size, msamp)
 		}
+		sc.Frame.SetSamples(msamp)     // nop if same
 		sc.Frame.SetSize(sc.Geom.Size) // nop if same
 		sc.Camera.Aspect = float32(sc.Geom.Size.X) / float32(sc.Geom.Size.Y)
 		// fmt.Printf("aspect ratio: %v\n", sc.Camera.Aspect)
@@ -898,6 +1182,21 @@ func (sc *Scene) DeleteResources() {
 		if sc.Frame != nil {
 			sc.Frame.Delete()
 		}
+		if sc.FxaaFrame != nil {
+			sc.FxaaFrame.Delete()
+		}
+		if sc.DepthNormFrame != nil {
+			sc.DepthNormFrame.Delete()
+		}
+		if sc.AOFrame != nil {
+			sc.AOFrame.Delete()
+		}
+		if sc.SSAOFrame != nil {
+			sc.SSAOFrame.Delete()
+		}
+		if sc.ToneMapFrame != nil {
+			sc.ToneMapFrame.Delete()
+		}
 	})
 }
 
@@ -971,7 +1270,11 @@ func (sc *Scene) UpdateMVPMatrix() {
 		if nii == nil {
 			return false // going into a different type of thing, bail
 		}
-		nii.UpdateMVPMatrix(&sc.Camera.ViewMatrix, &sc.Camera.PrjnMatrix)
+		prjn := &sc.Camera.PrjnMatrix
+		if sld := nii.AsSolid(); sld != nil && sld.Layer == LayerViewModel {
+			prjn = &sc.Camera.VMPrjnMatrix // tighter near plane -- see RenderLayers
+		}
+		nii.UpdateMVPMatrix(&sc.Camera.ViewMatrix, prjn)
 		nii.UpdateBBox2D(size, sc)
 		return true
 	})
@@ -1045,22 +1348,258 @@ func (sc *Scene) Render() bool {
 	}
 	sc.Camera.UpdateMatrix()
 	sc.TrackCamera()
+	sc.ConfigAxisIndicator()
 	sc.UpdateNodes3D()
 	sc.UpdateWorldMatrix()
 	sc.UpdateMeshBBox()
 	sc.UpdateMVPMatrix()
+	sc.Renders.SetLights(sc) // recompiles if lights were added / removed
+	sc.Renders.SetEnvIrrad(sc)
 	oswin.TheApp.RunOnMain(func() {
+		sc.ReloadPendingTextures()
 		sc.Renders.SetLightsUnis(sc)
-		sc.Render3D()
+		sc.Renders.SetClipPlane(sc)
+		gpu.Draw.SRGB(sc.SRGBOut)
+		if sc.StereoMode != StereoOff {
+			sc.RenderStereo()
+		} else {
+			sc.Render3D()
+		}
 		gpu.Draw.Flush()
 		gpu.Draw.Wireframe(false)
+		gpu.Draw.SRGB(false)
 		sc.Frame.Rendered()
 		sc.Tex = sc.Frame.Texture()
 		sc.Tex.SetBotZero(true) // this has Y=0 at bottom!
+		if sc.SSAO {
+			sc.RenderSSAO()
+		}
+		if sc.ToneMap != ToneMapOff {
+			sc.RenderToneMap()
+		}
+		if sc.FXAA {
+			sc.RenderFXAA()
+		}
 	})
 	return true
 }
 
+// RenderStereo renders the scene twice with the camera offset by -/+ IPD/2
+// along its local X axis (left then right eye), compositing the two renders
+// according to StereoMode: anaglyph puts the left eye in the red channel and
+// the right in green / blue (red / cyan glasses), side-by-side splits the
+// viewport in half.  Uses simple parallel eye axes (no convergence / off-axis
+// projection).  Called by Render in place of Render3D -- must be called in
+// context on main thread.
+func (sc *Scene) RenderStereo() {
+	cam := sc.Camera // restored below -- per-eye renders modify pose, aspect
+	sz := sc.Geom.Size
+	right := mat32.Vec3{X: 1}.MulQuat(sc.Camera.Pose.Quat) // camera local X in world coords
+	for eye := 0; eye < 2; eye++ {
+		sc.Camera.Pose.Pos = cam.Pose.Pos.Add(right.MulScalar(sc.IPD * (float32(eye) - 0.5)))
+		switch sc.StereoMode {
+		case StereoAnaglyph:
+			if eye == 0 {
+				gpu.Draw.ColorMask(true, false, false, true) // left = red
+			} else {
+				gpu.Draw.Clear(false, true)                 // keep left eye color, fresh depth
+				gpu.Draw.ColorMask(false, true, true, true) // right = cyan
+			}
+		case StereoSideBySide:
+			hw := sz.X / 2
+			sc.Camera.Aspect = float32(hw) / float32(sz.Y)
+			// note: Viewport Max is the size, not the max coordinate
+			gpu.Draw.Viewport(image.Rectangle{Min: image.Point{X: eye * hw}, Max: image.Point{X: hw, Y: sz.Y}})
+		}
+		sc.Camera.UpdateMatrix()
+		sc.UpdateMVPMatrix()
+		sc.Render3D()
+	}
+	gpu.Draw.ColorMask(true, true, true, true)
+	gpu.Draw.Viewport(image.Rectangle{Max: sz})
+	sc.Camera = cam
+	sc.Camera.UpdateMatrix()
+	sc.UpdateMVPMatrix()
+}
+
+// RenderFXAA runs the post-process FXAA pass: draws the current scene
+// texture (sc.Tex) through the FXAA shader onto a fullscreen quad into the
+// FxaaFrame framebuffer, and sets sc.Tex to the resulting texture.
+// Must be called with appropriate context activated, on main (see RenderFXAA render).
+func (sc *Scene) RenderFXAA() {
+	rnd, ok := sc.Renders.Renders["RenderFXAA"]
+	if !ok {
+		return
+	}
+	fx := rnd.(*RenderFXAA)
+	sz := sc.Geom.Size
+	if sc.FxaaFrame == nil {
+		sc.FxaaFrame = gpu.TheGPU.NewFramebuffer(sc.Nm+"-fxaa", sz, 0)
+	}
+	sc.FxaaFrame.SetSize(sz) // nop if same
+	sc.FxaaFrame.Activate()
+	gpu.Draw.Viewport(image.Rectangle{Max: sz})
+	gpu.Draw.DepthTest(false)
+	gpu.Draw.Op(draw.Src)
+	fx.Activate(&sc.Renders)
+	pr := fx.VtxFragProg()
+	sc.Tex.Activate(0)
+	txu := pr.UniformByName("Tex")
+	txu.SetValue(0)
+	isu := pr.UniformByName("InvSize")
+	isu.SetValue(mat32.Vec2{1 / float32(sz.X), 1 / float32(sz.Y)})
+	fx.InitQuad(&sc.Renders)
+	fx.Quad.Activate()
+	gpu.Draw.Triangles(0, 6)
+	gpu.Draw.Flush()
+	gpu.Draw.DepthTest(true)
+	sc.FxaaFrame.Rendered()
+	sc.Tex = sc.FxaaFrame.Texture()
+	sc.Tex.SetBotZero(true)
+}
+
+// RenderToneMap runs the post-process tone mapping pass: draws the current
+// scene texture (sc.Tex) through the tone mapping shader (see ToneMap,
+// Exposure) onto a fullscreen quad into the ToneMapFrame framebuffer, and
+// sets sc.Tex to the resulting texture.
+// Must be called with appropriate context activated, on main (see Render).
+func (sc *Scene) RenderToneMap() {
+	rnd, ok := sc.Renders.Renders["RenderToneMap"]
+	if !ok {
+		return
+	}
+	tm := rnd.(*RenderToneMap)
+	sz := sc.Geom.Size
+	if sc.ToneMapFrame == nil {
+		sc.ToneMapFrame = gpu.TheGPU.NewFramebuffer(sc.Nm+"-tonemap", sz, 0)
+	}
+	sc.ToneMapFrame.SetSize(sz) // nop if same
+	sc.ToneMapFrame.Activate()
+	gpu.Draw.Viewport(image.Rectangle{Max: sz})
+	gpu.Draw.DepthTest(false)
+	gpu.Draw.Op(draw.Src)
+	tm.Activate(&sc.Renders)
+	pr := tm.VtxFragProg()
+	sc.Tex.Activate(0)
+	pr.UniformByName("Tex").SetValue(0)
+	pr.UniformByName("Mode").SetValue(int(sc.ToneMap))
+	pr.UniformByName("Exposure").SetValue(sc.Exposure)
+	tm.InitQuad(&sc.Renders)
+	tm.Quad.Activate()
+	gpu.Draw.Triangles(0, 6)
+	gpu.Draw.Flush()
+	gpu.Draw.DepthTest(true)
+	sc.ToneMapFrame.Rendered()
+	sc.Tex = sc.ToneMapFrame.Texture()
+	sc.Tex.SetBotZero(true)
+}
+
+// RenderSSAO runs the post-process SSAO (screen-space ambient occlusion)
+// pass, in three stages: (1) re-render the opaque scene geometry's
+// view-space normals and linear depth into DepthNormFrame (RenderDepthNorm);
+// (2) compute the raw per-pixel occlusion with a randomized hemisphere
+// sampling kernel into AOFrame (RenderSSAO); (3) blur the occlusion and
+// multiply the scene texture by it into SSAOFrame (RenderSSAOBlur), which
+// becomes the displayed texture.  See SSAOParams for the radius / intensity
+// parameters.  Must be called with appropriate context activated, on main.
+func (sc *Scene) RenderSSAO() {
+	dnr, ok := sc.Renders.Renders["RenderDepthNorm"]
+	if !ok {
+		return
+	}
+	dn := dnr.(*RenderDepthNorm)
+	ao := sc.Renders.Renders["RenderSSAO"].(*RenderSSAO)
+	bl := sc.Renders.Renders["RenderSSAOBlur"].(*RenderSSAOBlur)
+	sz := sc.Geom.Size
+	if sc.DepthNormFrame == nil {
+		sc.DepthNormFrame = gpu.TheGPU.NewFramebuffer(sc.Nm+"-depthnorm", sz, 0)
+	}
+	if sc.AOFrame == nil {
+		sc.AOFrame = gpu.TheGPU.NewFramebuffer(sc.Nm+"-ao", sz, 0)
+	}
+	if sc.SSAOFrame == nil {
+		sc.SSAOFrame = gpu.TheGPU.NewFramebuffer(sc.Nm+"-ssao", sz, 0)
+	}
+	sc.DepthNormFrame.SetSize(sz) // nop if same
+	sc.AOFrame.SetSize(sz)
+	sc.SSAOFrame.SetSize(sz)
+
+	// pass 1: view-space depth / normals of the opaque scene geometry
+	sc.DepthNormFrame.Activate()
+	gpu.Draw.Viewport(image.Rectangle{Max: sz})
+	gpu.Draw.ClearColor(0.5, 0.5, 1) // normal = (0,0,1), depth = far
+	gpu.Draw.Clear(true, true)
+	gpu.Draw.Op(draw.Src)
+	dn.Activate(&sc.Renders)
+	pr := dn.VtxFragProg()
+	pr.UniformByName("CamFar").SetValue(sc.Camera.Far)
+	sc.FuncDownMeFirst(0, sc.This(), func(k ki.Ki, level int, d interface{}) bool {
+		if k == sc.This() {
+			return true
+		}
+		nii, ni := KiToNode3D(k)
+		if nii == nil {
+			return false // going into a different type of thing, bail
+		}
+		if ni.IsInvisible() || ni.ObjBBox == image.ZR {
+			return false
+		}
+		if !nii.IsSolid() {
+			return true
+		}
+		sld := nii.AsSolid()
+		if sld == nil || sld.Layer != LayerDefault || sld.IsTransparent() {
+			return true // transparent / background / overlay solids don't occlude
+		}
+		dn.SetSkin(sld.Skel)
+		sc.Renders.SetMatrix(&sld.Pose)
+		sld.MeshPtr.Render3D(sc)
+		return true
+	})
+	gpu.Draw.Flush()
+	sc.DepthNormFrame.Rendered()
+	dnTex := sc.DepthNormFrame.Texture()
+
+	// pass 2: raw occlusion from the hemisphere sampling kernel
+	sc.AOFrame.Activate()
+	gpu.Draw.Viewport(image.Rectangle{Max: sz})
+	gpu.Draw.DepthTest(false)
+	ao.Activate(&sc.Renders)
+	pr = ao.VtxFragProg()
+	dnTex.Activate(0)
+	pr.UniformByName("DepthNorm").SetValue(0)
+	pr.UniformByName("PrjnMatrix").SetValue(sc.Camera.PrjnMatrix)
+	pr.UniformByName("InvPrjnMatrix").SetValue(sc.Camera.InvPrjnMatrix)
+	pr.UniformByName("Samples").SetValue(SSAOKernelSamples())
+	pr.UniformByName("CamFar").SetValue(sc.Camera.Far)
+	pr.UniformByName("Radius").SetValue(sc.SSAOParams.Radius)
+	ao.InitQuad(&sc.Renders)
+	ao.Quad.Activate()
+	gpu.Draw.Triangles(0, 6)
+	gpu.Draw.Flush()
+	sc.AOFrame.Rendered()
+
+	// pass 3: blur the occlusion and composite with the scene color
+	sc.SSAOFrame.Activate()
+	gpu.Draw.Viewport(image.Rectangle{Max: sz})
+	bl.Activate(&sc.Renders)
+	pr = bl.VtxFragProg()
+	sc.Tex.Activate(0)
+	pr.UniformByName("Tex").SetValue(0)
+	sc.AOFrame.Texture().Activate(1)
+	pr.UniformByName("AOTex").SetValue(1)
+	pr.UniformByName("InvSize").SetValue(mat32.Vec2{1 / float32(sz.X), 1 / float32(sz.Y)})
+	pr.UniformByName("Intensity").SetValue(sc.SSAOParams.Intensity)
+	bl.InitQuad(&sc.Renders)
+	bl.Quad.Activate()
+	gpu.Draw.Triangles(0, 6)
+	gpu.Draw.Flush()
+	gpu.Draw.DepthTest(true)
+	sc.SSAOFrame.Rendered()
+	sc.Tex = sc.SSAOFrame.Texture()
+	sc.Tex.SetBotZero(true)
+}
+
 // RenderOffFrame renders the scene to currently-activated offscreen framebuffer
 // must call ActivateOffFrame first and call Frame.Rendered() after!
 func (sc *Scene) RenderOffFrame() bool {
@@ -1069,10 +1608,16 @@ func (sc *Scene) RenderOffFrame() bool {
 	sc.UpdateWorldMatrix()
 	sc.UpdateMeshBBox()
 	sc.UpdateMVPMatrix()
+	sc.Renders.SetLights(sc) // recompiles if lights were added / removed
+	sc.Renders.SetEnvIrrad(sc)
 	oswin.TheApp.RunOnMain(func() {
+		sc.ReloadPendingTextures()
 		sc.Renders.SetLightsUnis(sc)
+		sc.Renders.SetClipPlane(sc)
+		gpu.Draw.SRGB(sc.SRGBOut)
 		sc.Render3D()
 		gpu.Draw.Flush()
+		gpu.Draw.SRGB(false)
 	})
 	return true
 }
@@ -1126,7 +1671,8 @@ func (sc *Scene) DirectWinUpload() bool {
 // Render3D renders the scene to the framebuffer
 // all scene-level resources must be initialized and activated at this point
 func (sc *Scene) Render3D() {
-	var rcs [RenderClassesN][]Node3D
+	var lrcs [RenderLayersN][RenderClassesN][]Node3D
+	var outlines []*Solid
 	sc.FuncDownMeFirst(0, sc.This(), func(k ki.Ki, level int, d interface{}) bool {
 		if k == sc.This() {
 			return true
@@ -1147,10 +1693,99 @@ func (sc *Scene) Render3D() {
 		if rc > RClassTransTexture { // all in one group b/c z-sorting is key
 			rc = RClassTransTexture
 		}
-		rcs[rc] = append(rcs[rc], nii)
+		layer := LayerDefault
+		if sld := nii.AsSolid(); sld != nil {
+			layer = sld.Layer
+			if sld.Outline.On {
+				outlines = append(outlines, sld)
+			}
+		}
+		lrcs[layer][rc] = append(lrcs[layer][rc], nii)
 		return true
 	})
 
+	if sc.BgGradient || sc.BgImage != "" {
+		sc.RenderBg()
+	}
+
+	for _, layer := range RenderLayerOrder {
+		sc.Render3DLayer(layer, &lrcs[layer])
+	}
+
+	if len(outlines) > 0 { // outline / highlight pass (see Solid.Outline)
+		rnd := sc.Renders.Renders["RenderUniformColor"].(*RenderUniformColor)
+		gpu.Draw.Op(draw.Over)
+		rnd.Activate(&sc.Renders)
+		for _, sld := range outlines {
+			sld.RenderOutline(sc, rnd)
+		}
+	}
+
+	// restore per-material depth state (see Material.PolyOffset, DepthFunc)
+	gpu.Draw.PolygonOffset(0, 0)
+	gpu.Draw.DepthFunc(gpu.DepthLEqual)
+}
+
+// RenderBg renders the background gradient or image (see BgGradient,
+// BgImage) as a fullscreen quad behind all 3D content -- depth testing is
+// off while it draws, so all geometry renders over it.  Called at the
+// start of Render3D.  Must be called in context on main thread.
+func (sc *Scene) RenderBg() {
+	rnd, has := sc.Renders.Renders["RenderBackground"]
+	if !has {
+		return
+	}
+	bg := rnd.(*RenderBackground)
+	var tex Texture
+	if sc.BgImage != "" {
+		tex = sc.Textures[string(sc.BgImage)]
+	}
+	gpu.Draw.DepthTest(false)
+	gpu.Draw.Op(draw.Src)
+	bg.Activate(&sc.Renders)
+	pr := bg.VtxFragProg()
+	if tex != nil {
+		tex.Activate(sc, 0)
+		pr.UniformByName("Tex").SetValue(0)
+		pr.UniformByName("UseTex").SetValue(true)
+		pr.UniformByName("FlipY").SetValue(!tex.BotZero())
+	} else {
+		pr.UniformByName("UseTex").SetValue(false)
+		top := ColorToVec4f(sc.BgColor)
+		bot := top
+		if sc.BgGradient {
+			bot = ColorToVec4f(sc.BgEndColor)
+		}
+		pr.UniformByName("TopColor").SetValue(top)
+		pr.UniformByName("BotColor").SetValue(bot)
+	}
+	bg.InitQuad(&sc.Renders)
+	bg.Quad.Activate()
+	gpu.Draw.Triangles(0, 6)
+	gpu.Draw.DepthTest(true)
+}
+
+// Render3DLayer renders one RenderLayers layer of the scene, with the
+// solids bucketed by RenderClasses -- the Background and Overlay layers
+// are rendered with depth testing disabled, so they are always behind /
+// on top of the Default scene content respectively.
+func (sc *Scene) Render3DLayer(layer RenderLayers, rcs *[RenderClassesN][]Node3D) {
+	nobj := 0
+	for _, objs := range rcs {
+		nobj += len(objs)
+	}
+	if nobj == 0 {
+		return
+	}
+	depthOff := layer == LayerBackground || layer == LayerOverlay
+	if depthOff {
+		gpu.Draw.DepthTest(false)
+	}
+	if layer == LayerViewModel {
+		// clear depth so viewmodel content draws over the scene, but still
+		// depth-tests against itself -- see RenderLayers
+		gpu.Draw.Clear(false, true)
+	}
 	for rci, objs := range rcs {
 		rc := RenderClasses(rci)
 		if len(objs) == 0 {
@@ -1160,7 +1795,8 @@ func (sc *Scene) Render3D() {
 			sort.Slice(objs, func(i, j int) bool {
 				return objs[i].AsNode3D().NDCBBox.Max.Z > objs[j].AsNode3D().NDCBBox.Max.Z
 			})
-		} else { // sort front-to-back for opaque to allow "early z rejection"
+		} else if sc.OpaqueSort == OpaqueSortFrontToBack {
+			// sort front-to-back for opaque to allow "early z rejection"
 			sort.Slice(objs, func(i, j int) bool {
 				return objs[i].AsNode3D().NDCBBox.Min.Z < objs[j].AsNode3D().NDCBBox.Min.Z
 			})
@@ -1202,6 +1838,9 @@ func (sc *Scene) Render3D() {
 			obj.Render3D(sc, rc, rnd)
 		}
 	}
+	if depthOff {
+		gpu.Draw.DepthTest(true)
+	}
 }
 
 // TrackCamera -- a Group at the top-level named "TrackCamera"
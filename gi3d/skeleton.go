@@ -0,0 +1,130 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/goki/gi/mat32"
+	"github.com/goki/ki/kit"
+)
+
+// MaxSkinJoints is the maximum number of joints in a Skeleton -- this is
+// baked into the vertex shaders as the size of the skinning matrix palette
+// uniform array (64 mat4's = 4kb, well within the minimum guaranteed
+// uniform space of any OpenGL 3.3 GPU).
+const MaxSkinJoints = 64
+
+// Joint is one joint (bone) in a Skeleton.  Joints are stored in a flat
+// slice with parent indexes, ordered so that parents always come before
+// their children (indexes only ever point backward), which allows world
+// matrices to be computed in a single forward pass.
+type Joint struct {
+	Nm      string     `desc:"name of the joint -- canonically the bone name from the source rig"`
+	Par     int        `desc:"index of the parent joint in Skeleton.Joints -- must be less than this joint's own index -- -1 for a root joint"`
+	Pose    Pose       `view:"inline" desc:"current local transform relative to the parent joint -- this is what animation drives"`
+	InvBind mat32.Mat4 `view:"-" desc:"inverse of the joint's world matrix in the bind (rest) pose -- transforms mesh vertices into this joint's local space -- see Skeleton.SetBindPose"`
+}
+
+// Skeleton is a hierarchy of joints (bones) that drives the deformation of
+// skinned meshes: each frame the joint Pose transforms are updated (e.g., by
+// an AnimClip -- see Scene.PlayAnim), UpdateMats computes the skinning
+// matrix palette, and the palette is uploaded as a uniform matrix array and
+// applied to the mesh vertices in the vertex shader, weighted by the
+// per-vertex Joints / Weights data on the Mesh.  Assign to Solid.Skel to
+// activate skinning for a solid.
+type Skeleton struct {
+	Joints   []Joint      `desc:"the joints, ordered with parents before children -- see AddJoint"`
+	SkinMats []mat32.Mat4 `view:"-" desc:"computed skinning matrix palette: world matrix * InvBind per joint -- updated by UpdateMats, uploaded as a uniform array for the vertex shader"`
+}
+
+var KiT_Skeleton = kit.Types.AddType(&Skeleton{}, nil)
+
+// AddJoint adds a new joint with given name and parent joint index
+// (-1 for a root joint), and returns its index.  The parent must already
+// have been added (parent index < this index).  Logs an error and returns
+// -1 if the parent index is out of range or MaxSkinJoints is exceeded.
+func (sk *Skeleton) AddJoint(name string, par int) int {
+	idx := len(sk.Joints)
+	if idx >= MaxSkinJoints {
+		err := fmt.Errorf("gi3d.Skeleton: AddJoint %s: maximum number of joints: %d exceeded", name, MaxSkinJoints)
+		log.Println(err)
+		return -1
+	}
+	if par >= idx {
+		err := fmt.Errorf("gi3d.Skeleton: AddJoint %s: parent index: %d must be less than joint index: %d (parents before children)", name, par, idx)
+		log.Println(err)
+		return -1
+	}
+	jt := Joint{Nm: name, Par: par}
+	jt.Pose.Defaults()
+	jt.InvBind.SetIdentity()
+	sk.Joints = append(sk.Joints, jt)
+	return idx
+}
+
+// JointByName returns the index of the joint with given name
+// (-1 if not found).
+func (sk *Skeleton) JointByName(name string) int {
+	for i := range sk.Joints {
+		if sk.Joints[i].Nm == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetBindPose records the current joint poses as the bind (rest) pose,
+// setting each joint's InvBind to the inverse of its current world matrix.
+// Call once after building the skeleton with the joints posed to match the
+// mesh as modeled -- vertices are then deformed by the *difference* from
+// this pose.  Loaders with explicit inverse bind matrices can set InvBind
+// directly instead.
+func (sk *Skeleton) SetBindPose() {
+	sk.updateWorldMats()
+	for i := range sk.Joints {
+		jt := &sk.Joints[i]
+		ib, err := jt.Pose.WorldMatrix.Inverse()
+		if err != nil { // degenerate (e.g., zero scale) joint
+			log.Printf("gi3d.Skeleton: SetBindPose joint %s: %v\n", jt.Nm, err)
+			ib = mat32.NewMat4()
+		}
+		jt.InvBind = *ib
+	}
+}
+
+// updateWorldMats updates the local and world matrices of all joints,
+// in order (parents before children).
+func (sk *Skeleton) updateWorldMats() {
+	for i := range sk.Joints {
+		jt := &sk.Joints[i]
+		jt.Pose.UpdateMatrix()
+		if jt.Par >= 0 {
+			jt.Pose.UpdateWorldMatrix(&sk.Joints[jt.Par].Pose.WorldMatrix)
+		} else {
+			jt.Pose.UpdateWorldMatrix(mat32.NewMat4()) // identity
+		}
+	}
+}
+
+// UpdateMats updates the skinning matrix palette (SkinMats) from the
+// current joint poses: world matrix * InvBind per joint.  Call after
+// changing joint poses (done automatically by AnimClip.Apply).
+// The palette is always MaxSkinJoints long (identity beyond the last
+// joint) as required for the uniform array upload.
+func (sk *Skeleton) UpdateMats() {
+	if len(sk.SkinMats) != MaxSkinJoints {
+		sk.SkinMats = make([]mat32.Mat4, MaxSkinJoints)
+		for i := range sk.SkinMats {
+			sk.SkinMats[i].SetIdentity()
+		}
+	}
+	sk.updateWorldMats()
+	for i := range sk.Joints {
+		jt := &sk.Joints[i]
+		sk.SkinMats[i].MulMatrices(&jt.Pose.WorldMatrix, &jt.InvBind)
+	}
+}
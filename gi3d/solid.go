@@ -21,9 +21,30 @@ import (
 // and points to a mesh structure defining the shape of the solid.
 type Solid struct {
 	Node3DBase
-	Mesh    MeshName `desc:"name of the mesh shape information used for rendering this solid -- all meshes are collected on the Scene"`
-	Mat     Material `view:"add-fields" desc:"material properties of the surface (color, shininess, texture, etc)"`
-	MeshPtr Mesh     `view:"-" desc:"cached pointer to mesh"`
+	Mesh    MeshName      `desc:"name of the mesh shape information used for rendering this solid -- all meshes are collected on the Scene"`
+	Mat     Material      `view:"add-fields" desc:"material properties of the surface (color, shininess, texture, etc)"`
+	MatNm   MatName       `desc:"optional name of a shared material in the Scene materials library (see Scene.AddMaterial) -- if set, material values are re-copied from the shared material during styling, so editing the shared material updates all solids that reference it"`
+	Layer   RenderLayers  `desc:"explicit rendering layer: LayerDefault (the default) is the normal scene content, LayerBackground is always behind it (e.g., a skybox), LayerViewModel is drawn over it with a tighter near plane (e.g., first-person weapons -- see Camera.ViewModelNear), and LayerOverlay is always on top (e.g., HUD elements) -- see RenderLayers"`
+	Outline OutlineParams `view:"inline" desc:"selection outline / highlight parameters -- disabled by default"`
+	Skel    *Skeleton     `copy:"-" json:"-" xml:"-" view:"-" desc:"optional skeleton driving skinned (rigged) deformation of this solid's mesh -- mesh must have per-vertex Joints / Weights -- see Skeleton, Scene.PlayAnim"`
+	MeshPtr Mesh          `copy:"-" json:"-" xml:"-" view:"-" desc:"cached pointer to mesh"`
+}
+
+// OutlineParams are the parameters for the optional outline / highlight
+// render pass around a Solid, for selection feedback in editors.
+// Uses an inverted-hull approach: the mesh is re-rendered slightly scaled
+// up, in the outline color, with front faces culled, so only a silhouette
+// band shows around the normal render -- this does not require a stencil
+// buffer on the framebuffer.
+type OutlineParams struct {
+	On    bool     `desc:"turn on the outline"`
+	Color gi.Color `desc:"color of the outline"`
+	Width float32  `desc:"width of the outline band, as a proportional scale-up of the solid (e.g., .05 = 5% larger)"`
+}
+
+func (ol *OutlineParams) Defaults() {
+	ol.Color.SetUInt8(255, 200, 0, 255)
+	ol.Width = .05
 }
 
 var KiT_Solid = kit.Types.AddType(&Solid{}, SolidProps)
@@ -41,6 +62,9 @@ func (sld *Solid) CopyFieldsFrom(frm interface{}) {
 	sld.Node3DBase.CopyFieldsFrom(&fr.Node3DBase)
 	sld.Mesh = fr.Mesh
 	sld.Mat = fr.Mat
+	sld.MatNm = fr.MatNm
+	sld.Layer = fr.Layer
+	sld.Outline = fr.Outline
 	sld.MeshPtr = fr.MeshPtr
 }
 
@@ -58,6 +82,7 @@ func (sld *Solid) AsSolid() *Solid {
 func (sld *Solid) Defaults() {
 	sld.Pose.Defaults()
 	sld.Mat.Defaults()
+	sld.Outline.Defaults()
 }
 
 // SetMeshName sets mesh to given mesh name.
@@ -86,6 +111,25 @@ func (sld *Solid) SetMesh(sc *Scene, ms Mesh) {
 	}
 }
 
+// ChangeMesh changes the mesh to given mesh name at runtime, e.g., for
+// morphing between shapes or swapping LOD meshes.  It re-links the mesh
+// reference, initializes the new mesh on the GPU if it has not yet been
+// used, and triggers a re-render -- unlike a full Scene.Update, it does
+// not re-initialize any of the other meshes.
+func (sld *Solid) ChangeMesh(sc *Scene, meshName string) error {
+	err := sld.SetMeshName(sc, meshName)
+	if err != nil {
+		return err
+	}
+	updt := sld.UpdateStart()
+	if mb := sld.MeshPtr.AsMeshBase(); mb.Buff == nil { // not yet on GPU
+		sc.InitMesh(meshName)
+	}
+	sld.UpdateMeshBBox()
+	sld.UpdateEnd(updt) // triggers re-render -- don't need a full Update() which updates meshes
+	return nil
+}
+
 func (sld *Solid) Init3D(sc *Scene) {
 	err := sld.Validate(sc)
 	if err != nil {
@@ -106,7 +150,29 @@ func (sld *Solid) ParentMaterial() *Material {
 	return &(psi.(*Solid).Mat)
 }
 
+// SetMaterialByName sets the material of this solid from the shared
+// materials library on the Scene (see Scene.AddMaterial), copying the
+// shared values into Mat.  The name reference is retained, so subsequent
+// edits to the shared material are re-copied during styling, updating
+// all solids that reference it.
+func (sld *Solid) SetMaterialByName(sc *Scene, matName string) error {
+	mt, err := sc.MaterialByNameTry(matName)
+	if err != nil {
+		err = fmt.Errorf("gi3d.Solid: %s SetMaterialByName: %v", sld.PathUnique(), err)
+		log.Println(err)
+		return err
+	}
+	sld.MatNm = MatName(matName)
+	sld.Mat = *mt
+	return nil
+}
+
 func (sld *Solid) Style3D(sc *Scene) {
+	if sld.MatNm != "" { // re-sync from shared material -- picks up any edits
+		if mt := sc.MaterialByName(string(sld.MatNm)); mt != nil {
+			sld.Mat = *mt
+		}
+	}
 	styprops := *sld.Properties()
 	parMat := sld.ParentMaterial()
 	sld.Mat.SetMatProps(parMat, styprops, sc.Viewport)
@@ -186,22 +252,59 @@ func (sld *Solid) RenderClass() RenderClasses {
 
 // Render3D activates this solid for rendering
 func (sld *Solid) Render3D(sc *Scene, rc RenderClasses, rnd Render) {
+	if crnd, ok := sld.Mat.Custom.(*RenderCustom); ok {
+		crnd.Activate(&sc.Renders)
+		crnd.SetMat(&sld.Mat, sc)
+		sc.Renders.SetMatrix(&sld.Pose)
+		sld.MeshPtr.Render3D(sc)
+		gpu.TheGPU.ErrCheck("sld custom render")
+		rnd.Activate(&sc.Renders) // restore standard program for subsequent solids
+		return
+	}
 	switch rc {
 	case RClassOpaqueUniform, RClassTransUniform:
 		rndu := rnd.(*RenderUniformColor)
 		rndu.SetMat(&sld.Mat, sc)
+		rndu.SetSkin(sld.Skel)
+		rndu.SetEnv(&sc.Renders)
 	case RClassOpaqueVertex, RClassTransVertex:
 		rndv := rnd.(*RenderVertexColor)
 		rndv.SetMat(&sld.Mat, sc)
+		rndv.SetSkin(sld.Skel)
+		rndv.SetEnv(&sc.Renders)
 	case RClassOpaqueTexture, RClassTransTexture:
 		rndt := rnd.(*RenderTexture)
 		rndt.SetMat(&sld.Mat, sc)
+		rndt.SetSkin(sld.Skel)
+		rndt.SetEnv(&sc.Renders)
 	}
 	sc.Renders.SetMatrix(&sld.Pose)
 	sld.MeshPtr.Render3D(sc)
 	gpu.TheGPU.ErrCheck("sld render")
 }
 
+// RenderOutline renders the outline pass for this solid: the mesh is
+// re-rendered scaled up by the outline Width, in the outline Color, with
+// front faces culled so only the silhouette band shows around the normal
+// render.  The RenderUniformColor program must already be activated.
+func (sld *Solid) RenderOutline(sc *Scene, rnd *RenderUniformColor) {
+	rnd.SetColor(sld.Outline.Color)
+	ps := sld.Pose // copy
+	ps.Scale = ps.Scale.MulScalar(1 + sld.Outline.Width)
+	ps.UpdateMatrix()
+	ps.WorldMatrix.MulMatrices(&ps.ParMatrix, &ps.Matrix)
+	prjn := &sc.Camera.PrjnMatrix
+	if sld.Layer == LayerViewModel {
+		prjn = &sc.Camera.VMPrjnMatrix // match the layer's near plane
+	}
+	ps.UpdateMVPMatrix(&sc.Camera.ViewMatrix, prjn)
+	sc.Renders.SetMatrix(&ps)
+	gpu.Draw.CullFace(true, false, true) // cull front faces -- only the hull's far side shows
+	sld.MeshPtr.Render3D(sc)
+	gpu.Draw.CullFace(false, true, true) // restore standard back face culling
+	gpu.TheGPU.ErrCheck("sld render outline")
+}
+
 var SolidProps = ki.Props{
 	"EnumType:Flag": gi.KiT_NodeFlags,
 }
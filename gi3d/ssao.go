@@ -0,0 +1,386 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"math/rand"
+
+	"github.com/goki/gi/mat32"
+	"github.com/goki/gi/oswin/gpu"
+)
+
+// SSAOSamples is the number of hemisphere kernel samples used per pixel
+// in the SSAO occlusion pass.
+const SSAOSamples = 16
+
+// SSAOParams are the parameters for the SSAO (screen-space ambient
+// occlusion) post-process pass -- see Scene.SSAO.
+type SSAOParams struct {
+	Radius    float32 `desc:"sampling radius of the occlusion hemisphere, in world units -- should be on the order of the feature size of the scene (e.g., .5 for meter-scale scenes)"`
+	Intensity float32 `desc:"strength of the occlusion darkening -- 1 = standard, higher exaggerates the effect, 0 = off"`
+}
+
+func (sp *SSAOParams) Defaults() {
+	sp.Radius = 0.5
+	sp.Intensity = 1
+}
+
+// ssaoKernel is the shared hemisphere sampling kernel, generated once:
+// random directions in the +Z hemisphere, with lengths biased toward the
+// center so close-range occlusion dominates.  A fixed seed keeps the
+// pattern (and thus the rendered output) reproducible across runs.
+var ssaoKernel []mat32.Vec3
+
+// SSAOKernelSamples returns the shared hemisphere sampling kernel,
+// generating it on first use.
+func SSAOKernelSamples() []mat32.Vec3 {
+	if ssaoKernel != nil {
+		return ssaoKernel
+	}
+	rs := rand.New(rand.NewSource(1))
+	ssaoKernel = make([]mat32.Vec3, SSAOSamples)
+	for i := range ssaoKernel {
+		v := mat32.Vec3{X: rs.Float32()*2 - 1, Y: rs.Float32()*2 - 1, Z: rs.Float32()}
+		v.SetNormal()
+		scale := float32(i) / float32(SSAOSamples)
+		scale = 0.1 + 0.9*scale*scale
+		ssaoKernel[i] = v.MulScalar(scale)
+	}
+	return ssaoKernel
+}
+
+//////////////////////////////////////////////////////////////////////////
+//    RenderDepthNorm
+
+// RenderDepthNorm renders the view-space normals and linear depth of the
+// opaque scene geometry into a color texture (the "gbuffer" for the SSAO
+// pass): normal X,Y in the R,G channels (Z is reconstructed), and depth
+// normalized by the camera far plane packed 16-bit into B,A.  Skinned
+// meshes are deformed the same as in the main render, so they occlude
+// correctly.
+type RenderDepthNorm struct {
+	RenderBase
+}
+
+func (rb *RenderDepthNorm) Init(rn *Renderers) error {
+	rb.Nm = "RenderDepthNorm"
+	if rb.Pipe == nil {
+		rb.Pipe = gpu.TheGPU.NewPipeline(rb.Nm)
+		rb.Pipe.AddProgram("VtxFrag")
+	}
+	pl := rb.Pipe
+	pr := pl.ProgramByName("VtxFrag")
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx", RenderUniCamera+RenderSkin+
+		`
+layout(location = 0) in vec3 VtxPos;
+layout(location = 1) in vec3 VtxNorm;
+out vec4 Pos;
+out vec3 Norm;
+
+void main() {
+	vec4 vPos = vec4(VtxPos, 1.0);
+	vec3 vNorm = VtxNorm;
+	skinPosNorm(vPos, vNorm);
+	Pos = MVMatrix * vPos;
+	Norm = normalize(NormMatrix * vNorm);
+	gl_ClipDistance[0] = dot(Pos.xyz, ClipPlane.xyz) + ClipPlane.w;
+
+	gl_Position = MVPMatrix * vPos;
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.AddShader(gpu.FragmentShader, "Frag",
+		`
+uniform float CamFar;
+in vec4 Pos;
+in vec3 Norm;
+out vec4 outputColor;
+
+void main() {
+	vec3 n = normalize(Norm);
+	float d = clamp(-Pos.z / CamFar, 0.0, 1.0);
+	vec2 dp = vec2(d, fract(d * 255.0));
+	dp.x -= dp.y / 255.0;
+	outputColor = vec4(n.xy * 0.5 + 0.5, dp);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	pr.AddUniforms(rn.Unis["Camera"])
+	pr.AddUniform("CamFar", gpu.FUniType, false, 0)
+	pr.AddUniform("SkinMats", gpu.Mat4fUniType, true, MaxSkinJoints)
+	pr.AddUniform("UseSkin", gpu.BUniType, false, 0)
+
+	pr.SetFragDataVar("outputColor")
+
+	return nil
+}
+
+// Activate activates the depth / normal program -- binds the Camera
+// uniforms but not the Lights, which it does not use.
+func (rb *RenderDepthNorm) Activate(rn *Renderers) {
+	pr := rb.VtxFragProg()
+	pr.Activate()
+	gpu.TheGPU.ErrCheck("depthnorm prog activate")
+	cmu := rn.Unis["Camera"]
+	cmu.Activate()
+	cmu.Bind(pr)
+	gpu.TheGPU.ErrCheck("depthnorm camera bind")
+	pr.Activate()
+}
+
+//////////////////////////////////////////////////////////////////////////
+//    RenderSSAO
+
+// RenderSSAO computes per-pixel ambient occlusion from the depth / normal
+// texture produced by RenderDepthNorm: view-space position is
+// reconstructed from the depth, and a hemisphere of kernel samples
+// oriented along the surface normal is tested against the depth buffer --
+// samples behind nearby geometry count as occluded.  The raw (noisy)
+// occlusion factor is written to a texture for the blur / composite pass
+// (RenderSSAOBlur).  See Scene.SSAO, SSAOParams.
+type RenderSSAO struct {
+	RenderBase
+	Quad gpu.BufferMgr `desc:"fullscreen quad (2 triangles) used to drive the fragment shader"`
+}
+
+func (rb *RenderSSAO) Init(rn *Renderers) error {
+	rb.Nm = "RenderSSAO"
+	if rb.Pipe == nil {
+		rb.Pipe = gpu.TheGPU.NewPipeline(rb.Nm)
+		rb.Pipe.AddProgram("VtxFrag")
+	}
+	pl := rb.Pipe
+	pr := pl.ProgramByName("VtxFrag")
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx",
+		`
+layout(location = 0) in vec3 VtxPos;
+uniform mat4 InvPrjnMatrix;
+out vec2 TexCoord;
+out vec3 ViewRay;
+
+void main() {
+	TexCoord = VtxPos.xy * 0.5 + 0.5;
+	// view-space ray through this corner of the frustum, scaled to z = -1,
+	// so viewPos = ViewRay * linearDepth
+	vec4 vr = InvPrjnMatrix * vec4(VtxPos.xy, 1.0, 1.0);
+	ViewRay = vr.xyz / -vr.z;
+	gl_Position = vec4(VtxPos.xy, 0.0, 1.0);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.AddShader(gpu.FragmentShader, "Frag",
+		`
+uniform sampler2D DepthNorm;
+uniform mat4 PrjnMatrix;
+uniform vec3 Samples[SAMPLES_LEN];
+uniform float CamFar;
+uniform float Radius;
+in vec2 TexCoord;
+in vec3 ViewRay;
+out vec4 outputColor;
+
+float unpackDepth(vec2 dp) {
+	return dp.x + dp.y / 255.0;
+}
+
+void main() {
+	vec4 dn = texture(DepthNorm, TexCoord);
+	float d = unpackDepth(dn.ba);
+	if (d >= 1.0) { // background
+		outputColor = vec4(1.0);
+		return;
+	}
+	vec3 pos = ViewRay * (d * CamFar);
+	vec2 nxy = dn.rg * 2.0 - 1.0;
+	vec3 norm = vec3(nxy, sqrt(max(1.0 - dot(nxy, nxy), 0.0)));
+
+	// per-pixel random rotation of the kernel around the normal -- the
+	// resulting noise is removed by the blur pass
+	float ang = fract(sin(dot(gl_FragCoord.xy, vec2(12.9898, 78.233))) * 43758.5453) * 6.28318;
+	vec3 rvec = vec3(cos(ang), sin(ang), 0.0);
+	vec3 tangent = normalize(rvec - norm * dot(rvec, norm));
+	mat3 tbn = mat3(tangent, cross(norm, tangent), norm);
+
+	float occ = 0.0;
+	for (int i = 0; i < SAMPLES_LEN; i++) {
+		vec3 sp = pos + tbn * Samples[i] * Radius;
+		vec4 clip = PrjnMatrix * vec4(sp, 1.0);
+		vec2 suv = (clip.xy / clip.w) * 0.5 + 0.5;
+		if (suv != clamp(suv, 0.0, 1.0)) {
+			continue;
+		}
+		float sd = unpackDepth(texture(DepthNorm, suv).ba) * CamFar;
+		float rangeCheck = smoothstep(0.0, 1.0, Radius / abs(-pos.z - sd));
+		occ += (sd < -sp.z - 0.02 ? 1.0 : 0.0) * rangeCheck;
+	}
+	float ao = 1.0 - occ / float(SAMPLES_LEN);
+	outputColor = vec4(vec3(ao), 1.0);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	pr.AddUniform("DepthNorm", gpu.IUniType, false, 0)
+	pr.AddUniform("PrjnMatrix", gpu.Mat4fUniType, false, 0)
+	pr.AddUniform("InvPrjnMatrix", gpu.Mat4fUniType, false, 0)
+	pr.AddUniform("Samples", gpu.Vec3fUniType, true, SSAOSamples)
+	pr.AddUniform("CamFar", gpu.FUniType, false, 0)
+	pr.AddUniform("Radius", gpu.FUniType, false, 0)
+
+	pr.SetFragDataVar("outputColor")
+
+	return nil
+}
+
+// Activate activates the SSAO program -- unlike the material renders, it
+// does not bind the Camera / Lights uniforms, which it does not use.
+func (rb *RenderSSAO) Activate(rn *Renderers) {
+	pr := rb.VtxFragProg()
+	pr.Activate()
+	gpu.TheGPU.ErrCheck("ssao prog activate")
+}
+
+// InitQuad initializes the fullscreen quad buffer if not already done.
+// Must be called with appropriate context activated, on main.
+func (rb *RenderSSAO) InitQuad(rn *Renderers) {
+	if rb.Quad != nil {
+		return
+	}
+	rb.Quad = gpu.TheGPU.NewBufferMgr()
+	vbuf := rb.Quad.AddVectorsBuffer(gpu.StaticDraw)
+	vbuf.AddVectors(rn.Vectors[InVtxPos], false)
+	vtxs := mat32.ArrayF32{
+		-1, -1, 0, 1, -1, 0, 1, 1, 0,
+		-1, -1, 0, 1, 1, 0, -1, 1, 0,
+	}
+	vbuf.SetLen(6)
+	vbuf.SetVecData(rn.Vectors[InVtxPos], vtxs)
+	rb.Quad.Activate()
+	rb.Quad.TransferAll()
+}
+
+func (rb *RenderSSAO) Delete(rn *Renderers) {
+	if rb.Quad != nil {
+		rb.Quad.Delete()
+		rb.Quad = nil
+	}
+	rb.RenderBase.Delete(rn)
+}
+
+//////////////////////////////////////////////////////////////////////////
+//    RenderSSAOBlur
+
+// RenderSSAOBlur is the final SSAO pass: box-blurs the raw occlusion
+// texture from RenderSSAO (removing the per-pixel rotation noise) and
+// multiplies the scene color by the result, approximating the reduction
+// in ambient lighting within occluded corners and creases.
+type RenderSSAOBlur struct {
+	RenderBase
+	Quad gpu.BufferMgr `desc:"fullscreen quad (2 triangles) used to drive the fragment shader"`
+}
+
+func (rb *RenderSSAOBlur) Init(rn *Renderers) error {
+	rb.Nm = "RenderSSAOBlur"
+	if rb.Pipe == nil {
+		rb.Pipe = gpu.TheGPU.NewPipeline(rb.Nm)
+		rb.Pipe.AddProgram("VtxFrag")
+	}
+	pl := rb.Pipe
+	pr := pl.ProgramByName("VtxFrag")
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx",
+		`
+layout(location = 0) in vec3 VtxPos;
+out vec2 TexCoord;
+
+void main() {
+	TexCoord = VtxPos.xy * 0.5 + 0.5;
+	gl_Position = vec4(VtxPos.xy, 0.0, 1.0);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.AddShader(gpu.FragmentShader, "Frag",
+		`
+uniform sampler2D Tex;
+uniform sampler2D AOTex;
+uniform vec2 InvSize;
+uniform float Intensity;
+in vec2 TexCoord;
+out vec4 outputColor;
+
+void main() {
+	float ao = 0.0;
+	for (int x = -2; x < 2; x++) {
+		for (int y = -2; y < 2; y++) {
+			ao += texture(AOTex, TexCoord + vec2(float(x), float(y)) * InvSize).r;
+		}
+	}
+	ao /= 16.0;
+	ao = clamp(1.0 - Intensity * (1.0 - ao), 0.0, 1.0);
+	vec4 clr = texture(Tex, TexCoord);
+	outputColor = vec4(clr.rgb * ao, clr.a);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	pr.AddUniform("Tex", gpu.IUniType, false, 0)
+	pr.AddUniform("AOTex", gpu.IUniType, false, 0)
+	pr.AddUniform("InvSize", gpu.Vec2fUniType, false, 0)
+	pr.AddUniform("Intensity", gpu.FUniType, false, 0)
+
+	pr.SetFragDataVar("outputColor")
+
+	return nil
+}
+
+// Activate activates the SSAO blur / composite program -- unlike the
+// material renders, it does not bind the Camera / Lights uniforms, which
+// it does not use.
+func (rb *RenderSSAOBlur) Activate(rn *Renderers) {
+	pr := rb.VtxFragProg()
+	pr.Activate()
+	gpu.TheGPU.ErrCheck("ssao blur prog activate")
+}
+
+// InitQuad initializes the fullscreen quad buffer if not already done.
+// Must be called with appropriate context activated, on main.
+func (rb *RenderSSAOBlur) InitQuad(rn *Renderers) {
+	if rb.Quad != nil {
+		return
+	}
+	rb.Quad = gpu.TheGPU.NewBufferMgr()
+	vbuf := rb.Quad.AddVectorsBuffer(gpu.StaticDraw)
+	vbuf.AddVectors(rn.Vectors[InVtxPos], false)
+	vtxs := mat32.ArrayF32{
+		-1, -1, 0, 1, -1, 0, 1, 1, 0,
+		-1, -1, 0, 1, 1, 0, -1, 1, 0,
+	}
+	vbuf.SetLen(6)
+	vbuf.SetVecData(rn.Vectors[InVtxPos], vtxs)
+	rb.Quad.Activate()
+	rb.Quad.TransferAll()
+}
+
+func (rb *RenderSSAOBlur) Delete(rn *Renderers) {
+	if rb.Quad != nil {
+		rb.Quad.Delete()
+		rb.Quad = nil
+	}
+	rb.RenderBase.Delete(rn)
+}
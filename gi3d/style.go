@@ -133,6 +133,20 @@ var StyleMatFuncs = map[string]gi.StyleFunc{
 			mt.Bright = iv
 		}
 	},
+	"unlit": func(obj interface{}, key string, val interface{}, par interface{}, vp *gi.Viewport2D) {
+		mt := obj.(*Material)
+		if inh, init := gi.StyleInhInit(val, par); inh || init {
+			if inh {
+				mt.Unlit = par.(*Material).Unlit
+			} else if init {
+				mt.Unlit = false
+			}
+			return
+		}
+		if bv, ok := kit.ToBool(val); ok {
+			mt.Unlit = bv
+		}
+	},
 	"texture": func(obj interface{}, key string, val interface{}, par interface{}, vp *gi.Viewport2D) {
 		mt := obj.(*Material)
 		if inh, init := gi.StyleInhInit(val, par); inh || init {
@@ -58,7 +58,7 @@ func (txt *Text2D) Defaults(sc *Scene) {
 	txt.SetProp("margin", units.NewPx(2))
 	txt.SetProp("color", "black")
 	txt.SetProp("background-color", gi.Color{0, 0, 0, 0})
-	txt.Mat.Bright = 5 // this is key for making e.g., a white background show up as white..
+	txt.Mat.Unlit = true // text color should not depend on scene lighting
 }
 
 func (txt *Text2D) Disconnect() {
@@ -6,7 +6,15 @@ package gi3d
 
 import (
 	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
 	"log"
+	"os"
+	"sync"
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/oswin/gpu"
@@ -49,6 +57,18 @@ type Texture interface {
 
 	// SetTransparent sets the transparency flag for this texture.
 	SetTransparent(trans bool)
+
+	// IsSRGB returns true if this texture is uploaded to the GPU in the
+	// sRGB color space, so the GPU linearizes colors when sampling.
+	IsSRGB() bool
+
+	// SetSRGB sets whether this texture is uploaded to the GPU in the sRGB
+	// color space.  Color images (diffuse / albedo maps) are authored in
+	// sRGB and should set this so lighting math operates on linear values
+	// -- pair with Scene.SRGBOut for correct output gamma.  Data textures
+	// such as normal, roughness or metallic maps encode non-color values
+	// and must remain linear (off, the default).
+	SetSRGB(srgb bool)
 }
 
 //////////////////////////////////////////////////////////////////////////////////////
@@ -59,6 +79,7 @@ type TextureBase struct {
 	Nm    string        `desc:"name of the texture -- textures are connected to material by name"`
 	Bot0  bool          `desc:"set to true if this texture has Y=0 at the bottom -- otherwise default is Y=0 is at top as is the case in most images loaded from files etc"`
 	Trans bool          `desc:"set to true if texture has transparency"`
+	SRGB  bool          `desc:"set to true to upload this texture in the sRGB color space, so the GPU linearizes colors when sampling -- use for color images (diffuse / albedo maps), pairing with Scene.SRGBOut -- data textures such as normal, roughness or metallic maps must remain linear (off)"`
 	Tex   gpu.Texture2D `view:"-" desc:"gpu texture object"`
 }
 
@@ -87,6 +108,20 @@ func (tx *TextureBase) SetTransparent(trans bool) {
 	tx.Trans = trans
 }
 
+func (tx *TextureBase) IsSRGB() bool {
+	return tx.SRGB
+}
+
+// SetSRGB sets the sRGB color space flag -- if the texture is already on
+// the GPU, the image is re-uploaded with the new internal format, which
+// must be done in context on main thread
+func (tx *TextureBase) SetSRGB(srgb bool) {
+	tx.SRGB = srgb
+	if tx.Tex != nil {
+		tx.Tex.SetSRGB(tx.SRGB)
+	}
+}
+
 // makes a new gpu.Texture2D if Tex field is nil, and returns it in any case
 func (tx *TextureBase) NewTex() gpu.Texture2D {
 	if tx.Tex != nil {
@@ -94,6 +129,7 @@ func (tx *TextureBase) NewTex() gpu.Texture2D {
 	}
 	tx.Tex = gpu.TheGPU.NewTexture2D(tx.Nm)
 	tx.Tex.SetBotZero(tx.Bot0)
+	tx.Tex.SetSRGB(tx.SRGB)
 	return tx.Tex
 }
 
@@ -103,6 +139,7 @@ func (tx *TextureBase) NewTex() gpu.Texture2D {
 func (tx *TextureBase) Init(sc *Scene) error {
 	if tx.Tex != nil {
 		tx.Tex.SetBotZero(tx.Bot0)
+		tx.Tex.SetSRGB(tx.SRGB)
 		tx.Tex.Activate(0)
 	}
 	return nil
@@ -113,6 +150,7 @@ func (tx *TextureBase) Init(sc *Scene) error {
 func (tx *TextureBase) Activate(sc *Scene, texNo int) {
 	if tx.Tex != nil {
 		tx.Tex.SetBotZero(tx.Bot0)
+		tx.Tex.SetSRGB(tx.SRGB)
 		tx.Tex.Activate(texNo)
 	}
 }
@@ -144,11 +182,101 @@ func AddNewTextureFile(sc *Scene, name string, filename string) *TextureFile {
 	return tx
 }
 
+// texFileCacheEnt is one reference-counted entry in the texture file cache
+type texFileCacheEnt struct {
+	tex  gpu.Texture2D
+	refs int
+}
+
+var (
+	// texFileCache caches the decoded, GPU-uploaded textures by file path,
+	// so that multiple TextureFile users of the same file (within or across
+	// scenes -- all windows share one GPU context) share a single texture
+	// instead of re-opening and re-decoding the file each time
+	texFileCache   = map[string]*texFileCacheEnt{}
+	texFileCacheMu sync.Mutex
+)
+
+// TexFileCacheBypass can be set to true to bypass the texture file cache,
+// so that every TextureFile loads its file directly -- for development
+// situations where the file contents change between loads.
+var TexFileCacheBypass = false
+
+// TexFileCacheInvalidate removes any cached texture for the given file path,
+// so that the next load re-opens and re-decodes the file -- textures already
+// initialized keep the previously-loaded image until they are deleted and
+// re-initialized.  Use for hot-reloading texture assets during development.
+func TexFileCacheInvalidate(path string) {
+	texFileCacheMu.Lock()
+	delete(texFileCache, texFileCacheKey(path, false))
+	delete(texFileCache, texFileCacheKey(path, true))
+	texFileCacheMu.Unlock()
+}
+
+// texFileCacheKey returns the cache key for given file path and sRGB
+// setting -- the color space is part of the key because it determines the
+// GPU internal format of the uploaded texture.
+func texFileCacheKey(path string, srgb bool) string {
+	if srgb {
+		return path + "|srgb"
+	}
+	return path
+}
+
+// texFileCacheOpen returns the texture for given file path, either from the
+// cache (incrementing its reference count) or freshly opened and cached.
+// Must be called in context on main thread
+func texFileCacheOpen(nm, path string, botZero, srgb bool) (gpu.Texture2D, error) {
+	key := texFileCacheKey(path, srgb)
+	texFileCacheMu.Lock()
+	if !TexFileCacheBypass {
+		if ent, has := texFileCache[key]; has {
+			ent.refs++
+			texFileCacheMu.Unlock()
+			return ent.tex, nil
+		}
+	}
+	texFileCacheMu.Unlock()
+	tex := gpu.TheGPU.NewTexture2D(nm)
+	tex.SetBotZero(botZero)
+	tex.SetSRGB(srgb)
+	err := tex.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !TexFileCacheBypass {
+		texFileCacheMu.Lock()
+		texFileCache[key] = &texFileCacheEnt{tex: tex, refs: 1}
+		texFileCacheMu.Unlock()
+	}
+	return tex, nil
+}
+
+// texFileCacheRelease decrements the reference count for given file path
+// and sRGB setting, returning true if the caller holds the last reference
+// and should delete the GPU resources (also true for uncached textures,
+// which the caller owns).
+func texFileCacheRelease(path string, srgb bool) bool {
+	texFileCacheMu.Lock()
+	defer texFileCacheMu.Unlock()
+	ent, has := texFileCache[texFileCacheKey(path, srgb)]
+	if !has {
+		return true
+	}
+	ent.refs--
+	if ent.refs <= 0 {
+		delete(texFileCache, texFileCacheKey(path, srgb))
+		return true
+	}
+	return false
+}
+
 // Init initializes the texture, opens the file, and uploads it to the GPU
 // Must be called in context on main thread
 func (tx *TextureFile) Init(sc *Scene) error {
 	if tx.Tex != nil {
 		tx.Tex.SetBotZero(tx.Bot0)
+		tx.Tex.SetSRGB(tx.SRGB)
 		tx.Tex.Activate(0)
 		return nil
 	}
@@ -157,17 +285,52 @@ func (tx *TextureFile) Init(sc *Scene) error {
 		log.Println(err)
 		return err
 	}
-	tx.Tex = gpu.TheGPU.NewTexture2D(tx.Nm)
+	tex, err := texFileCacheOpen(tx.Nm, string(tx.File), tx.Bot0, tx.SRGB)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	tx.Tex = tex
 	tx.Tex.SetBotZero(tx.Bot0)
+	tx.Tex.Activate(0)
+	return nil
+}
+
+// Reload re-opens the source image file and re-uploads it to the GPU
+// texture, so that changes to the file appear without restarting the app --
+// because the texture object is shared through the file cache, all users of
+// the same file update together.  See also Scene.WatchTextures for automatic
+// reload on file change.
+// Must be called in context on main thread
+func (tx *TextureFile) Reload(sc *Scene) error {
+	if tx.Tex == nil {
+		return tx.Init(sc)
+	}
 	err := tx.Tex.Open(string(tx.File))
 	if err != nil {
 		log.Println(err)
 		return err
 	}
+	tx.Tex.SetBotZero(tx.Bot0)
 	tx.Tex.Activate(0)
 	return nil
 }
 
+// Delete releases this texture's reference on the shared cached texture for
+// its file, deleting the GPU resources only when the last user is deleted --
+// must be called in context on main thread
+func (tx *TextureFile) Delete(sc *Scene) {
+	if tx.Tex == nil {
+		return
+	}
+	if texFileCacheRelease(string(tx.File), tx.SRGB) {
+		if tx.Tex.IsActive() {
+			tx.Tex.Delete()
+		}
+	}
+	tx.Tex = nil
+}
+
 // Activate activates this texture on the GPU, in preparation for rendering
 // Must be called in context on main thread
 func (tx *TextureFile) Activate(sc *Scene, texNo int) {
@@ -178,6 +341,235 @@ func (tx *TextureFile) Activate(sc *Scene, texNo int) {
 	tx.Tex.Activate(texNo)
 }
 
+//////////////////////////////////////////////////////////////////////////////////////
+// TextureImage
+
+// TextureImage is a texture initialized from a static image.Image, e.g.,
+// decoded from an embedded filesystem, zip archive, or network stream --
+// see AddNewTextureReader, AddNewTextureImage.
+type TextureImage struct {
+	TextureBase
+	Img image.Image `view:"-" desc:"image that the texture is initialized from"`
+}
+
+var KiT_TextureImage = kit.Types.AddType(&TextureImage{}, nil)
+
+// AddNewTextureImage adds a new texture of given name to the scene,
+// initialized from the given image.
+func AddNewTextureImage(sc *Scene, name string, img image.Image) *TextureImage {
+	tx := &TextureImage{}
+	tx.Nm = name
+	tx.Img = img
+	sc.AddTexture(tx)
+	return tx
+}
+
+// AddNewTextureReader adds a new texture of given name to the scene, with
+// the image decoded from the given reader, e.g., a file opened from an
+// embedded filesystem (go:embed), a zip archive, or an http response body.
+// The image is decoded immediately, with the format inferred from the
+// contents -- same formats as AddNewTextureFile are supported.
+// Returns nil and logs an error if decoding fails.
+func AddNewTextureReader(sc *Scene, name string, r io.Reader) (*TextureImage, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		err = fmt.Errorf("gi3d.AddNewTextureReader: %v decoding image: %v", name, err)
+		log.Println(err)
+		return nil, err
+	}
+	return AddNewTextureImage(sc, name, img), nil
+}
+
+// Init initializes the texture from its image and uploads it to the GPU
+// Must be called in context on main thread
+func (tx *TextureImage) Init(sc *Scene) error {
+	if tx.Tex != nil {
+		tx.Tex.SetBotZero(tx.Bot0)
+		tx.Tex.Activate(0)
+		return nil
+	}
+	if tx.Img == nil {
+		err := fmt.Errorf("gi3d.Texture: %v Img must be set to an image to load texture from", tx.Nm)
+		log.Println(err)
+		return err
+	}
+	tx.Tex = gpu.TheGPU.NewTexture2D(tx.Nm)
+	tx.Tex.SetBotZero(tx.Bot0)
+	err := tx.Tex.SetImage(tx.Img)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	tx.Tex.Activate(0)
+	return nil
+}
+
+// Activate activates this texture on the GPU, in preparation for rendering
+// Must be called in context on main thread
+func (tx *TextureImage) Activate(sc *Scene, texNo int) {
+	if tx.Tex == nil {
+		tx.Init(sc)
+	}
+	tx.Tex.SetBotZero(tx.Bot0)
+	tx.Tex.Activate(texNo)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//   TextureAnim
+
+// TextureAnim is a texture that cycles through a sequence of image frames,
+// e.g., from an animated GIF or a list of image files.  Stepping through
+// the frames is driven by the app, e.g., on a timer or per render, by
+// calling NextFrame or SetFrame, which upload the new frame to the GPU.
+type TextureAnim struct {
+	TextureBase
+	Frames   []image.Image `view:"-" desc:"the sequence of frame images"`
+	Delays   []int         `desc:"per-frame delay in 100ths of a second, as in GIF -- same length as Frames if set -- 0 = no delay info"`
+	CurFrame int           `desc:"index of the frame currently shown"`
+}
+
+var KiT_TextureAnim = kit.Types.AddType(&TextureAnim{}, nil)
+
+// AddNewTextureAnim adds a new animated texture of given name to scene,
+// with no frames -- use OpenGIF or AddFrame to populate.
+func AddNewTextureAnim(sc *Scene, name string) *TextureAnim {
+	tx := &TextureAnim{}
+	tx.Nm = name
+	sc.AddTexture(tx)
+	return tx
+}
+
+// AddFrame adds a frame image to the sequence, with given delay in
+// 100ths of a second (0 for none).
+func (tx *TextureAnim) AddFrame(img image.Image, delay int) {
+	tx.Frames = append(tx.Frames, img)
+	tx.Delays = append(tx.Delays, delay)
+}
+
+// OpenGIF opens an animated GIF file, loading all of its frames
+// (composited, so partial frames display correctly), replacing any
+// existing frames.
+func (tx *TextureAnim) OpenGIF(fname string) error {
+	file, err := os.Open(fname)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer file.Close()
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	nf := len(g.Image)
+	if nf == 0 {
+		err = fmt.Errorf("gi3d.TextureAnim: %v no frames in GIF file: %v", tx.Nm, fname)
+		log.Println(err)
+		return err
+	}
+	tx.Frames = make([]image.Image, 0, nf)
+	tx.Delays = make([]int, 0, nf)
+	rect := image.Rectangle{Max: image.Point{g.Config.Width, g.Config.Height}}
+	acc := image.NewRGBA(rect)
+	for fi, fr := range g.Image {
+		draw.Draw(acc, fr.Bounds(), fr, fr.Bounds().Min, draw.Over)
+		frm := image.NewRGBA(rect)
+		copy(frm.Pix, acc.Pix)
+		dly := 0
+		if fi < len(g.Delay) {
+			dly = g.Delay[fi]
+		}
+		tx.AddFrame(frm, dly)
+	}
+	tx.CurFrame = 0
+	return nil
+}
+
+// SetFrame sets the current frame to given index (wraps around), and
+// uploads it to the GPU if the texture is initialized.
+// Must be called in context on main thread
+func (tx *TextureAnim) SetFrame(idx int) {
+	nf := len(tx.Frames)
+	if nf == 0 {
+		return
+	}
+	idx = idx % nf
+	if idx < 0 {
+		idx += nf
+	}
+	tx.CurFrame = idx
+	if tx.Tex != nil {
+		tx.Tex.SetImage(tx.Frames[idx])
+	}
+}
+
+// NextFrame advances to the next frame in the sequence, wrapping around,
+// and uploads it to the GPU if the texture is initialized.
+// Must be called in context on main thread
+func (tx *TextureAnim) NextFrame() {
+	tx.SetFrame(tx.CurFrame + 1)
+}
+
+// Init initializes the texture with the current frame, and uploads it to the GPU
+// Must be called in context on main thread
+func (tx *TextureAnim) Init(sc *Scene) error {
+	if tx.Tex != nil {
+		tx.Tex.SetBotZero(tx.Bot0)
+		tx.Tex.Activate(0)
+		return nil
+	}
+	if len(tx.Frames) == 0 {
+		err := fmt.Errorf("gi3d.TextureAnim: %v has no frames -- use OpenGIF or AddFrame first", tx.Nm)
+		log.Println(err)
+		return err
+	}
+	tx.Tex = gpu.TheGPU.NewTexture2D(tx.Nm)
+	tx.Tex.SetBotZero(tx.Bot0)
+	tx.Tex.SetImage(tx.Frames[tx.CurFrame])
+	tx.Tex.Activate(0)
+	return nil
+}
+
+// Activate activates this texture on the GPU, in preparation for rendering
+// Must be called in context on main thread
+func (tx *TextureAnim) Activate(sc *Scene, texNo int) {
+	if tx.Tex == nil {
+		tx.Init(sc)
+	}
+	tx.Tex.SetBotZero(tx.Bot0)
+	tx.Tex.Activate(texNo)
+}
+
+// OpenGIFFrame opens a GIF file and returns the given frame (0 = first) as
+// an image, compositing partial frames onto the accumulated image as needed.
+// The resulting image can be set on a gpu.Texture2D via SetImage, e.g., for
+// using a specific frame of an animated GIF as a texture.
+func OpenGIFFrame(fname string, frame int) (image.Image, error) {
+	file, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, err
+	}
+	nf := len(g.Image)
+	if nf == 0 {
+		return nil, fmt.Errorf("gi3d.OpenGIFFrame: no frames in GIF file: %v", fname)
+	}
+	if frame < 0 || frame >= nf {
+		return nil, fmt.Errorf("gi3d.OpenGIFFrame: frame %v out of range (%v frames) in GIF file: %v", frame, nf, fname)
+	}
+	rect := image.Rectangle{Max: image.Point{g.Config.Width, g.Config.Height}}
+	img := image.NewRGBA(rect)
+	for fi := 0; fi <= frame; fi++ {
+		fr := g.Image[fi]
+		draw.Draw(img, fr.Bounds(), fr, fr.Bounds().Min, draw.Over)
+	}
+	return img, nil
+}
+
 // TextureGi2D is a dynamic texture material driven by a gi.Viewport2D viewport
 // anything rendered to the viewport will be projected onto the surface of any
 // solid using this texture.
@@ -0,0 +1,137 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gi3d
+
+import (
+	"github.com/goki/gi/mat32"
+	"github.com/goki/gi/oswin/gpu"
+	"github.com/goki/ki/kit"
+)
+
+// ToneMapModes are the tone mapping operators for resolving rendered scene
+// color into display range -- see Scene.ToneMap
+type ToneMapModes int32
+
+const (
+	// ToneMapOff does no tone mapping: scene color is displayed as rendered
+	// (the default)
+	ToneMapOff ToneMapModes = iota
+
+	// ToneMapReinhard applies the Reinhard operator c / (c + 1): simple
+	// global compression that never clips, with gentle highlight rolloff
+	ToneMapReinhard
+
+	// ToneMapACES applies a filmic ACES approximation (Narkowicz fit):
+	// more contrast and saturation than Reinhard, with a film-like
+	// shoulder on bright highlights
+	ToneMapACES
+
+	ToneMapModesN
+)
+
+//go:generate stringer -type=ToneMapModes
+
+var KiT_ToneMapModes = kit.Enums.AddEnum(ToneMapModesN, kit.NotBitFlag, nil)
+
+// RenderToneMap implements the post-process tone mapping pass: the rendered
+// scene texture is drawn through a tone mapping fragment shader onto a
+// fullscreen quad, compressing bright color values into display range with
+// pleasing highlight rolloff -- even without full HDR rendering this
+// improves bright specular areas.  The operator and exposure are set by
+// Scene.ToneMap / Scene.Exposure.
+type RenderToneMap struct {
+	RenderBase
+	Quad gpu.BufferMgr `desc:"fullscreen quad (2 triangles) used to drive the fragment shader"`
+}
+
+func (rb *RenderToneMap) Init(rn *Renderers) error {
+	rb.Nm = "RenderToneMap"
+	if rb.Pipe == nil {
+		rb.Pipe = gpu.TheGPU.NewPipeline(rb.Nm)
+		rb.Pipe.AddProgram("VtxFrag")
+	}
+	pl := rb.Pipe
+	pr := pl.ProgramByName("VtxFrag")
+	_, err := pr.AddShader(gpu.VertexShader, "Vtx",
+		`
+layout(location = 0) in vec3 VtxPos;
+out vec2 TexCoord;
+
+void main() {
+	TexCoord = VtxPos.xy * 0.5 + 0.5;
+	gl_Position = vec4(VtxPos.xy, 0.0, 1.0);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.AddShader(gpu.FragmentShader, "Frag",
+		`
+uniform sampler2D Tex;
+uniform int Mode;
+uniform float Exposure;
+in vec2 TexCoord;
+out vec4 outputColor;
+
+void main() {
+	vec4 tc = texture(Tex, TexCoord);
+	vec3 c = tc.rgb * Exposure;
+	if (Mode == 1) { // Reinhard
+		c = c / (c + vec3(1.0));
+	} else if (Mode == 2) { // ACES filmic (Narkowicz fit)
+		c = clamp((c * (2.51 * c + 0.03)) / (c * (2.43 * c + 0.59) + 0.14), 0.0, 1.0);
+	}
+	outputColor = vec4(c, tc.a);
+}
+`+"\x00")
+	if err != nil {
+		return err
+	}
+
+	pr.AddUniform("Tex", gpu.IUniType, false, 0)
+	pr.AddUniform("Mode", gpu.IUniType, false, 0)
+	pr.AddUniform("Exposure", gpu.FUniType, false, 0)
+
+	pr.SetFragDataVar("outputColor")
+
+	return nil
+}
+
+// Activate activates the tone mapping program -- unlike the material
+// renders, it does not bind the Camera / Lights uniforms, which it does
+// not use.
+func (rb *RenderToneMap) Activate(rn *Renderers) {
+	pr := rb.VtxFragProg()
+	pr.Activate()
+	gpu.TheGPU.ErrCheck("tonemap prog activate")
+}
+
+// InitQuad initializes the fullscreen quad buffer if not already done.
+// Must be called with appropriate context activated, on main.
+func (rb *RenderToneMap) InitQuad(rn *Renderers) {
+	if rb.Quad != nil {
+		return
+	}
+	rb.Quad = gpu.TheGPU.NewBufferMgr()
+	vbuf := rb.Quad.AddVectorsBuffer(gpu.StaticDraw)
+	vbuf.AddVectors(rn.Vectors[InVtxPos], false)
+	vtxs := mat32.ArrayF32{
+		-1, -1, 0, 1, -1, 0, 1, 1, 0,
+		-1, -1, 0, 1, 1, 0, -1, 1, 0,
+	}
+	vbuf.SetLen(6)
+	vbuf.SetVecData(rn.Vectors[InVtxPos], vtxs)
+	rb.Quad.Activate()
+	rb.Quad.TransferAll()
+}
+
+func (rb *RenderToneMap) Delete(rn *Renderers) {
+	if rb.Quad != nil {
+		rb.Quad.Delete()
+		rb.Quad = nil
+	}
+	rb.RenderBase.Delete(rn)
+}
@@ -1021,3 +1021,57 @@ func (vv *NilValueView) ConfigWidget(widg gi.Node2D) {
 	sb.Tooltip, _ = vv.Tag("desc")
 	vv.UpdateWidget()
 }
+
+////////////////////////////////////////////////////////////////////////////////////////
+//  NilPtrValueView
+
+// NilPtrValueView presents a button to allocate a new instance of a nil
+// pointer's underlying type, so the value becomes editable -- e.g., for nil
+// pointer values in maps (map[string]*Config) or struct fields.  The new
+// instance is written back through SetValue, so it goes into the owning
+// map / struct, and ViewSig is emitted so the view can reconfigure to edit it.
+type NilPtrValueView struct {
+	ValueViewBase
+}
+
+var KiT_NilPtrValueView = kit.Types.AddType(&NilPtrValueView{}, nil)
+
+func (vv *NilPtrValueView) WidgetType() reflect.Type {
+	vv.WidgetTyp = gi.KiT_Action
+	return vv.WidgetTyp
+}
+
+func (vv *NilPtrValueView) UpdateWidget() {
+	if vv.Widget == nil {
+		return
+	}
+	ac := vv.Widget.(*gi.Action)
+	ac.SetText("nil -- alloc")
+}
+
+func (vv *NilPtrValueView) ConfigWidget(widg gi.Node2D) {
+	vv.Widget = widg
+	vv.StdConfigWidget(widg)
+	ac := vv.Widget.(*gi.Action)
+	ac.Tooltip = fmt.Sprintf("nil pointer -- click to allocate a new %v so it can be edited", kit.NonPtrType(vv.Value.Type()))
+	ac.SetProp("padding", units.NewPx(2))
+	ac.SetProp("margin", units.NewPx(2))
+	ac.SetProp("border-radius", units.NewPx(4))
+	ac.SetInactiveState(vv.This().(ValueView).IsInactive())
+	ac.ActionSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		vvv, _ := recv.Embed(KiT_NilPtrValueView).(*NilPtrValueView)
+		vvv.Alloc()
+	})
+	vv.UpdateWidget()
+}
+
+// Alloc allocates a new instance of the pointer's underlying type and sets
+// the value to it (writing back through the owning map / struct) -- emits
+// ViewSig (via SetValue) so the view can reconfigure to edit the new value.
+func (vv *NilPtrValueView) Alloc() {
+	if vv.This().(ValueView).IsInactive() {
+		return
+	}
+	typ := kit.NonPtrType(vv.Value.Type())
+	vv.SetValue(reflect.New(typ).Interface())
+}
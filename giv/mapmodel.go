@@ -0,0 +1,88 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package giv
+
+import (
+	"reflect"
+	"sync"
+)
+
+// MapModel is an interface that map-like containers can implement to be
+// viewed and edited in a MapView, as an alternative to a plain Go map value
+// -- e.g., a sync.Map (see SyncMapModel) or any user type providing
+// Get / Set / Delete access to its entries.  Pass the MapModel itself to
+// MapView.SetMap -- all add / delete / value edits are then routed through
+// these methods instead of reflection on a map value, so concurrent-safe or
+// computed backing stores can be used.  Note: keys are shown read-only in
+// model mode, as they cannot be renamed in-place through this interface --
+// delete and re-add instead.
+type MapModel interface {
+	// Keys returns all current keys in the map -- order does not matter,
+	// as the view sorts for display.
+	Keys() []interface{}
+
+	// Get returns the value for given key, and whether it was present.
+	Get(key interface{}) (interface{}, bool)
+
+	// Set sets the value for given key, adding the entry if not yet present.
+	Set(key, val interface{})
+
+	// Delete removes the entry with given key.
+	Delete(key interface{})
+
+	// KeyType returns the type of the keys, e.g., for making a new zero
+	// key when adding an entry.
+	KeyType() reflect.Type
+
+	// ElemType returns the type of the values, e.g., for making a new zero
+	// value when adding an entry.
+	ElemType() reflect.Type
+}
+
+// SyncMapModel is a MapModel adapter for a sync.Map.  Because sync.Map is
+// untyped, the key and element types must be supplied, for making new
+// entries and appropriate value widgets.
+type SyncMapModel struct {
+	Map     *sync.Map    `desc:"the sync.Map we are an adapter for"`
+	KeyTyp  reflect.Type `desc:"type of the map keys"`
+	ElemTyp reflect.Type `desc:"type of the map values"`
+}
+
+var _ MapModel = (*SyncMapModel)(nil)
+
+// NewSyncMapModel returns a MapModel adapter for given sync.Map, with given
+// key and element types -- pass the result to MapView.SetMap.
+func NewSyncMapModel(mp *sync.Map, keyTyp, elemTyp reflect.Type) *SyncMapModel {
+	return &SyncMapModel{Map: mp, KeyTyp: keyTyp, ElemTyp: elemTyp}
+}
+
+func (sm *SyncMapModel) Keys() []interface{} {
+	var keys []interface{}
+	sm.Map.Range(func(k, v interface{}) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func (sm *SyncMapModel) Get(key interface{}) (interface{}, bool) {
+	return sm.Map.Load(key)
+}
+
+func (sm *SyncMapModel) Set(key, val interface{}) {
+	sm.Map.Store(key, val)
+}
+
+func (sm *SyncMapModel) Delete(key interface{}) {
+	sm.Map.Delete(key)
+}
+
+func (sm *SyncMapModel) KeyType() reflect.Type {
+	return sm.KeyTyp
+}
+
+func (sm *SyncMapModel) ElemType() reflect.Type {
+	return sm.ElemTyp
+}
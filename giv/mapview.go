@@ -5,32 +5,140 @@
 package giv
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"image"
+	"log"
 	"reflect"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mimedata"
+	"github.com/goki/gi/oswin/mouse"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
 	"github.com/goki/ki/kit"
+	"github.com/goki/pi/filecat"
 )
 
+// MapSortModes determines the order in which map entries are displayed
+// in a MapView.
+type MapSortModes int32
+
+const (
+	// MapSortKeys sorts the entries by their keys (the default)
+	MapSortKeys MapSortModes = iota
+
+	// MapSortVals sorts the entries by their values
+	MapSortVals
+
+	// MapSortNone does no sorting: entries are shown in a cached order,
+	// initialized when first displayed, which the user can change by
+	// dragging the row handles -- Go maps are unordered, so this cached
+	// order (see KeyOrder) is the only record of the sequence
+	MapSortNone
+
+	MapSortModesN
+)
+
+//go:generate stringer -type=MapSortModes
+
+var KiT_MapSortModes = kit.Enums.AddEnum(MapSortModesN, kit.NotBitFlag, nil)
+
 // MapView represents a map, creating a property editor of the values --
 // constructs Children widgets to show the key / value pairs, within an
 // overall frame.
 type MapView struct {
 	gi.Frame
-	Map        interface{} `desc:"the map that we are a view onto"`
-	MapValView ValueView   `desc:"ValueView for the map itself, if this was created within value view framework -- otherwise nil"`
-	Changed    bool        `desc:"has the map been edited?"`
-	Keys       []ValueView `json:"-" xml:"-" desc:"ValueView representations of the map keys"`
-	Values     []ValueView `json:"-" xml:"-" desc:"ValueView representations of the map values"`
-	SortVals   bool        `desc:"sort by values instead of keys"`
-	TmpSave    ValueView   `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
-	ViewSig    ki.Signal   `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
-	ViewPath   string      `desc:"a record of parent View names that have led up to this view -- displayed as extra contextual information in view dialog windows"`
-	ToolbarMap interface{} `desc:"the map that we successfully set a toolbar for"`
+	Map              interface{}                  `desc:"the map that we are a view onto"`
+	MapValView       ValueView                    `desc:"ValueView for the map itself, if this was created within value view framework -- otherwise nil"`
+	Title            string                       `desc:"title / prompt to show above the map grid -- see also ShowCount"`
+	ShowCount        bool                         `desc:"append the current number of entries to the title label, e.g., 'Settings (12 items)' -- updated whenever the grid is rebuilt after add / delete"`
+	Collapsible      bool                         `desc:"show a disclosure triangle next to the title that collapses / expands the whole view -- collapsed, only the header row (title, with entry count if ShowCount) shows, so several MapViews can be stacked in an accordion-style settings panel -- see Collapsed"`
+	Collapsed        bool                         `viewif:"Collapsible" desc:"current collapsed state when Collapsible is set: the toolbar and map grid are removed, leaving only the header row -- see SetCollapsed"`
+	Changed          bool                         `desc:"has the map been edited?"`
+	Keys             []ValueView                  `json:"-" xml:"-" desc:"ValueView representations of the map keys"`
+	Values           []ValueView                  `json:"-" xml:"-" desc:"ValueView representations of the map values"`
+	SortMode         MapSortModes                 `desc:"how the map entries are ordered for display: by keys, by values, or in a cached user-controllable order (MapSortNone)"`
+	KeyOrder         []interface{}                `copy:"-" json:"-" xml:"-" view:"-" desc:"cached display order of the map keys, for MapSortNone -- updated on each grid config and rearranged by drag-to-reorder"`
+	DragRow          int                          `copy:"-" json:"-" xml:"-" view:"-" desc:"row currently being dragged by its handle for reordering -- -1 if none"`
+	ShowTypes        bool                         `desc:"show a read-only column with the type of each value -- for interface{} value maps, the type-changing chooser column is always shown instead"`
+	Striped          bool                         `desc:"alternating (zebra) row background colors in the map grid, for readability -- on by default (set during initial Config) -- call ConfigMapGrid after changing"`
+	HighlightChanges bool                         `desc:"on each RefreshFromMap, briefly flash (fade out) the background of rows whose value changed since the last refresh, with newly added rows and rows about to be removed in distinct colors (see MapViewChangedFlashColor etc) -- makes the view usable as a live monitoring / telemetry display"`
+	MaxVisibleRows   int                          `desc:"maximum number of rows visible in the map grid before it gets its own scrollbar, with the title and toolbar staying fixed above -- 0 (the default) means no limit, so the grid grows to fit all entries"`
+	DeleteConfirm    bool                         `desc:"ask for confirmation before deleting an entry -- applies to the per-row delete action and the keyboard delete shortcut"`
+	AddLabel         string                       `desc:"label for the toolbar Add action -- default \"Add\" -- set for localization -- set before the view is configured"`
+	AddIcon          gi.IconName                  `desc:"icon for the toolbar Add action -- default \"plus\" -- set before the view is configured"`
+	DeleteIcon       gi.IconName                  `desc:"icon for the per-row delete action -- default \"minus\" -- applied on each grid config"`
+	KeyColWidth      units.Value                  `desc:"fixed width for the key column widgets -- zero (the default) sizes keys naturally -- e.g., units.NewPx(200) keeps long keys from unbalancing the grid -- applied on each grid config"`
+	ValColWidth      units.Value                  `desc:"fixed width for the value column widgets -- zero (the default) sizes values naturally, or stretches them to fill the remaining width when KeyColWidth is set -- applied on each grid config"`
+	KeyColAlign      gi.Align                     `desc:"horizontal alignment for the key column widgets -- default AlignLeft"`
+	ValColAlign      gi.Align                     `desc:"horizontal alignment for the value column widgets -- default AlignLeft"`
+	HoverRow         int                          `copy:"-" json:"-" xml:"-" view:"-" desc:"row currently highlighted from mouse hover -- -1 if none"`
+	TmpSave          ValueView                    `json:"-" xml:"-" desc:"value view that needs to have SaveTmp called on it whenever a change is made to one of the underlying values -- pass this down to any sub-views created from a parent"`
+	ViewSig          ki.Signal                    `json:"-" xml:"-" desc:"signal for valueview -- only one signal sent when a value has been set -- all related value views interconnect with each other to update when others update"`
+	ViewPath         string                       `desc:"a record of parent View names that have led up to this view -- displayed as extra contextual information in view dialog windows"`
+	MapType          reflect.Type                 `copy:"-" json:"-" xml:"-" view:"-" desc:"map type to allocate when the Add action is used while Map is nil -- see SetMapType"`
+	ToolbarMap       interface{}                  `desc:"the map that we successfully set a toolbar for"`
+	KeyDesc          func(key interface{}) string `copy:"-" json:"-" xml:"-" view:"-" desc:"optional function returning a description for given map key, shown as the tooltip on the key widget -- tooltips are updated whenever the grid is rebuilt"`
+	ValTags          map[string]string            `copy:"-" json:"-" xml:"-" view:"-" desc:"optional value-widget constraint tags applied to every map value: min, max, step for numbers (spin boxes clamp to the range), enum for a fixed comma-separated set of allowed strings (rendered as a chooser) -- see https://github.com/goki/gi/wiki/Tags -- set before the grid is configured"`
+	KeyValTags       map[string]map[string]string `copy:"-" json:"-" xml:"-" view:"-" desc:"optional per-key value-widget constraint tags, keyed by the kit.ToString of the map key -- add to / override ValTags for that entry"`
+	InBatch          bool                         `copy:"-" json:"-" xml:"-" view:"-" desc:"batch update mode is active (see BeginBatch) -- individual map edits skip their per-operation rebuild / re-render"`
+	UndoStack        []MapViewUndoRec             `copy:"-" json:"-" xml:"-" view:"-" desc:"stack of undo records for map edits -- see Undo()"`
+	RedoStack        []MapViewUndoRec             `copy:"-" json:"-" xml:"-" view:"-" desc:"stack of undone records available for Redo()"`
+	PrevVals         []interface{}                `copy:"-" json:"-" xml:"-" view:"-" desc:"snapshot of displayed values from last grid config, used to capture the prior value for undo when a value is edited"`
+	FlashRows        map[string]*MapViewFlash     `copy:"-" json:"-" xml:"-" view:"-" desc:"currently flashing rows from HighlightChanges, keyed by key text -- managed by FlashRow / FlashStep"`
+	FlashTimer       *time.Timer                  `copy:"-" json:"-" xml:"-" view:"-" desc:"timer driving the flash fade steps -- see FlashStep"`
+	FlashRebuild     bool                         `copy:"-" json:"-" xml:"-" view:"-" desc:"a grid rebuild is pending until the removed-row flash completes -- see RefreshRebuildFlash"`
+	FlashMu          sync.Mutex                   `copy:"-" json:"-" xml:"-" view:"-" desc:"mutex protecting the flash state, which is advanced on a timer goroutine"`
 }
 
+// MapViewUndoRec is one record in the MapView undo / redo stack, capturing a
+// single add, delete, or value change so that it can be reversed.  Because a
+// map delete loses the value, it is snapshotted here before deletion.
+type MapViewUndoRec struct {
+	Add    bool        `desc:"the entry was added (Key is the new key)"`
+	Del    bool        `desc:"the entry was deleted (Key, OldVal are the deleted entry)"`
+	Key    interface{} `desc:"key of the affected entry"`
+	OldVal interface{} `desc:"value prior to the edit (or the deleted value)"`
+	NewVal interface{} `desc:"value after the edit (for adds and value changes)"`
+}
+
+// MapViewFlash is the fade state of one flashing row from HighlightChanges:
+// the flash color and the number of fade steps remaining.
+type MapViewFlash struct {
+	Color gi.Color `desc:"the flash background color, faded into the normal background over the remaining steps"`
+	Left  int      `desc:"number of fade steps left -- counts down from MapViewFlashSteps"`
+}
+
+var (
+	// MapViewChangedFlashColor is the HighlightChanges flash background for
+	// rows whose value changed since the last refresh.
+	MapViewChangedFlashColor = gi.Color{255, 230, 100, 255}
+
+	// MapViewAddedFlashColor is the HighlightChanges flash background for
+	// newly added rows.
+	MapViewAddedFlashColor = gi.Color{150, 230, 130, 255}
+
+	// MapViewDeletedFlashColor is the HighlightChanges flash background for
+	// rows about to be removed -- they flash in this color before the grid
+	// rebuild actually removes them.
+	MapViewDeletedFlashColor = gi.Color{255, 150, 150, 255}
+)
+
+// MapViewFlashSteps is the number of fade steps in a HighlightChanges row
+// flash -- the flash color fades linearly into the normal background.
+var MapViewFlashSteps = 8
+
+// MapViewFlashStepMSec is the interval between HighlightChanges fade steps
+// in milliseconds.
+var MapViewFlashStepMSec = 60
+
 var KiT_MapView = kit.Types.AddType(&MapView{}, MapViewProps)
 
 // AddNewMapView adds a new mapview to given parent node, with given name.
@@ -44,7 +152,9 @@ func (mv *MapView) Disconnect() {
 }
 
 // SetMap sets the source map that we are viewing -- rebuilds the children to
-// represent this map
+// represent this map.  mp can either be a plain Go map value (accessed via
+// reflection) or a MapModel (e.g., a SyncMapModel wrapping a sync.Map) --
+// see MapModel.
 func (mv *MapView) SetMap(mp interface{}) {
 	// note: because we make new maps, and due to the strangeness of reflect, they
 	// end up not being comparable types, so we can't check if equal
@@ -52,6 +162,15 @@ func (mv *MapView) SetMap(mp interface{}) {
 	mv.Config()
 }
 
+// SetMapType sets the map type to allocate when the Add action is used while
+// Map is nil, so that a freshly constructed editor (before any SetMap) has a
+// working Add button -- typ must be a map type, e.g.,
+// reflect.TypeOf(map[string]string{}).  The newly made map is set as Map on
+// the first Add.
+func (mv *MapView) SetMapType(typ reflect.Type) {
+	mv.MapType = typ
+}
+
 var MapViewProps = ki.Props{
 	"EnumType:Flag":    gi.KiT_NodeFlags,
 	"background-color": &gi.Prefs.Colors.Background,
@@ -59,22 +178,371 @@ var MapViewProps = ki.Props{
 	"max-height":       -1,
 }
 
+// Model returns the MapModel interface for the current Map, if the Map is a
+// MapModel instead of a plain map value -- nil otherwise.  See MapModel.
+func (mv *MapView) Model() MapModel {
+	if kit.IfaceIsNil(mv.Map) {
+		return nil
+	}
+	mm, _ := mv.Map.(MapModel)
+	return mm
+}
+
 // UpdateValues updates the widget display of slice values, assuming same slice config
 func (mv *MapView) UpdateValues() {
 	// maps have to re-read their values -- can't get pointers
 	mv.ConfigMapGrid()
 }
 
+// RefreshFromMap updates the display to reflect external mutations of the
+// underlying map -- the documented pattern for using MapView as a live
+// monitor of shared state: whatever code mutates the map calls this (from
+// the main event loop) afterwards.  Uses lightweight change detection,
+// comparing key sets and value strings against what is displayed: if the
+// keys are unchanged, only the widgets of rows whose values actually changed
+// are updated in place, preserving focus and scroll position -- a full grid
+// rebuild only happens when entries were added / removed / renamed (or when
+// sorting by values, where changed values can reorder the rows).
+func (mv *MapView) RefreshFromMap() {
+	if !mv.IsConfiged() {
+		return
+	}
+	if mv.Collapsible && mv.Collapsed { // no grid -- just the entry count
+		mv.UpdateTitle()
+		return
+	}
+	if kit.IfaceIsNil(mv.Map) {
+		if len(mv.Keys) > 0 {
+			mv.RefreshRebuildFlash(nil)
+		}
+		return
+	}
+	if mm := mv.Model(); mm != nil {
+		mv.refreshFromModel(mm)
+		return
+	}
+	mpvnp := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	var keys []reflect.Value
+	if mv.SortMode == MapSortNone {
+		keys = mv.OrderedMapKeys(mpvnp)
+	} else {
+		keys = kit.MapSort(mv.Map, mv.SortMode != MapSortVals, true)
+	}
+	ntxts := make([]string, len(keys))
+	for i, key := range keys {
+		ntxts[i] = kit.ToString(key.Interface())
+	}
+	if len(keys) != len(mv.Keys) {
+		mv.RefreshRebuildFlash(ntxts)
+		return
+	}
+	var changed []int
+	for i, key := range keys {
+		if ntxts[i] != kit.ToString(kit.NonPtrValue(mv.Keys[i].Val()).Interface()) {
+			mv.RefreshRebuildFlash(ntxts)
+			return
+		}
+		val := mpvnp.MapIndex(key)
+		if i < len(mv.PrevVals) && kit.ToString(val.Interface()) != kit.ToString(mv.PrevVals[i]) {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	if mv.SortMode == MapSortVals { // display order depends on the changed values
+		mv.RefreshRebuild()
+		if mv.HighlightChanges { // flash by key text -- rows may have moved
+			for _, i := range changed {
+				mv.FlashRow(ntxts[i], MapViewChangedFlashColor)
+			}
+		}
+		return
+	}
+	updt := mv.UpdateStart()
+	for _, i := range changed {
+		vv := mv.Values[i]
+		key := keys[i]
+		val := kit.OnePtrUnderlyingValue(mpvnp.MapIndex(key))
+		vv.SetMapValue(val, mv.Map, key.Interface(), mv.Keys[i], mv.TmpSave, mv.ViewPath)
+		vv.UpdateWidget()
+		if vval := kit.NonPtrValue(vv.Val()); vval.IsValid() {
+			mv.PrevVals[i] = vval.Interface()
+		}
+	}
+	mv.UpdateEnd(updt)
+	if mv.HighlightChanges {
+		for _, i := range changed {
+			mv.FlashRow(ntxts[i], MapViewChangedFlashColor)
+		}
+	}
+}
+
+// refreshFromModel is the MapModel version of RefreshFromMap -- see there.
+func (mv *MapView) refreshFromModel(mm MapModel) {
+	keys := mm.Keys()
+	if mv.SortMode == MapSortNone {
+		keys = mv.OrderedKeys(keys)
+	} else {
+		sort.Slice(keys, func(i, j int) bool {
+			if mv.SortMode == MapSortVals {
+				vi, _ := mm.Get(keys[i])
+				vj, _ := mm.Get(keys[j])
+				return kit.ToString(vi) < kit.ToString(vj)
+			}
+			return kit.ToString(keys[i]) < kit.ToString(keys[j])
+		})
+	}
+	ntxts := make([]string, len(keys))
+	for i, key := range keys {
+		ntxts[i] = kit.ToString(key)
+	}
+	if len(keys) != len(mv.Keys) {
+		mv.RefreshRebuildFlash(ntxts)
+		return
+	}
+	var changed []int
+	for i, key := range keys {
+		if ntxts[i] != kit.ToString(kit.NonPtrValue(mv.Keys[i].Val()).Interface()) {
+			mv.RefreshRebuildFlash(ntxts)
+			return
+		}
+		val, _ := mm.Get(key)
+		if i < len(mv.PrevVals) && kit.ToString(val) != kit.ToString(mv.PrevVals[i]) {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	if mv.SortMode == MapSortVals { // display order depends on the changed values
+		mv.RefreshRebuild()
+		if mv.HighlightChanges { // flash by key text -- rows may have moved
+			for _, i := range changed {
+				mv.FlashRow(ntxts[i], MapViewChangedFlashColor)
+			}
+		}
+		return
+	}
+	updt := mv.UpdateStart()
+	for _, i := range changed {
+		vv := mv.Values[i]
+		val, _ := mm.Get(keys[i])
+		if val == nil {
+			val = reflect.New(mm.ElemType()).Elem().Interface()
+		}
+		vp := reflect.New(mm.ElemType())
+		vp.Elem().Set(reflect.ValueOf(val))
+		vv.AsValueViewBase().SetSoloValue(vp.Elem())
+		vv.UpdateWidget()
+		mv.PrevVals[i] = val
+	}
+	mv.UpdateEnd(updt)
+	if mv.HighlightChanges {
+		for _, i := range changed {
+			mv.FlashRow(ntxts[i], MapViewChangedFlashColor)
+		}
+	}
+}
+
+// RefreshRebuild does the full grid rebuild fallback for RefreshFromMap,
+// for when the set or order of keys has changed.
+func (mv *MapView) RefreshRebuild() {
+	updt := mv.UpdateStart()
+	mv.SetFullReRender()
+	mv.ConfigMapGrid()
+	mv.UpdateEnd(updt)
+}
+
+// RefreshRebuildFlash is the HighlightChanges-aware version of
+// RefreshRebuild, for when the set of keys has changed: rows about to be
+// removed are first flashed in MapViewDeletedFlashColor, deferring the
+// actual rebuild until the flash completes (FlashStep then re-runs
+// RefreshFromMap), and after a rebuild, newly added rows are flashed in
+// MapViewAddedFlashColor.  newKeyTxts is the key text of all entries now in
+// the map.  Without HighlightChanges this is just RefreshRebuild.
+func (mv *MapView) RefreshRebuildFlash(newKeyTxts []string) {
+	if !mv.HighlightChanges {
+		mv.RefreshRebuild()
+		return
+	}
+	mv.FlashMu.Lock()
+	pending := mv.FlashRebuild
+	mv.FlashMu.Unlock()
+	if pending { // already waiting on a removed-row flash -- its rebuild will resync
+		return
+	}
+	nmap := make(map[string]struct{}, len(newKeyTxts))
+	for _, kt := range newKeyTxts {
+		nmap[kt] = struct{}{}
+	}
+	var removed []string
+	otxts := make(map[string]struct{}, len(mv.Keys))
+	for _, kv := range mv.Keys {
+		kt := kit.ToString(kit.NonPtrValue(kv.Val()).Interface())
+		otxts[kt] = struct{}{}
+		if _, ok := nmap[kt]; !ok {
+			removed = append(removed, kt)
+		}
+	}
+	if len(removed) > 0 { // flash first -- rebuild happens when the flash completes
+		mv.FlashMu.Lock()
+		mv.FlashRebuild = true
+		mv.FlashMu.Unlock()
+		for _, kt := range removed {
+			mv.FlashRow(kt, MapViewDeletedFlashColor)
+		}
+		return
+	}
+	mv.RefreshRebuild()
+	for _, kt := range newKeyTxts {
+		if _, ok := otxts[kt]; !ok {
+			mv.FlashRow(kt, MapViewAddedFlashColor)
+		}
+	}
+}
+
+// FlashRow starts (or restarts) a HighlightChanges background flash on the
+// row displaying the key with given text, in given color -- the color fades
+// into the normal background over MapViewFlashSteps timer steps (see
+// FlashStep).  Rows are tracked by key text so the flash survives grid
+// rebuilds and reordering.
+func (mv *MapView) FlashRow(keytxt string, clr gi.Color) {
+	mv.FlashMu.Lock()
+	if mv.FlashRows == nil {
+		mv.FlashRows = make(map[string]*MapViewFlash)
+	}
+	mv.FlashRows[keytxt] = &MapViewFlash{Color: clr, Left: MapViewFlashSteps}
+	mv.applyFlash()
+	if mv.FlashTimer == nil {
+		mv.FlashTimer = time.AfterFunc(time.Duration(MapViewFlashStepMSec)*time.Millisecond, mv.FlashStep)
+	}
+	mv.FlashMu.Unlock()
+}
+
+// RowByKeyText returns the display row index of the entry whose key has
+// given text representation -- -1 if not displayed.
+func (mv *MapView) RowByKeyText(keytxt string) int {
+	for i, kv := range mv.Keys {
+		if kit.ToString(kit.NonPtrValue(kv.Val()).Interface()) == keytxt {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyFlash applies the current fade state of all flashing rows to the row
+// widget backgrounds, removing finished and no-longer-displayed entries --
+// FlashMu must be held by the caller.
+func (mv *MapView) applyFlash() {
+	nk := len(mv.Keys)
+	sg := mv.MapGrid()
+	if sg == nil || nk == 0 {
+		return
+	}
+	ncol := len(sg.Kids) / nk
+	if ncol == 0 {
+		return
+	}
+	updt := sg.UpdateStart()
+	base := sg.Sty.Font.BgColor.Color
+	for keytxt, fl := range mv.FlashRows {
+		row := mv.RowByKeyText(keytxt)
+		if row < 0 {
+			delete(mv.FlashRows, keytxt)
+			continue
+		}
+		if row == mv.HoverRow { // hover highlight takes precedence
+			continue
+		}
+		pct := 100 * float32(fl.Left) / float32(MapViewFlashSteps)
+		clr := base.Blend(pct, fl.Color)
+		for c := 0; c < ncol; c++ {
+			wb := sg.Child(row*ncol + c).(gi.Node2D).AsWidget()
+			if wb == nil {
+				continue
+			}
+			if fl.Left > 0 {
+				wb.SetProp("background-color", clr)
+			} else {
+				wb.DeleteProp("background-color")
+			}
+			wb.SetFullReRender()
+		}
+		if fl.Left <= 0 {
+			delete(mv.FlashRows, keytxt)
+		}
+	}
+	sg.UpdateEnd(updt)
+}
+
+// FlashStep is the flash timer callback: advances the fade on all flashing
+// rows, restores the background of finished ones, and re-arms the timer
+// while any remain -- runs on the timer goroutine, like the text field
+// cursor blinker.  When the removed-row flash of a pending rebuild (see
+// RefreshRebuildFlash) completes, the deferred RefreshFromMap is run.
+func (mv *MapView) FlashStep() {
+	if mv == nil || mv.This() == nil {
+		return
+	}
+	if !mv.This().(gi.Node2D).IsVisible() { // gone -- drop the flash state
+		mv.FlashMu.Lock()
+		mv.FlashRows = nil
+		mv.FlashTimer = nil
+		mv.FlashRebuild = false
+		mv.FlashMu.Unlock()
+		return
+	}
+	mv.FlashMu.Lock()
+	for _, fl := range mv.FlashRows {
+		fl.Left--
+	}
+	mv.applyFlash()
+	rebuild := false
+	if len(mv.FlashRows) > 0 {
+		mv.FlashTimer = time.AfterFunc(time.Duration(MapViewFlashStepMSec)*time.Millisecond, mv.FlashStep)
+	} else {
+		mv.FlashTimer = nil
+		rebuild = mv.FlashRebuild
+		mv.FlashRebuild = false
+	}
+	mv.FlashMu.Unlock()
+	if rebuild {
+		mv.RefreshFromMap()
+	}
+}
+
 // Config configures the view
 func (mv *MapView) Config() {
+	if len(mv.Kids) == 0 { // defaults, prior to initial config
+		mv.Striped = true
+		mv.HoverRow = -1
+		mv.DragRow = -1
+	}
 	mv.Lay = gi.LayoutVert
 	mv.SetProp("spacing", gi.StdDialogVSpaceUnits)
 	config := kit.TypeAndNameList{}
-	config.Add(gi.KiT_ToolBar, "toolbar")
-	config.Add(gi.KiT_Frame, "map-grid")
+	if mv.Collapsible {
+		config.Add(gi.KiT_Layout, "header")
+	} else {
+		config.Add(gi.KiT_Label, "title")
+	}
+	if !(mv.Collapsible && mv.Collapsed) {
+		config.Add(gi.KiT_ToolBar, "toolbar")
+		config.Add(gi.KiT_Frame, "map-grid")
+	}
 	mods, updt := mv.ConfigChildren(config, true)
+	if mv.Collapsible {
+		mv.ConfigHeader()
+	}
+	if mv.Collapsible && mv.Collapsed { // grid is removed -- clear stale row state
+		mv.Keys = nil
+		mv.Values = nil
+		mv.PrevVals = nil
+	}
 	mv.ConfigMapGrid()
 	mv.ConfigToolbar()
+	mv.UpdateTitle()
 	if mods {
 		mv.UpdateEnd(updt)
 	}
@@ -88,14 +556,139 @@ func (mv *MapView) IsConfiged() bool {
 	return true
 }
 
-// MapGrid returns the MapGrid grid layout widget, which contains all the fields and values
+// TitleWidget returns the title label widget -- inside the header row for
+// Collapsible map views, a direct child otherwise.  Returns nil if not
+// yet configured.
+func (mv *MapView) TitleWidget() *gi.Label {
+	if mv.Collapsible {
+		hdr := mv.Header()
+		if hdr == nil {
+			return nil
+		}
+		ti := hdr.ChildByName("title", 1)
+		if ti == nil {
+			return nil
+		}
+		return ti.(*gi.Label)
+	}
+	ti := mv.ChildByName("title", 0)
+	if ti == nil {
+		return nil
+	}
+	return ti.(*gi.Label)
+}
+
+// Header returns the header row layout holding the disclosure triangle and
+// title label, for Collapsible map views -- nil otherwise (see Collapsible)
+func (mv *MapView) Header() *gi.Layout {
+	hdr := mv.ChildByName("header", 0)
+	if hdr == nil {
+		return nil
+	}
+	return hdr.(*gi.Layout)
+}
+
+// ConfigHeader configures the header row for Collapsible map views: the
+// disclosure triangle action that toggles the collapsed state, and the
+// title label -- called automatically by Config.
+func (mv *MapView) ConfigHeader() {
+	hdr := mv.Header()
+	if hdr == nil {
+		return
+	}
+	hdr.Lay = gi.LayoutHoriz
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Action, "collapse")
+	config.Add(gi.KiT_Label, "title")
+	mods, updt := hdr.ConfigChildren(config, false)
+	act := hdr.Child(0).(*gi.Action)
+	if mv.Collapsed {
+		act.SetIcon("wedge-right")
+		act.Tooltip = "expand this map view"
+	} else {
+		act.SetIcon("wedge-down")
+		act.Tooltip = "collapse this map view, leaving only the header row"
+	}
+	act.Sty.Template = "giv.MapView.CollapseAction"
+	act.ActionSig.ConnectOnly(mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		mvv := recv.Embed(KiT_MapView).(*MapView)
+		mvv.ToggleCollapse()
+	})
+	if mods {
+		hdr.UpdateEnd(updt)
+	}
+}
+
+// SetCollapsed sets the collapsed state for a Collapsible map view and
+// reconfigures accordingly: collapsed, the toolbar and map grid are removed,
+// so only the header row shows -- expanding rebuilds them.  No-op if
+// Collapsible is not set or the state is unchanged.
+func (mv *MapView) SetCollapsed(collapsed bool) {
+	if !mv.Collapsible || mv.Collapsed == collapsed {
+		return
+	}
+	mv.Collapsed = collapsed
+	updt := mv.UpdateStart()
+	mv.SetFullReRender()
+	mv.Config()
+	mv.UpdateEnd(updt)
+}
+
+// ToggleCollapse toggles the collapsed state for a Collapsible map view --
+// this is what the disclosure triangle in the header invokes.
+func (mv *MapView) ToggleCollapse() {
+	mv.SetCollapsed(!mv.Collapsed)
+}
+
+// SetTitle sets the optional title and updates the Title label
+func (mv *MapView) SetTitle(title string) {
+	mv.Title = title
+	mv.UpdateTitle()
+}
+
+// UpdateTitle updates the title label from the Title field, appending the
+// current number of map entries if ShowCount is set -- called whenever the
+// map grid is rebuilt (e.g., after add / delete).
+func (mv *MapView) UpdateTitle() {
+	lab := mv.TitleWidget()
+	if lab == nil {
+		return
+	}
+	txt := mv.Title
+	if mv.ShowCount && !kit.IfaceIsNil(mv.Map) {
+		if mm := mv.Model(); mm != nil {
+			if txt == "" {
+				txt = fmt.Sprintf("%T", mv.Map)
+			}
+			txt = fmt.Sprintf("%v (%v items)", txt, len(mm.Keys()))
+		} else {
+			mpvnp := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+			if txt == "" {
+				txt = mpvnp.Type().String()
+			}
+			txt = fmt.Sprintf("%v (%v items)", txt, mpvnp.Len())
+		}
+	}
+	lab.Text = txt
+}
+
+// MapGrid returns the MapGrid grid layout widget, which contains all the
+// fields and values -- nil when collapsed (see Collapsible)
 func (mv *MapView) MapGrid() *gi.Frame {
-	return mv.ChildByName("map-grid", 0).(*gi.Frame)
+	sg := mv.ChildByName("map-grid", 0)
+	if sg == nil {
+		return nil
+	}
+	return sg.(*gi.Frame)
 }
 
-// ToolBar returns the toolbar widget
+// ToolBar returns the toolbar widget -- nil when collapsed (see Collapsible)
 func (mv *MapView) ToolBar() *gi.ToolBar {
-	return mv.ChildByName("toolbar", 0).(*gi.ToolBar)
+	tb := mv.ChildByName("toolbar", 0)
+	if tb == nil {
+		return nil
+	}
+	return tb.(*gi.ToolBar)
 }
 
 // KiPropTag returns the PropTag value from Ki owner of this map, if it is..
@@ -115,14 +708,72 @@ func (mv *MapView) KiPropTag() string {
 	return ""
 }
 
+// GridMaxHeight sets the max-height on the map grid according to
+// MaxVisibleRows and the given number of rows, so that the grid scrolls
+// within a fixed max height when there are more rows than fit -- the title
+// and toolbar stay fixed above the scrolling grid.
+func (mv *MapView) GridMaxHeight(sg *gi.Frame, nrows int) {
+	if mv.MaxVisibleRows > 0 && nrows > mv.MaxVisibleRows {
+		sg.SetProp("max-height", units.NewEm(float32(mv.MaxVisibleRows)*2.2)) // approx height of a widget row
+	} else {
+		sg.SetStretchMaxHeight()
+	}
+}
+
+// ConfigEmptyGrid configures the map grid with a single placeholder label,
+// shown when the map is nil or has no entries, so that a freshly constructed
+// editor gives some guidance instead of a blank frame -- called automatically
+// by ConfigMapGrid.
+func (mv *MapView) ConfigEmptyGrid() {
+	sg := mv.MapGrid()
+	sg.Lay = gi.LayoutGrid
+	sg.SetProp("columns", 1)
+	sg.SetMinPrefHeight(units.NewEm(1.5))
+	sg.SetMinPrefWidth(units.NewEm(10))
+	sg.SetStretchMax()
+	// always start fresh!
+	mv.Keys = make([]ValueView, 0)
+	mv.Values = make([]ValueView, 0)
+	mv.PrevVals = nil
+	config := kit.TypeAndNameList{}
+	config.Add(gi.KiT_Label, "empty-label")
+	mods, updt := sg.ConfigChildren(config, true)
+	if mods {
+		sg.SetFullReRender()
+	} else {
+		updt = sg.UpdateStart()
+	}
+	lbl := sg.Child(0).(*gi.Label)
+	lbl.Text = "No entries — click Add"
+	lbl.Sty.Template = "giv.MapView.EmptyLabel"
+	sg.UpdateEnd(updt)
+	mv.UpdateTitle()
+}
+
 // ConfigMapGrid configures the MapGrid for the current map
 func (mv *MapView) ConfigMapGrid() {
+	if mv.MapGrid() == nil { // collapsed -- nothing to configure
+		return
+	}
 	if kit.IfaceIsNil(mv.Map) {
+		mv.ConfigEmptyGrid()
+		return
+	}
+	if mm := mv.Model(); mm != nil {
+		mv.ConfigMapModelGrid(mm)
+		return
+	}
+	if kit.NonPtrValue(reflect.ValueOf(mv.Map)).Len() == 0 {
+		mv.ConfigEmptyGrid()
 		return
 	}
 	sg := mv.MapGrid()
 	sg.Lay = gi.LayoutGrid
-	sg.Stripes = gi.RowStripes
+	if mv.Striped {
+		sg.Stripes = gi.RowStripes
+	} else {
+		sg.Stripes = gi.NoStripes
+	}
 	// setting a pref here is key for giving it a scrollbar in larger context
 	sg.SetMinPrefHeight(units.NewEm(1.5))
 	sg.SetMinPrefWidth(units.NewEm(10))
@@ -152,13 +803,31 @@ func (mv *MapView) ConfigMapGrid() {
 		// dialog.. a bit hacky and indirect..
 	}
 
+	showTypes := false
+	if !ifaceType && mv.ShowTypes {
+		showTypes = true
+		ncol = 4
+	}
+
 	valtypes := append(kit.Types.AllTagged(typeTag), kit.Enums.AllTagged(typeTag)...)
 	valtypes = append(valtypes, kit.Types.AllTagged("basic-type")...)
 	valtypes = append(valtypes, reflect.TypeOf((*reflect.Type)(nil)).Elem())
 
+	dragOrd := mv.SortMode == MapSortNone
+	colOff := 0
+	if dragOrd { // extra leading column with the drag-to-reorder handles
+		ncol++
+		colOff = 1
+	}
+
 	sg.SetProp("columns", ncol)
 
-	keys := kit.MapSort(mv.Map, !mv.SortVals, true) // note: this is a slice of reflect.Value!
+	var keys []reflect.Value
+	if mv.SortMode == MapSortNone {
+		keys = mv.OrderedMapKeys(mpvnp)
+	} else {
+		keys = kit.MapSort(mv.Map, mv.SortMode != MapSortVals, true) // note: this is a slice of reflect.Value!
+	}
 	for _, key := range keys {
 		kv := ToValueView(key.Interface(), "")
 		if kv == nil { // shouldn't happen
@@ -178,48 +847,87 @@ func (mv *MapView) ConfigMapGrid() {
 		valnm := fmt.Sprintf("value-%v", keytxt)
 		delnm := fmt.Sprintf("del-%v", keytxt)
 
+		mv.SetValTags(vv, valtyp, keytxt) // must set before WidgetType is chosen
+
+		if dragOrd {
+			config.Add(gi.KiT_Label, fmt.Sprintf("drag-%v", keytxt))
+		}
 		config.Add(kv.WidgetType(), keynm)
 		config.Add(vv.WidgetType(), valnm)
 		if ifaceType {
 			typnm := fmt.Sprintf("type-%v", keytxt)
 			config.Add(gi.KiT_ComboBox, typnm)
+		} else if showTypes {
+			typnm := fmt.Sprintf("type-%v", keytxt)
+			config.Add(gi.KiT_Label, typnm)
 		}
 		config.Add(gi.KiT_Action, delnm)
 		mv.Keys = append(mv.Keys, kv)
 		mv.Values = append(mv.Values, vv)
 	}
+	mv.GridMaxHeight(sg, len(mv.Keys))
 	mods, updt := sg.ConfigChildren(config, true)
 	if mods {
 		sg.SetFullReRender()
 	} else {
 		updt = sg.UpdateStart() // cover rest of updates, which can happen even if same config
 	}
+	mv.PrevVals = make([]interface{}, len(mv.Values))
 	for i, vv := range mv.Values {
+		if vval := kit.NonPtrValue(vv.Val()); vval.IsValid() {
+			mv.PrevVals[i] = vval.Interface()
+		}
+		idx := i
 		vvb := vv.AsValueViewBase()
 		vvb.ViewSig.ConnectOnly(mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			mvv, _ := recv.Embed(KiT_MapView).(*MapView)
+			mvv.RecordValueChange(idx)
 			mvv.SetChanged()
+			if _, wasNil := send.(*NilPtrValueView); wasNil {
+				// nil pointer was just allocated -- widget type changed -- rebuild
+				updt := mvv.UpdateStart()
+				mvv.SetFullReRender()
+				mvv.ConfigMapGrid()
+				mvv.UpdateEnd(updt)
+			}
 		})
-		keyw := sg.Child(i * ncol).(gi.Node2D)
-		widg := sg.Child(i*ncol + 1).(gi.Node2D)
+		if dragOrd {
+			hdl := sg.Child(i * ncol).(*gi.Label)
+			hdl.Text = "\u2630" // trigram -- standard drag handle glyph
+			hdl.Tooltip = "drag to move this entry to a different position"
+			hdl.Sty.Template = "giv.MapView.DragHandle"
+		}
+		keyw := sg.Child(i*ncol + colOff).(gi.Node2D)
+		widg := sg.Child(i*ncol + colOff + 1).(gi.Node2D)
 		kv := mv.Keys[i]
 		kvb := kv.AsValueViewBase()
 		kvb.ViewSig.ConnectOnly(mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			mvv, _ := recv.Embed(KiT_MapView).(*MapView)
+			updt := mvv.UpdateStart()
+			mvv.SetFullReRender()
+			mvv.ConfigMapGrid() // a key rename changes the widget names -- rebuild
 			mvv.SetChanged()
+			mvv.UpdateEnd(updt)
 		})
 		kv.ConfigWidget(keyw)
 		vv.ConfigWidget(widg)
+		mv.SetColProps(keyw, widg)
 		wb := widg.AsWidget()
 		if wb != nil {
 			wb.Sty.Template = "giv.MapView.ItemWidget." + vv.WidgetType().Name()
+			if tt := StructValDesc(vv.Val()); tt != "" {
+				wb.Tooltip = tt
+			}
 		}
 		wb = keyw.AsWidget()
 		if wb != nil {
 			wb.Sty.Template = "giv.MapView.KeyWidget." + kv.WidgetType().Name()
+			if mv.KeyDesc != nil {
+				wb.Tooltip = mv.KeyDesc(kv.Val().Interface())
+			}
 		}
 		if ifaceType {
-			typw := sg.Child(i*ncol + 2).(*gi.ComboBox)
+			typw := sg.Child(i*ncol + colOff + 2).(*gi.ComboBox)
 			typw.ItemsFromTypes(valtypes, false, true, 50)
 			vtyp := kit.NonPtrType(reflect.TypeOf(vv.Val().Interface()))
 			if vtyp == nil {
@@ -234,78 +942,588 @@ func (mv *MapView) ConfigMapGrid() {
 				mvv := recv.Embed(KiT_MapView).(*MapView)
 				mvv.MapChangeValueType(idx, typ)
 			})
+		} else if showTypes {
+			typw := sg.Child(i*ncol + colOff + 2).(*gi.Label)
+			vtyp := kit.NonPtrType(reflect.TypeOf(vv.Val().Interface()))
+			if vtyp == nil {
+				vtyp = valtyp // static type
+			}
+			typw.SetText(vtyp.String())
+			typw.Tooltip = "type of the value (read-only)"
 		}
 		delact := sg.Child(i*ncol + ncol - 1).(*gi.Action)
-		delact.SetIcon("minus")
+		delact.SetIcon(string(mv.DeleteIconName()))
 		delact.Tooltip = "delete item"
 		delact.Data = kv
 		delact.Sty.Template = "giv.MapView.DelAction"
 		delact.ActionSig.ConnectOnly(mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 			act := send.(*gi.Action)
 			mvv := recv.Embed(KiT_MapView).(*MapView)
-			mvv.MapDelete(act.Data.(ValueView).Val())
+			mvv.MapDeleteAction(act.Data.(ValueView).Val())
 		})
 	}
 	sg.UpdateEnd(updt)
+	mv.UpdateTitle()
 }
 
-// SetChanged sets the Changed flag and emits the ViewSig signal for the
-// SliceView, indicating that some kind of edit / change has taken place to
-// the table data.  It isn't really practical to record all the different
-// types of changes, so this is just generic.
-func (mv *MapView) SetChanged() {
-	mv.Changed = true
-	mv.ViewSig.Emit(mv.This(), 0, nil)
-	mv.ToolBar().UpdateActions() // nil safe
-}
-
-// MapChangeValueType changes the type of the value for given map element at
-// idx -- for maps with interface{} values
-func (mv *MapView) MapChangeValueType(idx int, typ reflect.Type) {
-	if kit.IfaceIsNil(mv.Map) {
+// ConfigMapModelGrid configures the map grid from a MapModel instead of a
+// plain map value -- called automatically by ConfigMapGrid (see MapModel).
+// Key widgets are read-only: keys cannot be renamed in-place through a model.
+func (mv *MapView) ConfigMapModelGrid(mm MapModel) {
+	if len(mm.Keys()) == 0 {
+		mv.ConfigEmptyGrid()
 		return
 	}
-	updt := mv.UpdateStart()
-	defer mv.UpdateEnd(updt)
-
-	keyv := mv.Keys[idx]
-	ck := kit.NonPtrValue(keyv.Val()) // current key value
-	valv := mv.Values[idx]
-	cv := kit.NonPtrValue(valv.Val()) // current val value
-
-	// create a new item of selected type, and attempt to convert existing to it
-	var evn reflect.Value
-	if kit.ValueIsZero(cv) {
-		evn = kit.MakeOfType(typ)
+	sg := mv.MapGrid()
+	sg.Lay = gi.LayoutGrid
+	if mv.Striped {
+		sg.Stripes = gi.RowStripes
 	} else {
-		evn = kit.CloneToType(typ, cv.Interface())
+		sg.Stripes = gi.NoStripes
 	}
-	ov := kit.NonPtrValue(reflect.ValueOf(mv.Map))
-	valv.AsValueViewBase().Value = evn.Elem()
-	ov.SetMapIndex(ck, evn.Elem())
-	if mv.TmpSave != nil {
-		mv.TmpSave.SaveTmp()
+	// setting a pref here is key for giving it a scrollbar in larger context
+	sg.SetMinPrefHeight(units.NewEm(1.5))
+	sg.SetMinPrefWidth(units.NewEm(10))
+	sg.SetStretchMax() // for this to work, ALL layers above need it too
+	config := kit.TypeAndNameList{}
+	// always start fresh!
+	mv.Keys = make([]ValueView, 0)
+	mv.Values = make([]ValueView, 0)
+
+	ncol := 3
+	dragOrd := mv.SortMode == MapSortNone
+	colOff := 0
+	if dragOrd { // extra leading column with the drag-to-reorder handles
+		ncol++
+		colOff = 1
+	}
+	sg.SetProp("columns", ncol)
+
+	keys := mm.Keys()
+	if mv.SortMode == MapSortNone {
+		keys = mv.OrderedKeys(keys)
+	} else {
+		sort.Slice(keys, func(i, j int) bool {
+			if mv.SortMode == MapSortVals {
+				vi, _ := mm.Get(keys[i])
+				vj, _ := mm.Get(keys[j])
+				return kit.ToString(vi) < kit.ToString(vj)
+			}
+			return kit.ToString(keys[i]) < kit.ToString(keys[j])
+		})
+	}
+	for _, key := range keys {
+		kv := ToValueView(key, "")
+		if kv == nil { // shouldn't happen
+			continue
+		}
+		kp := reflect.New(mm.KeyType())
+		kp.Elem().Set(reflect.ValueOf(key))
+		kv.AsValueViewBase().SetSoloValue(kp.Elem())
+
+		val, _ := mm.Get(key)
+		if val == nil {
+			val = reflect.New(mm.ElemType()).Elem().Interface()
+		}
+		vv := ToValueView(val, "")
+		if vv == nil { // shouldn't happen
+			continue
+		}
+		vp := reflect.New(mm.ElemType())
+		vp.Elem().Set(reflect.ValueOf(val))
+		vv.AsValueViewBase().SetSoloValue(vp.Elem())
+
+		keytxt := kit.ToString(key)
+		keynm := fmt.Sprintf("key-%v", keytxt)
+		valnm := fmt.Sprintf("value-%v", keytxt)
+		delnm := fmt.Sprintf("del-%v", keytxt)
+
+		mv.SetValTags(vv, mm.ElemType(), keytxt) // must set before WidgetType is chosen
+
+		if dragOrd {
+			config.Add(gi.KiT_Label, fmt.Sprintf("drag-%v", keytxt))
+		}
+		config.Add(kv.WidgetType(), keynm)
+		config.Add(vv.WidgetType(), valnm)
+		config.Add(gi.KiT_Action, delnm)
+		mv.Keys = append(mv.Keys, kv)
+		mv.Values = append(mv.Values, vv)
+	}
+	mv.GridMaxHeight(sg, len(mv.Keys))
+	mods, updt := sg.ConfigChildren(config, true)
+	if mods {
+		sg.SetFullReRender()
+	} else {
+		updt = sg.UpdateStart() // cover rest of updates, which can happen even if same config
+	}
+	mv.PrevVals = make([]interface{}, len(mv.Values))
+	for i, vv := range mv.Values {
+		if vval := kit.NonPtrValue(vv.Val()); vval.IsValid() {
+			mv.PrevVals[i] = vval.Interface()
+		}
+		idx := i
+		vvb := vv.AsValueViewBase()
+		vvb.ViewSig.ConnectOnly(mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv, _ := recv.Embed(KiT_MapView).(*MapView)
+			mvv.ModelValueChange(idx)
+		})
+		if dragOrd {
+			hdl := sg.Child(i * ncol).(*gi.Label)
+			hdl.Text = "\u2630" // trigram -- standard drag handle glyph
+			hdl.Tooltip = "drag to move this entry to a different position"
+			hdl.Sty.Template = "giv.MapView.DragHandle"
+		}
+		keyw := sg.Child(i*ncol + colOff).(gi.Node2D)
+		widg := sg.Child(i*ncol + colOff + 1).(gi.Node2D)
+		kv := mv.Keys[i]
+		kv.ConfigWidget(keyw)
+		vv.ConfigWidget(widg)
+		mv.SetColProps(keyw, widg)
+		wb := widg.AsWidget()
+		if wb != nil {
+			wb.Sty.Template = "giv.MapView.ItemWidget." + vv.WidgetType().Name()
+			if tt := StructValDesc(vv.Val()); tt != "" {
+				wb.Tooltip = tt
+			}
+		}
+		wb = keyw.AsWidget()
+		if wb != nil {
+			wb.Sty.Template = "giv.MapView.KeyWidget." + kv.WidgetType().Name()
+			wb.SetInactive() // keys cannot be renamed in-place through a model
+			if mv.KeyDesc != nil {
+				wb.Tooltip = mv.KeyDesc(kv.Val().Interface())
+			}
+		}
+		delact := sg.Child(i*ncol + ncol - 1).(*gi.Action)
+		delact.SetIcon(string(mv.DeleteIconName()))
+		delact.Tooltip = "delete item"
+		delact.Data = kv
+		delact.Sty.Template = "giv.MapView.DelAction"
+		delact.ActionSig.ConnectOnly(mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			act := send.(*gi.Action)
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.MapDeleteAction(act.Data.(ValueView).Val())
+		})
+	}
+	sg.UpdateEnd(updt)
+	mv.UpdateTitle()
+}
+
+// MapValTagNames are the constraint tag names read from properties
+// registered on the map's declared element type in kit.Types -- see
+// MapView.SetValTags.
+var MapValTagNames = []string{"min", "max", "step", "enum"}
+
+// SetValTags sets value-widget constraint tags (min, max, step, enum) on the
+// given value view, from (in increasing priority): properties registered on
+// the map's declared element type in kit.Types, the ValTags applying to all
+// entries, and any per-key KeyValTags for given key text.  Must be called
+// during grid config, before the widget type is chosen.
+func (mv *MapView) SetValTags(vv ValueView, elemtyp reflect.Type, keytxt string) {
+	if elemtyp != nil {
+		for _, tag := range MapValTagNames {
+			if tp, ok := kit.Types.Prop(kit.NonPtrType(elemtyp), tag); ok {
+				vv.SetTag(tag, kit.ToString(tp))
+			}
+		}
+	}
+	if mv.ValTags != nil {
+		vv.SetTags(mv.ValTags)
+	}
+	if mv.KeyValTags != nil {
+		if tags, ok := mv.KeyValTags[keytxt]; ok {
+			vv.SetTags(tags)
+		}
+	}
+}
+
+// SetColProps applies the configurable column width and alignment settings
+// (KeyColWidth, ValColWidth, KeyColAlign, ValColAlign) to the given key and
+// value widgets -- called during grid config for each row.  A fixed
+// KeyColWidth with a zero ValColWidth makes the value column stretch to fill
+// the remaining width.
+func (mv *MapView) SetColProps(keyw, valw gi.Node2D) {
+	if kwb := keyw.AsWidget(); kwb != nil {
+		if mv.KeyColWidth.Val > 0 {
+			kwb.SetProp("width", mv.KeyColWidth)
+			kwb.SetProp("max-width", mv.KeyColWidth)
+		}
+		if mv.KeyColAlign != gi.AlignLeft {
+			kwb.SetProp("horizontal-align", mv.KeyColAlign)
+		}
+	}
+	if vwb := valw.AsWidget(); vwb != nil {
+		if mv.ValColWidth.Val > 0 {
+			vwb.SetProp("width", mv.ValColWidth)
+			vwb.SetProp("max-width", mv.ValColWidth)
+		} else if mv.KeyColWidth.Val > 0 {
+			vwb.SetStretchMaxWidth() // fill the width freed up by the fixed keys
+		}
+		if mv.ValColAlign != gi.AlignLeft {
+			vwb.SetProp("horizontal-align", mv.ValColAlign)
+		}
+	}
+}
+
+// ModelValueChange pushes an edited value at given display index back into
+// the MapModel, records undo, and sets changed -- connected to the value
+// ViewSig in model mode, where edits go into a standalone copy of the value
+// rather than directly into a map.
+func (mv *MapView) ModelValueChange(idx int) {
+	mm := mv.Model()
+	if mm == nil || idx < 0 || idx >= len(mv.Values) || idx >= len(mv.Keys) {
+		return
+	}
+	key := kit.NonPtrValue(mv.Keys[idx].Val()).Interface()
+	val := kit.NonPtrValue(mv.Values[idx].Val()).Interface()
+	mm.Set(key, val)
+	mv.RecordValueChange(idx)
+	mv.SetChanged()
+}
+
+// StructValDesc returns tooltip text for given value, if it is a struct
+// (or pointer to one): a summary of the struct's fields with their desc
+// tags, following the same desc-tag convention used throughout -- returns
+// "" for non-struct values or structs without any desc tags.
+func StructValDesc(val reflect.Value) string {
+	typ := kit.NonPtrType(val.Type())
+	if typ.Kind() == reflect.Interface {
+		vnp := kit.NonPtrValue(val)
+		if !vnp.IsValid() || kit.ValueIsZero(vnp) {
+			return ""
+		}
+		typ = kit.NonPtrType(vnp.Type())
+	}
+	if typ.Kind() != reflect.Struct {
+		return ""
+	}
+	tt := ""
+	for i := 0; i < typ.NumField(); i++ {
+		fld := typ.Field(i)
+		dsc := fld.Tag.Get("desc")
+		if dsc == "" {
+			continue
+		}
+		if tt != "" {
+			tt += "\n"
+		}
+		tt += fld.Name + ": " + dsc
+	}
+	return tt
+}
+
+// SetChanged sets the Changed flag and emits the ViewSig signal for the
+// SliceView, indicating that some kind of edit / change has taken place to
+// the table data.  It isn't really practical to record all the different
+// types of changes, so this is just generic.
+func (mv *MapView) SetChanged() {
+	mv.Changed = true
+	mv.ViewSig.Emit(mv.This(), 0, nil)
+	mv.ToolBar().UpdateActions() // nil safe
+}
+
+// MapChangeValueType changes the type of the value for given map element at
+// idx -- for maps with interface{} values
+func (mv *MapView) MapChangeValueType(idx int, typ reflect.Type) {
+	if kit.IfaceIsNil(mv.Map) {
+		return
+	}
+	updt := mv.UpdateStart()
+	defer mv.UpdateEnd(updt)
+
+	keyv := mv.Keys[idx]
+	ck := kit.NonPtrValue(keyv.Val()) // current key value
+	valv := mv.Values[idx]
+	cv := kit.NonPtrValue(valv.Val()) // current val value
+
+	// create a new item of selected type, and attempt to convert existing to it
+	var evn reflect.Value
+	if kit.ValueIsZero(cv) {
+		evn = kit.MakeOfType(typ)
+	} else {
+		evn = kit.CloneToType(typ, cv.Interface())
+	}
+	ov := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	valv.AsValueViewBase().Value = evn.Elem()
+	ov.SetMapIndex(ck, evn.Elem())
+	if mv.TmpSave != nil {
+		mv.TmpSave.SaveTmp()
 	}
 	mv.ConfigMapGrid()
 	mv.SetChanged()
 }
 
-// ToggleSort toggles sorting by values vs. keys
+// MapViewState is a JSON-serializable snapshot of the presentation
+// configuration of a MapView -- the view arrangement, not the map data:
+// sort mode, cached row order, collapsed state, column sizing / alignment,
+// and read-only state.  Save with SaveViewState and restore with
+// LoadViewState, so apps can remember how a user arranged a particular map
+// editor across sessions.
+type MapViewState struct {
+	SortMode       MapSortModes `desc:"how the entries are ordered for display -- see MapView.SortMode"`
+	KeyOrder       []string     `desc:"cached display order for MapSortNone, as key text -- mapped back onto the current map keys on load (keys no longer present are dropped, new keys appended)"`
+	Collapsed      bool         `desc:"collapsed state, for Collapsible map views"`
+	ShowTypes      bool         `desc:"show the read-only value type column"`
+	Striped        bool         `desc:"alternating row background colors"`
+	MaxVisibleRows int          `desc:"maximum rows before the grid scrolls -- 0 = no limit"`
+	KeyColWidth    units.Value  `desc:"fixed key column width -- zero = natural"`
+	ValColWidth    units.Value  `desc:"fixed value column width -- zero = natural / stretch"`
+	KeyColAlign    gi.Align     `desc:"key column horizontal alignment"`
+	ValColAlign    gi.Align     `desc:"value column horizontal alignment"`
+	Inactive       bool         `desc:"view is read-only (inactive)"`
+}
+
+// SaveViewState returns a snapshot of the current presentation configuration
+// of this map view -- see MapViewState.
+func (mv *MapView) SaveViewState() MapViewState {
+	vs := MapViewState{
+		SortMode:       mv.SortMode,
+		Collapsed:      mv.Collapsed,
+		ShowTypes:      mv.ShowTypes,
+		Striped:        mv.Striped,
+		MaxVisibleRows: mv.MaxVisibleRows,
+		KeyColWidth:    mv.KeyColWidth,
+		ValColWidth:    mv.ValColWidth,
+		KeyColAlign:    mv.KeyColAlign,
+		ValColAlign:    mv.ValColAlign,
+		Inactive:       mv.IsInactive(),
+	}
+	if mv.SortMode == MapSortNone {
+		vs.KeyOrder = make([]string, len(mv.KeyOrder))
+		for i, k := range mv.KeyOrder {
+			vs.KeyOrder[i] = kit.ToString(k)
+		}
+	}
+	return vs
+}
+
+// LoadViewState restores a presentation configuration previously captured
+// with SaveViewState, and reconfigures the view accordingly -- the cached
+// row order is mapped back onto the current map keys by their text
+// representation, so it survives serialization of any key type.
+func (mv *MapView) LoadViewState(vs MapViewState) {
+	mv.SortMode = vs.SortMode
+	mv.ShowTypes = vs.ShowTypes
+	mv.Striped = vs.Striped
+	mv.MaxVisibleRows = vs.MaxVisibleRows
+	mv.KeyColWidth = vs.KeyColWidth
+	mv.ValColWidth = vs.ValColWidth
+	mv.KeyColAlign = vs.KeyColAlign
+	mv.ValColAlign = vs.ValColAlign
+	mv.SetInactiveState(vs.Inactive)
+	if mv.Collapsible {
+		mv.Collapsed = vs.Collapsed
+	}
+	if vs.SortMode == MapSortNone && len(vs.KeyOrder) > 0 {
+		mv.KeyOrder = mv.keyOrderFromTexts(vs.KeyOrder)
+	}
+	updt := mv.UpdateStart()
+	mv.SetFullReRender()
+	mv.Config()
+	mv.UpdateEnd(updt)
+}
+
+// keyOrderFromTexts maps the given key-text display order back onto the
+// current map keys, for LoadViewState: keys no longer present are dropped,
+// and keys without a recorded position are appended at the end.
+func (mv *MapView) keyOrderFromTexts(txts []string) []interface{} {
+	var cur []interface{}
+	if mm := mv.Model(); mm != nil {
+		cur = mm.Keys()
+	} else if !kit.IfaceIsNil(mv.Map) {
+		mpvnp := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+		for _, k := range mpvnp.MapKeys() {
+			cur = append(cur, kit.NonPtrValue(k).Interface())
+		}
+	}
+	curm := make(map[string]interface{}, len(cur))
+	for _, k := range cur {
+		curm[kit.ToString(k)] = k
+	}
+	keys := make([]interface{}, 0, len(cur))
+	for _, kt := range txts {
+		if k, ok := curm[kt]; ok {
+			keys = append(keys, k)
+			delete(curm, kt)
+		}
+	}
+	for _, k := range cur { // keys without a recorded position
+		if _, ok := curm[kit.ToString(k)]; ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// ToggleSort cycles through the sort modes: by keys, by values, or none
+// (cached user-controlled order, with drag handles for reordering)
 func (mv *MapView) ToggleSort() {
-	mv.SortVals = !mv.SortVals
+	mv.SortMode = (mv.SortMode + 1) % MapSortModesN
+	mv.SetFullReRender() // the drag-handle column comes and goes
 	mv.ConfigMapGrid()
 }
 
-// MapAdd adds a new entry to the map
+// OrderedKeys arranges the given current map keys according to the cached
+// KeyOrder, for MapSortNone mode: keys no longer present in the map are
+// dropped from the cache, and new keys are appended at the end, in given
+// order.  The cache is updated to match the returned keys.
+func (mv *MapView) OrderedKeys(cur []interface{}) []interface{} {
+	curm := make(map[interface{}]struct{}, len(cur))
+	for _, k := range cur {
+		curm[k] = struct{}{}
+	}
+	keys := make([]interface{}, 0, len(cur))
+	for _, ko := range mv.KeyOrder {
+		if _, ok := curm[ko]; ok {
+			keys = append(keys, ko)
+			delete(curm, ko)
+		}
+	}
+	for _, k := range cur { // new keys not yet in the cache
+		if _, ok := curm[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	mv.KeyOrder = keys
+	return keys
+}
+
+// OrderedMapKeys is the reflect.Value version of OrderedKeys, for the keys
+// of the given (non-pointer) map value -- see OrderedKeys.
+func (mv *MapView) OrderedMapKeys(mpvnp reflect.Value) []reflect.Value {
+	cur := mpvnp.MapKeys()
+	curm := make(map[interface{}]reflect.Value, len(cur))
+	for _, k := range cur {
+		curm[kit.NonPtrValue(k).Interface()] = k
+	}
+	keys := make([]reflect.Value, 0, len(cur))
+	nord := make([]interface{}, 0, len(cur))
+	for _, ko := range mv.KeyOrder {
+		if k, ok := curm[ko]; ok {
+			keys = append(keys, k)
+			nord = append(nord, ko)
+			delete(curm, ko)
+		}
+	}
+	for _, k := range cur { // new keys not yet in the cache
+		kif := kit.NonPtrValue(k).Interface()
+		if _, ok := curm[kif]; ok {
+			keys = append(keys, k)
+			nord = append(nord, kif)
+		}
+	}
+	mv.KeyOrder = nord
+	return keys
+}
+
+// MoveRow moves the map entry at the given row to the given target row,
+// rearranging the cached key order and re-displaying -- only applies in
+// MapSortNone mode, where the user controls the sequence (this is what the
+// drag handles call).
+func (mv *MapView) MoveRow(from, to int) {
+	if mv.SortMode != MapSortNone {
+		return
+	}
+	sz := len(mv.KeyOrder)
+	if from == to || from < 0 || from >= sz || to < 0 || to >= sz {
+		return
+	}
+	ko := mv.KeyOrder[from]
+	ord := append(mv.KeyOrder[:from:from], mv.KeyOrder[from+1:]...)
+	nord := make([]interface{}, 0, sz)
+	nord = append(nord, ord[:to]...)
+	nord = append(nord, ko)
+	nord = append(nord, ord[to:]...)
+	mv.KeyOrder = nord
+	updt := mv.UpdateStart()
+	mv.SetFullReRender()
+	mv.ConfigMapGrid()
+	mv.SetChanged()
+	mv.UpdateEnd(updt)
+}
+
+// DragHandleRow returns the row whose drag handle contains the given window
+// position, or -1 if none -- only valid in MapSortNone mode, where the
+// first grid column holds the drag handles.
+func (mv *MapView) DragHandleRow(pos image.Point) int {
+	if mv.SortMode != MapSortNone {
+		return -1
+	}
+	nk := len(mv.Keys)
+	if nk == 0 {
+		return -1
+	}
+	sg := mv.MapGrid()
+	ncol := len(sg.Kids) / nk
+	for r := 0; r < nk; r++ {
+		wb := sg.Child(r * ncol).(gi.Node2D).AsWidget()
+		if wb != nil && pos.In(wb.WinBBox) {
+			return r
+		}
+	}
+	return -1
+}
+
+// MapAdd adds a new entry to the map -- if the map is nil but a MapType has
+// been set (see SetMapType), a new map of that type is allocated first and
+// becomes the Map.
 func (mv *MapView) MapAdd() {
 	if kit.IfaceIsNil(mv.Map) {
+		if mv.MapType == nil || mv.MapType.Kind() != reflect.Map {
+			return
+		}
+		nmp := reflect.New(mv.MapType) // pointer, so the map is addressable / settable
+		nmp.Elem().Set(reflect.MakeMap(mv.MapType))
+		mv.Map = nmp.Interface()
+	}
+	if !mv.InBatch {
+		updt := mv.UpdateStart()
+		defer mv.UpdateEnd(updt)
+	}
+
+	if mm := mv.Model(); mm != nil {
+		nkey := reflect.New(mm.KeyType()).Elem().Interface() // zero key
+		et := mm.ElemType()
+		var nval interface{}
+		if et.Kind() == reflect.Ptr { // allocate so the new row is immediately editable
+			nval = reflect.New(et.Elem()).Interface()
+		} else {
+			nval = reflect.New(et).Elem().Interface()
+		}
+		if _, has := mm.Get(nkey); !has { // don't clobber existing zero-key entry
+			mm.Set(nkey, nval)
+			mv.SaveUndo(MapViewUndoRec{Add: true, Key: nkey, NewVal: nval})
+		}
+		if mv.InBatch { // rebuild / re-render happens once in EndBatch
+			return
+		}
+		if mv.TmpSave != nil {
+			mv.TmpSave.SaveTmp()
+		}
+		mv.ConfigMapGrid()
+		mv.SetChanged()
 		return
 	}
-	updt := mv.UpdateStart()
-	defer mv.UpdateEnd(updt)
 
 	kit.MapAdd(mv.Map)
 
+	nkey := reflect.New(kit.MapKeyType(mv.Map)).Elem() // zero key, as added by kit.MapAdd
+	ov := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	if vtyp := ov.Type().Elem(); vtyp.Kind() == reflect.Ptr {
+		if cv := ov.MapIndex(nkey); cv.IsValid() && cv.IsNil() {
+			// allocate so the new row is immediately editable
+			ov.SetMapIndex(nkey, reflect.New(vtyp.Elem()))
+		}
+	}
+	rec := MapViewUndoRec{Add: true, Key: nkey.Interface()}
+	if nv := ov.MapIndex(nkey); nv.IsValid() {
+		rec.NewVal = nv.Interface()
+	}
+	mv.SaveUndo(rec)
+
+	if mv.InBatch { // rebuild / re-render happens once in EndBatch
+		return
+	}
 	if mv.TmpSave != nil {
 		mv.TmpSave.SaveTmp()
 	}
@@ -318,11 +1536,302 @@ func (mv *MapView) MapDelete(key reflect.Value) {
 	if kit.IfaceIsNil(mv.Map) {
 		return
 	}
+	if !mv.InBatch {
+		updt := mv.UpdateStart()
+		defer mv.UpdateEnd(updt)
+	}
+
+	dk := kit.NonPtrValue(key)
+
+	if mm := mv.Model(); mm != nil {
+		k := dk.Interface()
+		rec := MapViewUndoRec{Del: true, Key: k}
+		if ov, has := mm.Get(k); has { // snapshot before delete -- otherwise lost
+			rec.OldVal = ov
+		}
+		mm.Delete(k)
+		mv.SaveUndo(rec)
+		if mv.InBatch { // rebuild / re-render happens once in EndBatch
+			return
+		}
+		if mv.TmpSave != nil {
+			mv.TmpSave.SaveTmp()
+		}
+		mv.ConfigMapGrid()
+		mv.SetChanged()
+		return
+	}
+
+	ov := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	rec := MapViewUndoRec{Del: true, Key: dk.Interface()}
+	if dv := ov.MapIndex(dk); dv.IsValid() { // snapshot before delete -- otherwise lost
+		rec.OldVal = dv.Interface()
+	}
+
+	kit.MapDeleteValue(mv.Map, dk)
+
+	mv.SaveUndo(rec)
+	if mv.InBatch { // rebuild / re-render happens once in EndBatch
+		return
+	}
+	if mv.TmpSave != nil {
+		mv.TmpSave.SaveTmp()
+	}
+	mv.ConfigMapGrid()
+	mv.SetChanged()
+}
+
+// MapDeleteAction deletes the map entry with the given key, asking for
+// confirmation first if DeleteConfirm is set -- this is what the per-row
+// delete actions and the keyboard delete shortcut call.
+func (mv *MapView) MapDeleteAction(key reflect.Value) {
+	if !mv.DeleteConfirm {
+		mv.MapDelete(key)
+		return
+	}
+	keytxt := kit.ToString(kit.NonPtrValue(key).Interface())
+	gi.ChoiceDialog(mv.Viewport, gi.DlgOpts{Title: "Delete Entry?",
+		Prompt: fmt.Sprintf("Delete the entry with key: %v?", keytxt)},
+		[]string{"Delete", "Cancel"}, mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			if sig == 0 {
+				mvv := recv.Embed(KiT_MapView).(*MapView)
+				mvv.MapDelete(key)
+			}
+		})
+}
+
+// MapDuplicate duplicates the map entry at given row index, using a derived
+// key: "-copy" is appended to the existing key text (then -copy1, -copy2,
+// etc as needed) to avoid collisions.  Only works for key types that can be
+// set from a string (e.g., not for numeric keys).
+func (mv *MapView) MapDuplicate(idx int) {
+	if kit.IfaceIsNil(mv.Map) || idx < 0 || idx >= len(mv.Keys) {
+		return
+	}
+	updt := mv.UpdateStart()
+	defer mv.UpdateEnd(updt)
+
+	key := kit.NonPtrValue(mv.Keys[idx].Val())
+
+	if mm := mv.Model(); mm != nil {
+		k := key.Interface()
+		val, has := mm.Get(k)
+		if !has {
+			return
+		}
+		kt := mm.KeyType()
+		keytxt := kit.ToString(k)
+		nkp := reflect.New(kt)
+		nm := keytxt + "-copy"
+		for cnt := 1; ; cnt++ {
+			if !kit.SetRobust(nkp.Interface(), nm) {
+				fmt.Printf("giv.MapView MapDuplicate: cannot derive new key of type %v from: %v\n", kt, nm)
+				return
+			}
+			if _, has := mm.Get(nkp.Elem().Interface()); !has {
+				break
+			}
+			nm = fmt.Sprintf("%v-copy%v", keytxt, cnt)
+		}
+		nkey := nkp.Elem().Interface()
+		mm.Set(nkey, val)
+		mv.SaveUndo(MapViewUndoRec{Add: true, Key: nkey, NewVal: val})
+		if mv.TmpSave != nil {
+			mv.TmpSave.SaveTmp()
+		}
+		mv.ConfigMapGrid()
+		mv.SetChanged()
+		return
+	}
+
+	mpvnp := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	val := mpvnp.MapIndex(key)
+	if !val.IsValid() {
+		return
+	}
+	kt := kit.MapKeyType(mv.Map)
+	keytxt := kit.ToString(key.Interface())
+	nkp := reflect.New(kt)
+	nm := keytxt + "-copy"
+	for cnt := 1; ; cnt++ {
+		if !kit.SetRobust(nkp.Interface(), nm) {
+			fmt.Printf("giv.MapView MapDuplicate: cannot derive new key of type %v from: %v\n", kt, nm)
+			return
+		}
+		if !mpvnp.MapIndex(nkp.Elem()).IsValid() {
+			break
+		}
+		nm = fmt.Sprintf("%v-copy%v", keytxt, cnt)
+	}
+	nkey := nkp.Elem()
+	mpvnp.SetMapIndex(nkey, val)
+
+	rec := MapViewUndoRec{Add: true, Key: nkey.Interface(), NewVal: val.Interface()}
+	mv.SaveUndo(rec)
+
+	if mv.TmpSave != nil {
+		mv.TmpSave.SaveTmp()
+	}
+	mv.ConfigMapGrid()
+	mv.SetChanged()
+}
+
+// CopyKeyToClipboard copies the key text of the entry at given row index
+// to the system clipboard.
+func (mv *MapView) CopyKeyToClipboard(idx int) {
+	if idx < 0 || idx >= len(mv.Keys) || mv.Viewport == nil || mv.Viewport.Win == nil {
+		return
+	}
+	keytxt := kit.ToString(kit.NonPtrValue(mv.Keys[idx].Val()).Interface())
+	oswin.TheApp.ClipBoard(mv.Viewport.Win.OSWin).Write(mimedata.NewText(keytxt))
+}
+
+// CopyValueToClipboard copies the value text of the entry at given row index
+// to the system clipboard.
+func (mv *MapView) CopyValueToClipboard(idx int) {
+	if idx < 0 || idx >= len(mv.Values) || mv.Viewport == nil || mv.Viewport.Win == nil {
+		return
+	}
+	valtxt := kit.ToString(kit.NonPtrValue(mv.Values[idx].Val()).Interface())
+	oswin.TheApp.ClipBoard(mv.Viewport.Win.OSWin).Write(mimedata.NewText(valtxt))
+}
+
+// BeginBatch starts batch update mode for programmatic bulk edits of the
+// map (e.g., loading many entries): individual MapAdd / MapDelete calls
+// skip their per-operation rebuild and re-render, and a single
+// ConfigMapGrid + re-render is done in EndBatch.  Must be balanced with
+// a corresponding EndBatch call.
+func (mv *MapView) BeginBatch() {
+	mv.InBatch = true
+}
+
+// EndBatch ends batch update mode, doing a single full rebuild and
+// re-render reflecting all the accumulated changes.
+func (mv *MapView) EndBatch() {
+	if !mv.InBatch {
+		return
+	}
+	mv.InBatch = false
+	updt := mv.UpdateStart()
+	defer mv.UpdateEnd(updt)
+	if mv.TmpSave != nil {
+		mv.TmpSave.SaveTmp()
+	}
+	mv.ConfigMapGrid()
+	mv.SetChanged()
+}
+
+// SaveUndo records given edit on the undo stack, clearing any redo history
+// (a new edit invalidates previously-undone state).
+func (mv *MapView) SaveUndo(rec MapViewUndoRec) {
+	mv.UndoStack = append(mv.UndoStack, rec)
+	mv.RedoStack = nil
+}
+
+// RecordValueChange records an undo record for an edit of the value at given
+// index in the current display order, using the prior-value snapshot taken
+// when the grid was last configured.  Called from the value ViewSig.
+func (mv *MapView) RecordValueChange(idx int) {
+	if idx < 0 || idx >= len(mv.Values) || idx >= len(mv.PrevVals) {
+		return
+	}
+	rec := MapViewUndoRec{OldVal: mv.PrevVals[idx]}
+	if kval := kit.NonPtrValue(mv.Keys[idx].Val()); kval.IsValid() {
+		rec.Key = kval.Interface()
+	}
+	if nval := kit.NonPtrValue(mv.Values[idx].Val()); nval.IsValid() {
+		rec.NewVal = nval.Interface()
+	}
+	mv.SaveUndo(rec)
+	mv.PrevVals[idx] = rec.NewVal
+}
+
+// mapUndoVal returns a reflect.Value for given snapshot value of given type,
+// handling nil snapshots (zero value of the type).  Value-change snapshots
+// are taken of the dereferenced value (see RecordValueChange), so for
+// pointer element types a new pointee is allocated to restore into.
+func mapUndoVal(typ reflect.Type, v interface{}) reflect.Value {
+	if v == nil {
+		return reflect.New(typ).Elem()
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Type().AssignableTo(typ) {
+		return rv
+	}
+	if typ.Kind() == reflect.Ptr && rv.Type().AssignableTo(typ.Elem()) {
+		pv := reflect.New(typ.Elem())
+		pv.Elem().Set(rv)
+		return pv
+	}
+	if rv.Type().ConvertibleTo(typ) {
+		return rv.Convert(typ)
+	}
+	return rv
+}
+
+// Undo reverses the most recent map edit (add, delete, or value change),
+// moving its record to the redo stack.
+func (mv *MapView) Undo() {
+	n := len(mv.UndoStack)
+	if n == 0 || kit.IfaceIsNil(mv.Map) {
+		return
+	}
 	updt := mv.UpdateStart()
 	defer mv.UpdateEnd(updt)
 
-	kit.MapDeleteValue(mv.Map, kit.NonPtrValue(key))
+	rec := mv.UndoStack[n-1]
+	mv.UndoStack = mv.UndoStack[:n-1]
+	if mm := mv.Model(); mm != nil {
+		if rec.Add {
+			mm.Delete(rec.Key)
+		} else { // delete or value change: restore prior value
+			mm.Set(rec.Key, mapUndoVal(mm.ElemType(), rec.OldVal).Interface())
+		}
+	} else {
+		ov := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+		ky := mapUndoVal(ov.Type().Key(), rec.Key)
+		if rec.Add {
+			ov.SetMapIndex(ky, reflect.Value{}) // delete
+		} else { // delete or value change: restore prior value
+			ov.SetMapIndex(ky, mapUndoVal(ov.Type().Elem(), rec.OldVal))
+		}
+	}
+	mv.RedoStack = append(mv.RedoStack, rec)
+	if mv.TmpSave != nil {
+		mv.TmpSave.SaveTmp()
+	}
+	mv.ConfigMapGrid()
+	mv.SetChanged()
+}
 
+// Redo re-applies the most recently undone map edit, moving its record back
+// to the undo stack.
+func (mv *MapView) Redo() {
+	n := len(mv.RedoStack)
+	if n == 0 || kit.IfaceIsNil(mv.Map) {
+		return
+	}
+	updt := mv.UpdateStart()
+	defer mv.UpdateEnd(updt)
+
+	rec := mv.RedoStack[n-1]
+	mv.RedoStack = mv.RedoStack[:n-1]
+	if mm := mv.Model(); mm != nil {
+		if rec.Del {
+			mm.Delete(rec.Key)
+		} else { // add or value change: re-apply new value
+			mm.Set(rec.Key, mapUndoVal(mm.ElemType(), rec.NewVal).Interface())
+		}
+	} else {
+		ov := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+		ky := mapUndoVal(ov.Type().Key(), rec.Key)
+		if rec.Del {
+			ov.SetMapIndex(ky, reflect.Value{}) // delete
+		} else { // add or value change: re-apply new value
+			ov.SetMapIndex(ky, mapUndoVal(ov.Type().Elem(), rec.NewVal))
+		}
+	}
+	mv.UndoStack = append(mv.UndoStack, rec)
 	if mv.TmpSave != nil {
 		mv.TmpSave.SaveTmp()
 	}
@@ -330,15 +1839,215 @@ func (mv *MapView) MapDelete(key reflect.Value) {
 	mv.SetChanged()
 }
 
+// MapToJSON returns a JSON representation of the map contents.  If the map
+// cannot be marshaled directly (e.g., values are not JSON-serializable), it
+// falls back on a map of kit.ToString representations per value.
+func (mv *MapView) MapToJSON() ([]byte, error) {
+	if kit.IfaceIsNil(mv.Map) {
+		return nil, fmt.Errorf("giv.MapView: map is nil")
+	}
+	if mm := mv.Model(); mm != nil {
+		kys := mm.Keys()
+		strmap := make(map[string]interface{}, len(kys))
+		for _, key := range kys {
+			val, _ := mm.Get(key)
+			strmap[kit.ToString(key)] = val
+		}
+		return json.MarshalIndent(strmap, "", "  ")
+	}
+	b, err := json.MarshalIndent(mv.Map, "", "  ")
+	if err == nil {
+		return b, nil
+	}
+	mpvnp := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	strmap := make(map[string]string, mpvnp.Len())
+	keys := kit.MapSort(mv.Map, mv.SortMode != MapSortVals, true)
+	for _, key := range keys {
+		val := mpvnp.MapIndex(key)
+		strmap[kit.ToString(key.Interface())] = kit.ToString(val.Interface())
+	}
+	return json.MarshalIndent(strmap, "", "  ")
+}
+
+// MapToTSV returns a tab-separated key <tab> value representation of the map
+// contents, one line per entry, using kit.ToString for both keys and values.
+func (mv *MapView) MapToTSV() []byte {
+	if kit.IfaceIsNil(mv.Map) {
+		return nil
+	}
+	var buf bytes.Buffer
+	if mm := mv.Model(); mm != nil {
+		kys := mm.Keys()
+		sort.Slice(kys, func(i, j int) bool {
+			return kit.ToString(kys[i]) < kit.ToString(kys[j])
+		})
+		for _, key := range kys {
+			val, _ := mm.Get(key)
+			buf.WriteString(kit.ToString(key))
+			buf.WriteString("\t")
+			buf.WriteString(kit.ToString(val))
+			buf.WriteString("\n")
+		}
+		return buf.Bytes()
+	}
+	mpvnp := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	keys := kit.MapSort(mv.Map, mv.SortMode != MapSortVals, true)
+	for _, key := range keys {
+		val := mpvnp.MapIndex(key)
+		buf.WriteString(kit.ToString(key.Interface()))
+		buf.WriteString("\t")
+		buf.WriteString(kit.ToString(val.Interface()))
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// CopyToClipboard copies the current map contents to the system clipboard,
+// as JSON if asJSON is true, otherwise as tab-separated key / value text.
+func (mv *MapView) CopyToClipboard(asJSON bool) {
+	if kit.IfaceIsNil(mv.Map) || mv.Viewport == nil || mv.Viewport.Win == nil {
+		return
+	}
+	var md mimedata.Mimes
+	if asJSON {
+		b, err := mv.MapToJSON()
+		if err != nil {
+			fmt.Printf("giv.MapView CopyToClipboard: %v\n", err)
+			return
+		}
+		md = mimedata.Mimes{&mimedata.Data{Type: filecat.DataJson, Data: b}}
+	} else {
+		md = mimedata.NewTextBytes(mv.MapToTSV())
+	}
+	oswin.TheApp.ClipBoard(mv.Viewport.Win.OSWin).Write(md)
+}
+
+// MapFromJSON sets the map contents from given JSON data, using reflection
+// on the map's key and element types to populate entries.  If replace is
+// true, any existing entries are first removed, otherwise the JSON entries
+// are merged into the existing map.  Type mismatches are reported in the
+// returned error rather than panicking.
+func (mv *MapView) MapFromJSON(b []byte, replace bool) error {
+	if kit.IfaceIsNil(mv.Map) {
+		return fmt.Errorf("giv.MapView: map is nil")
+	}
+	if mm := mv.Model(); mm != nil {
+		nmpt := reflect.MapOf(reflect.TypeOf(""), mm.ElemType())
+		nmp := reflect.New(nmpt)
+		err := json.Unmarshal(b, nmp.Interface())
+		if err != nil {
+			return fmt.Errorf("giv.MapView MapFromJSON: %v", err)
+		}
+		if replace {
+			for _, key := range mm.Keys() {
+				mm.Delete(key)
+			}
+		}
+		nmpv := nmp.Elem()
+		for _, key := range nmpv.MapKeys() {
+			kp := reflect.New(mm.KeyType())
+			if !kit.SetRobust(kp.Interface(), key.Interface()) {
+				return fmt.Errorf("giv.MapView MapFromJSON: cannot convert key %v to type %v", key.Interface(), mm.KeyType())
+			}
+			mm.Set(kp.Elem().Interface(), nmpv.MapIndex(key).Interface())
+		}
+		return nil
+	}
+	mpvnp := kit.NonPtrValue(reflect.ValueOf(mv.Map))
+	nmp := reflect.New(mpvnp.Type())
+	err := json.Unmarshal(b, nmp.Interface())
+	if err != nil {
+		return fmt.Errorf("giv.MapView MapFromJSON: %v", err)
+	}
+	if replace {
+		for _, key := range mpvnp.MapKeys() {
+			mpvnp.SetMapIndex(key, reflect.Value{})
+		}
+	}
+	nmpv := nmp.Elem()
+	for _, key := range nmpv.MapKeys() {
+		mpvnp.SetMapIndex(key, nmpv.MapIndex(key))
+	}
+	return nil
+}
+
+// ImportFromClipboard reads JSON from the system clipboard and populates the
+// map from it -- if replace is true, existing entries are replaced entirely,
+// otherwise the new entries are merged in.  Errors (bad JSON, key / value
+// type mismatches) are reported in a dialog, and the grid rebuilds after a
+// successful import.
+func (mv *MapView) ImportFromClipboard(replace bool) {
+	if kit.IfaceIsNil(mv.Map) || mv.Viewport == nil || mv.Viewport.Win == nil {
+		return
+	}
+	md := oswin.TheApp.ClipBoard(mv.Viewport.Win.OSWin).Read([]string{filecat.DataJson, filecat.TextPlain})
+	if md == nil {
+		return
+	}
+	var b []byte
+	for _, d := range md {
+		if d.Type == filecat.DataJson || d.Type == filecat.TextPlain {
+			b = d.Data
+			break
+		}
+	}
+	if b == nil {
+		return
+	}
+	updt := mv.UpdateStart()
+	defer mv.UpdateEnd(updt)
+	err := mv.MapFromJSON(b, replace)
+	if err != nil {
+		log.Println(err)
+		gi.PromptDialog(mv.Viewport, gi.DlgOpts{Title: "Map Import Failed", Prompt: err.Error()}, gi.AddOk, gi.NoCancel, nil, nil)
+		return
+	}
+	if mv.TmpSave != nil {
+		mv.TmpSave.SaveTmp()
+	}
+	mv.ConfigMapGrid()
+	mv.SetChanged()
+}
+
+// AddLabelText returns the label for the toolbar Add action: AddLabel if
+// set, otherwise the default "Add".
+func (mv *MapView) AddLabelText() string {
+	if mv.AddLabel != "" {
+		return mv.AddLabel
+	}
+	return "Add"
+}
+
+// AddIconName returns the icon for the toolbar Add action: AddIcon if set,
+// otherwise the default "plus".
+func (mv *MapView) AddIconName() gi.IconName {
+	if mv.AddIcon != "" {
+		return mv.AddIcon
+	}
+	return gi.IconName("plus")
+}
+
+// DeleteIconName returns the icon for the per-row delete action: DeleteIcon
+// if set, otherwise the default "minus".
+func (mv *MapView) DeleteIconName() gi.IconName {
+	if mv.DeleteIcon != "" {
+		return mv.DeleteIcon
+	}
+	return gi.IconName("minus")
+}
+
 // ConfigToolbar configures the toolbar actions
 func (mv *MapView) ConfigToolbar() {
-	if kit.IfaceIsNil(mv.Map) || mv.IsInactive() {
+	if (kit.IfaceIsNil(mv.Map) && mv.MapType == nil) || mv.IsInactive() {
 		return
 	}
 	if &mv.ToolbarMap == &mv.Map { // maps are not comparable
 		return
 	}
 	tb := mv.ToolBar()
+	if tb == nil { // collapsed
+		return
+	}
 	if len(*tb.Children()) == 0 {
 		tb.SetStretchMaxWidth()
 		tb.AddAction(gi.ActOpts{Label: "UpdtView", Icon: "update", Tooltip: "update the view to reflect current state of map"},
@@ -346,30 +2055,316 @@ func (mv *MapView) ConfigToolbar() {
 				mvv := recv.Embed(KiT_MapView).(*MapView)
 				mvv.UpdateValues()
 			})
-		tb.AddAction(gi.ActOpts{Label: "Add", Icon: "plus", Tooltip: "add a new element to the map"},
+		tb.AddAction(gi.ActOpts{Label: mv.AddLabelText(), Icon: string(mv.AddIconName()), Tooltip: "add a new element to the map"},
 			mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 				mvv := recv.Embed(KiT_MapView).(*MapView)
 				mvv.MapAdd()
 			})
-		tb.AddAction(gi.ActOpts{Label: "Sort", Icon: "update", Tooltip: "Switch between sorting by the keys vs. the values"},
+		tb.AddAction(gi.ActOpts{Label: "Sort", Icon: "update", Tooltip: "Cycle through sorting by the keys, by the values, or no sorting (user-controlled order with drag-to-reorder handles)"},
 			mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
 				mvv := recv.Embed(KiT_MapView).(*MapView)
 				mvv.ToggleSort()
 			})
+		tb.AddAction(gi.ActOpts{Label: "Undo", Icon: "rotate-left", Tooltip: "undo the most recent map edit (add, delete, or value change)", UpdateFunc: func(act *gi.Action) {
+			act.SetInactiveState(len(mv.UndoStack) == 0)
+		}}, mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.Undo()
+		})
+		tb.AddAction(gi.ActOpts{Label: "Redo", Icon: "rotate-right", Tooltip: "redo the most recently undone map edit", UpdateFunc: func(act *gi.Action) {
+			act.SetInactiveState(len(mv.RedoStack) == 0)
+		}}, mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.Redo()
+		})
+		cpa := tb.AddAction(gi.ActOpts{Label: "Copy", Icon: "copy", Tooltip: "copy the map contents to the clipboard, as JSON or as tab-separated key / value text"},
+			nil, nil)
+		cpa.Menu.AddAction(gi.ActOpts{Label: "Copy JSON"},
+			mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+				mvv := recv.Embed(KiT_MapView).(*MapView)
+				mvv.CopyToClipboard(true)
+			})
+		cpa.Menu.AddAction(gi.ActOpts{Label: "Copy Tab-Separated"},
+			mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+				mvv := recv.Embed(KiT_MapView).(*MapView)
+				mvv.CopyToClipboard(false)
+			})
+		ima := tb.AddAction(gi.ActOpts{Label: "Import", Icon: "file-open", Tooltip: "import JSON from the clipboard into the map, either merging into or replacing the current entries"},
+			nil, nil)
+		ima.Menu.AddAction(gi.ActOpts{Label: "Merge from Clipboard"},
+			mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+				mvv := recv.Embed(KiT_MapView).(*MapView)
+				mvv.ImportFromClipboard(false)
+			})
+		ima.Menu.AddAction(gi.ActOpts{Label: "Replace from Clipboard"},
+			mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+				mvv := recv.Embed(KiT_MapView).(*MapView)
+				mvv.ImportFromClipboard(true)
+			})
 	}
-	ndef := 3 // number of default actions
+	ndef := 7 // number of default actions
 	sz := len(*tb.Children())
 	if sz > ndef {
 		for i := sz - 1; i >= ndef; i-- {
 			tb.DeleteChildAtIndex(i, true)
 		}
 	}
-	if HasToolBarView(mv.Map) {
+	if !kit.IfaceIsNil(mv.Map) && HasToolBarView(mv.Map) {
 		ToolBarView(mv.Map, mv.Viewport, tb)
 	}
 	mv.ToolbarMap = mv.Map
 }
 
+// StdCtxtMenu adds the standard context menu items for the map entry at
+// given row index.  Because map entries are unordered, Insert simply adds
+// a new (zero-key) entry, the same as the toolbar Add action.
+func (mv *MapView) StdCtxtMenu(m *gi.Menu, idx int) {
+	m.AddAction(gi.ActOpts{Label: "Insert", Data: idx},
+		mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.MapAdd()
+		})
+	m.AddAction(gi.ActOpts{Label: "Duplicate", Data: idx},
+		mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.MapDuplicate(data.(int))
+		})
+	m.AddAction(gi.ActOpts{Label: "Copy Key", Data: idx},
+		mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.CopyKeyToClipboard(data.(int))
+		})
+	m.AddAction(gi.ActOpts{Label: "Copy Value", Data: idx},
+		mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.CopyValueToClipboard(data.(int))
+		})
+	m.AddAction(gi.ActOpts{Label: "Delete", Data: idx},
+		mv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+			mvv := recv.Embed(KiT_MapView).(*MapView)
+			mvv.MapDelete(mvv.Keys[data.(int)].Val())
+		})
+}
+
+// ItemCtxtMenu pops up the context menu for the map entry at given row index.
+func (mv *MapView) ItemCtxtMenu(idx int) {
+	if idx < 0 || idx >= len(mv.Keys) {
+		return
+	}
+	var men gi.Menu
+	mv.StdCtxtMenu(&men, idx)
+	if len(men) == 0 {
+		return
+	}
+	sg := mv.MapGrid()
+	ncol := len(sg.Kids) / len(mv.Keys)
+	widg := sg.Child(idx * ncol).(gi.Node2D).AsWidget()
+	pos := widg.ContextMenuPos()
+	gi.PopupMenu(men, pos.X, pos.Y, mv.Viewport, mv.Nm+"-menu")
+}
+
+// RowFromPos returns the map grid row index whose key widget contains the
+// given window Y coordinate -- -1 if not within any row.
+func (mv *MapView) RowFromPos(posY int) int {
+	if len(mv.Keys) == 0 {
+		return -1
+	}
+	sg := mv.MapGrid()
+	ncol := len(sg.Kids) / len(mv.Keys)
+	for i := range mv.Keys {
+		widg := sg.Child(i * ncol).(gi.Node2D).AsWidget()
+		if posY >= widg.WinBBox.Min.Y && posY < widg.WinBBox.Max.Y {
+			return i
+		}
+	}
+	return -1
+}
+
+// UpdateHoverRow updates the mouse-hover highlight to given row (-1 for
+// none): the widgets of that row get a highlighted background color derived
+// from the current style, and any previously hovered row is restored.
+func (mv *MapView) UpdateHoverRow(row int) {
+	if row == mv.HoverRow {
+		return
+	}
+	nk := len(mv.Keys)
+	if nk == 0 {
+		return
+	}
+	sg := mv.MapGrid()
+	ncol := len(sg.Kids) / nk
+	updt := sg.UpdateStart()
+	hic := sg.Sty.Font.BgColor.Color.Highlight(15) // derived from style, as for stripes
+	for _, r := range []int{mv.HoverRow, row} {
+		if r < 0 || r >= nk {
+			continue
+		}
+		for c := 0; c < ncol; c++ {
+			wb := sg.Child(r*ncol + c).(gi.Node2D).AsWidget()
+			if wb == nil {
+				continue
+			}
+			if r == row {
+				wb.SetProp("background-color", hic)
+			} else {
+				wb.DeleteProp("background-color")
+			}
+			wb.SetFullReRender()
+		}
+	}
+	mv.HoverRow = row
+	sg.UpdateEnd(updt)
+}
+
+// FocusRow returns the display row that currently contains the keyboard
+// focus widget, or -1 if focus is not within one of the grid rows.
+func (mv *MapView) FocusRow() int {
+	if mv.Viewport == nil || mv.Viewport.Win == nil {
+		return -1
+	}
+	foc := mv.Viewport.Win.CurFocus()
+	if foc == nil {
+		return -1
+	}
+	nk := len(mv.Keys)
+	if nk == 0 {
+		return -1
+	}
+	sg := mv.MapGrid()
+	ncol := len(sg.Kids) / nk
+	cur := foc
+	for cur != nil { // walk up to the direct child of the grid
+		par := cur.Parent()
+		if par == nil {
+			return -1
+		}
+		if par == sg.This() {
+			idx, ok := cur.IndexInParent()
+			if !ok {
+				return -1
+			}
+			return idx / ncol
+		}
+		cur = par
+	}
+	return -1
+}
+
+// FocusKey moves keyboard focus to the key widget of the row displaying
+// the given key value, e.g., to immediately edit a newly added entry.
+func (mv *MapView) FocusKey(key interface{}) {
+	nk := len(mv.Keys)
+	if nk == 0 {
+		return
+	}
+	sg := mv.MapGrid()
+	ncol := len(sg.Kids) / nk
+	colOff := 0
+	if mv.SortMode == MapSortNone { // first column is the drag handles
+		colOff = 1
+	}
+	for i, kv := range mv.Keys {
+		if kit.NonPtrValue(kv.Val()).Interface() == key {
+			if keyw, ok := sg.Child(i*ncol + colOff).(gi.Node2D); ok {
+				keyw.AsNode2D().GrabFocus()
+			}
+			return
+		}
+	}
+}
+
+// KeyInput handles keyboard shortcuts when focus is within the map view:
+// insert (e.g., Ctrl+I) adds a new entry and focuses its key for editing,
+// and delete removes the currently-focused row, with a confirmation dialog
+// if DeleteConfirm is set.
+func (mv *MapView) KeyInput(kt *key.ChordEvent) {
+	kf := gi.KeyFun(kt.Chord())
+	switch kf {
+	case gi.KeyFunInsert, gi.KeyFunInsertAfter:
+		kt.SetProcessed()
+		mv.MapAdd()
+		var nkey interface{} // zero key of the new entry
+		if mm := mv.Model(); mm != nil {
+			nkey = reflect.New(mm.KeyType()).Elem().Interface()
+		} else if !kit.IfaceIsNil(mv.Map) {
+			nkey = reflect.New(kit.NonPtrType(reflect.TypeOf(mv.Map)).Key()).Elem().Interface()
+		}
+		if nkey != nil {
+			mv.FocusKey(nkey)
+		}
+	case gi.KeyFunDelete:
+		row := mv.FocusRow()
+		if row >= 0 && row < len(mv.Keys) {
+			kt.SetProcessed()
+			mv.MapDeleteAction(mv.Keys[row].Val())
+		}
+	}
+}
+
+// HasFocus2D indicates that the view gets keyboard events whenever any
+// widget within it has focus -- see KeyInput for the shortcuts.
+func (mv *MapView) HasFocus2D() bool {
+	return mv.ContainsFocus()
+}
+
+// MapViewEvents connects the view-level event handling: right mouse click
+// on a grid row pops up the row context menu, mouse movement updates the
+// hovered-row highlight, and in MapSortNone mode, dragging a row handle
+// moves the entry to the row it is released over.
+func (mv *MapView) MapViewEvents() {
+	mv.ConnectEvent(oswin.MouseEvent, gi.LowRawPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.Event)
+		mvv := recv.Embed(KiT_MapView).(*MapView)
+		if me.Button == mouse.Right && me.Action == mouse.Release {
+			idx := mvv.RowFromPos(me.Pos().Y)
+			if idx >= 0 {
+				mvv.ItemCtxtMenu(idx)
+				me.SetProcessed()
+			}
+		}
+		if mvv.SortMode == MapSortNone && me.Button == mouse.Left {
+			switch me.Action {
+			case mouse.Press:
+				mvv.DragRow = mvv.DragHandleRow(me.Pos())
+				if mvv.DragRow >= 0 {
+					me.SetProcessed()
+				}
+			case mouse.Release:
+				if mvv.DragRow >= 0 {
+					mvv.MoveRow(mvv.DragRow, mvv.RowFromPos(me.Pos().Y))
+					mvv.DragRow = -1
+					me.SetProcessed()
+				}
+			}
+		}
+	})
+	mv.ConnectEvent(oswin.MouseDragEvent, gi.LowRawPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.DragEvent)
+		mvv := recv.Embed(KiT_MapView).(*MapView)
+		if mvv.DragRow >= 0 {
+			me.SetProcessed()
+			mvv.UpdateHoverRow(mvv.RowFromPos(me.Pos().Y)) // track the drop target
+		}
+	})
+	mv.ConnectEvent(oswin.MouseMoveEvent, gi.LowRawPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.MoveEvent)
+		mvv := recv.Embed(KiT_MapView).(*MapView)
+		mvv.UpdateHoverRow(mvv.RowFromPos(me.Pos().Y))
+	})
+	mv.ConnectEvent(oswin.MouseFocusEvent, gi.LowRawPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		me := d.(*mouse.FocusEvent)
+		mvv := recv.Embed(KiT_MapView).(*MapView)
+		if me.Action == mouse.Exit {
+			mvv.UpdateHoverRow(-1)
+		}
+	})
+	mv.ConnectEvent(oswin.KeyChordEvent, gi.LowPri, func(recv, send ki.Ki, sig int64, d interface{}) {
+		kt := d.(*key.ChordEvent)
+		mvv := recv.Embed(KiT_MapView).(*MapView)
+		mvv.KeyInput(kt) // low priority, so focused widgets get their keys first
+	})
+}
+
 func (mv *MapView) Style2D() {
 	if mv.Viewport != nil && mv.Viewport.IsDoingFullRender() {
 		mv.Config()
@@ -379,6 +2374,7 @@ func (mv *MapView) Style2D() {
 
 func (mv *MapView) Render2D() {
 	mv.ToolBar().UpdateActions() // nil safe..
+	mv.MapViewEvents()
 	if win := mv.ParentWindow(); win != nil {
 		if !win.IsResizing() {
 			win.MainMenuUpdateActives()
@@ -137,6 +137,18 @@ func ValueViewMapAdd(typeNm string, fun ValueViewFunc) {
 	ValueViewMap[typeNm] = fun
 }
 
+// RegisterValueViewFactory registers a factory that returns a new initialized
+// ValueView editor for values of the given type (e.g., a color, duration, or
+// file-path domain type), keyed directly by reflect.Type -- convenience
+// wrapper around ValueViewMapAdd that computes the long type name for you.
+// The factory is consulted by ToValueView (and thus applies everywhere value
+// editors are created: MapView, MapViewInline, SliceView, StructView, etc),
+// before all of the default Kind-based editors, which remain the fallback
+// for unregistered types.
+func RegisterValueViewFactory(typ reflect.Type, factory ValueViewFunc) {
+	ValueViewMapAdd(kit.LongTypeName(kit.NonPtrType(typ)), factory)
+}
+
 // ToValueView returns the appropriate ValueView for given item, based only on
 // its type -- attempts to get the ValueViewer interface and failing that,
 // falls back on default Kind-based options.  tags are optional tags, e.g.,
@@ -241,9 +253,11 @@ func ToValueView(it interface{}, tags string) ValueView {
 			return vv
 		}
 		if kit.IfaceIsNil(it) {
-			vv := NilValueView{}
-			vv.Init(&vv)
-			return &vv
+			// offer to allocate a new instance so the value can be edited --
+			// writes back through the pointer (e.g., map[string]*Config)
+			vv := &NilPtrValueView{}
+			vv.Init(vv)
+			return vv
 		}
 		v := reflect.ValueOf(it)
 		if !kit.ValueIsZero(v) {
@@ -611,6 +625,32 @@ func (vv *ValueViewBase) Val() reflect.Value {
 	return vv.Value
 }
 
+// MapKeyConflictPolicies are the ways that editing a map key to a value
+// that already exists in the map can be handled -- see MapKeyConflictPolicy.
+type MapKeyConflictPolicies int32
+
+const (
+	// MapKeyConflictAsk prompts with a choice dialog (the default).
+	MapKeyConflictAsk MapKeyConflictPolicies = iota
+
+	// MapKeyConflictRefuse cancels the key edit, leaving the map unchanged.
+	MapKeyConflictRefuse
+
+	// MapKeyConflictOverwrite replaces the existing entry with the renamed one.
+	MapKeyConflictOverwrite
+
+	MapKeyConflictPoliciesN
+)
+
+//go:generate stringer -type=MapKeyConflictPolicies
+
+var KiT_MapKeyConflictPolicies = kit.Enums.AddEnum(MapKeyConflictPoliciesN, kit.NotBitFlag, nil)
+
+// MapKeyConflictPolicy determines how editing a map key to a value that
+// already exists in the map is handled.  Renaming a key always preserves
+// its value: the old entry is deleted and the new key is set to that value.
+var MapKeyConflictPolicy = MapKeyConflictAsk
+
 func (vv *ValueViewBase) SetValue(val interface{}) bool {
 	if vv.This().(ValueView).IsInactive() {
 		return false
@@ -630,42 +670,45 @@ func (vv *ValueViewBase) SetValue(val interface{}) bool {
 			if vv.IsMapKey {
 				nv := kit.NonPtrValue(reflect.ValueOf(val)) // new key value
 				kv := kit.NonPtrValue(vv.Value)
-				cv := ov.MapIndex(kv)    // get current value
 				curnv := ov.MapIndex(nv) // see if new value there already
 				if val != kv.Interface() && !kit.ValueIsZero(curnv) {
-					var vp *gi.Viewport2D
-					if vv.Widget != nil {
-						widg := vv.Widget.AsNode2D()
-						vp = widg.Viewport
-					}
-					// actually new key and current exists
-					gi.ChoiceDialog(vp,
-						gi.DlgOpts{Title: "Map Key Conflict", Prompt: fmt.Sprintf("The map key value: %v already exists in the map -- are you sure you want to overwrite the current value?", val)},
-						[]string{"Cancel Change", "Overwrite"},
-						vv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
-							switch sig {
-							case 0:
-								if vp != nil {
-									vp.SetNeedsFullRender()
-								}
-							case 1:
-								cv := ov.MapIndex(kv)               // get current value
-								ov.SetMapIndex(kv, reflect.Value{}) // delete old key
-								ov.SetMapIndex(nv, cv)              // set new key to current value
-								vv.Value = nv                       // update value to new key
-								vv.This().(ValueView).SaveTmp()
-								vv.ViewSig.Emit(vv.This(), 0, nil)
-								if vp != nil {
-									vp.SetNeedsFullRender()
+					// actually new key and an entry for it already exists
+					switch MapKeyConflictPolicy {
+					case MapKeyConflictRefuse:
+						return false
+					case MapKeyConflictOverwrite:
+						vv.RenameMapKey(ov, kv, nv)
+						rval = true
+					default: // MapKeyConflictAsk
+						var vp *gi.Viewport2D
+						if vv.Widget != nil {
+							widg := vv.Widget.AsNode2D()
+							vp = widg.Viewport
+						}
+						gi.ChoiceDialog(vp,
+							gi.DlgOpts{Title: "Map Key Conflict", Prompt: fmt.Sprintf("The map key value: %v already exists in the map -- are you sure you want to overwrite the current value?", val)},
+							[]string{"Cancel Change", "Overwrite"},
+							vv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+								switch sig {
+								case 0:
+									if vp != nil {
+										vp.SetNeedsFullRender()
+									}
+								case 1:
+									vv.RenameMapKey(ov, kv, nv)
+									vv.This().(ValueView).SaveTmp()
+									vv.ViewSig.Emit(vv.This(), 0, nil)
+									if vp != nil {
+										vp.SetNeedsFullRender()
+									}
 								}
-							}
-						})
-					return false // abort this action right now
+							})
+						return false // deferred to the dialog callback
+					}
+				} else {
+					vv.RenameMapKey(ov, kv, nv)
+					rval = true
 				}
-				ov.SetMapIndex(kv, reflect.Value{}) // delete old key
-				ov.SetMapIndex(nv, cv)              // set new key to current value
-				vv.Value = nv                       // update value to new key
-				rval = true
 			} else {
 				vv.Value = kit.NonPtrValue(reflect.ValueOf(val))
 				if vv.KeyView != nil {
@@ -694,6 +737,20 @@ func (vv *ValueViewBase) SetValue(val interface{}) bool {
 	return rval
 }
 
+// RenameMapKey renames map key kv to nv in given map value, preserving the
+// value stored under the old key: the old entry is deleted and the new key
+// is set to that value, and our Value is updated to the new key.
+// This is a nop if the keys are equal.
+func (vv *ValueViewBase) RenameMapKey(ov, kv, nv reflect.Value) {
+	if kv.Interface() == nv.Interface() {
+		return
+	}
+	cv := ov.MapIndex(kv)               // get current value
+	ov.SetMapIndex(kv, reflect.Value{}) // delete old key
+	ov.SetMapIndex(nv, cv)              // set new key to current value
+	vv.Value = nv                       // update value to new key
+}
+
 func (vv *ValueViewBase) SaveTmp() {
 	if vv.TmpSave == nil {
 		return
@@ -848,6 +905,10 @@ func (vv *ValueViewBase) Label() (label, newPath string, isZero bool) {
 //   Base Widget Functions -- these are typically redefined in ValueView subtypes
 
 func (vv *ValueViewBase) WidgetType() reflect.Type {
+	if _, ok := vv.Tag("enum"); ok { // fixed set of allowed values -- chooser
+		vv.WidgetTyp = gi.KiT_ComboBox
+		return vv.WidgetTyp
+	}
 	vv.WidgetTyp = gi.KiT_TextField
 	return vv.WidgetTyp
 }
@@ -856,6 +917,10 @@ func (vv *ValueViewBase) UpdateWidget() {
 	if vv.Widget == nil {
 		return
 	}
+	if cb, ok := vv.Widget.(*gi.ComboBox); ok { // enum tag chooser
+		cb.SetCurVal(kit.ToString(vv.Value.Interface()))
+		return
+	}
 	tf := vv.Widget.(*gi.TextField)
 	npv := kit.NonPtrValue(vv.Value)
 	// fmt.Printf("vvb val: %v  type: %v  kind: %v\n", npv.Interface(), npv.Type().String(), npv.Kind())
@@ -870,6 +935,10 @@ func (vv *ValueViewBase) UpdateWidget() {
 func (vv *ValueViewBase) ConfigWidget(widg gi.Node2D) {
 	vv.Widget = widg
 	vv.StdConfigWidget(widg)
+	if enumtag, ok := vv.Tag("enum"); ok {
+		vv.ConfigEnumWidget(enumtag)
+		return
+	}
 	tf := vv.Widget.(*gi.TextField)
 	tf.SetStretchMaxWidth()
 	tf.Tooltip, _ = vv.Tag("desc")
@@ -899,6 +968,28 @@ func (vv *ValueViewBase) ConfigWidget(widg gi.Node2D) {
 	vv.UpdateWidget()
 }
 
+// ConfigEnumWidget configures a ComboBox for a value with an enum tag: a
+// comma-separated list of the allowed values for this value -- the chooser
+// only offers those values, so invalid ones cannot be entered.  See WidgetType.
+func (vv *ValueViewBase) ConfigEnumWidget(enumtag string) {
+	cb := vv.Widget.(*gi.ComboBox)
+	cb.Tooltip, _ = vv.Tag("desc")
+	cb.SetInactiveState(vv.This().(ValueView).IsInactive())
+	el := strings.Split(enumtag, ",")
+	for i := range el {
+		el[i] = strings.TrimSpace(el[i])
+	}
+	cb.ItemsFromStringList(el, false, 50)
+	cb.ComboSig.ConnectOnly(vv.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		vvv, _ := recv.Embed(KiT_ValueViewBase).(*ValueViewBase)
+		cbb := send.(*gi.ComboBox)
+		if vvv.SetValue(cbb.CurVal) {
+			vvv.UpdateWidget()
+		}
+	})
+	vv.UpdateWidget()
+}
+
 // StdConfigWidget does all of the standard widget configuration tag options
 func (vv *ValueViewBase) StdConfigWidget(widg gi.Node2D) {
 	nb := widg.AsNode2D()
@@ -52,10 +52,17 @@ var theApp = &appImpl{
 }
 
 type appImpl struct {
-	mu            sync.Mutex
-	mainQueue     chan funcRun
-	mainDone      chan struct{}
-	shareWin      *glfw.Window // a non-visible, always-present window that all windows share gl context with
+	mu        sync.Mutex
+	mainQueue chan funcRun
+	mainDone  chan struct{}
+	// shareWin is a non-visible, always-present window that all other
+	// windows share their gl context with, so that container-independent
+	// GPU objects (textures, vertex / index buffers, shader programs) are
+	// usable from any window.  Container objects (VAOs, framebuffers,
+	// transform feedback, queries) are NOT shared by OpenGL and must be
+	// created per-window (see windowImpl.drawQuads / fillQuads), with the
+	// relevant window's context Activate()'d, on the main thread.
+	shareWin      *glfw.Window
 	windows       map[*glfw.Window]*windowImpl
 	oswindows     map[uintptr]*windowImpl
 	winlist       []*windowImpl
@@ -140,6 +147,7 @@ outer:
 			}
 		default:
 			glfw.PollEvents()
+			app.pollGamepads()
 			break outer
 		}
 	}
@@ -173,6 +181,7 @@ func (app *appImpl) mainLoop() {
 			} else {
 				// glfw.WaitEvents()
 				glfw.WaitEventsTimeout(0.2) // timeout is essential to prevent hanging (on mac at least)
+				app.pollGamepads()
 			}
 		}
 	}
@@ -209,6 +218,7 @@ func (app *appImpl) initGl() {
 	}
 	glfw.DetachCurrentContext()
 	app.getScreens()
+	initGamepads()
 }
 
 ////////////////////////////////////////////////////////
@@ -278,12 +288,12 @@ func (app *appImpl) NewWindow(opts *oswin.NewWindowOptions) (oswin.Window, error
 	glw.SetSizeCallback(w.winResized)
 	glw.SetFramebufferSizeCallback(w.fbResized)
 	glw.SetCloseCallback(w.closeReq)
-	// glw.SetRefreshCallback(w.refresh)
+	glw.SetRefreshCallback(w.refresh)
 	glw.SetFocusCallback(w.focus)
 	glw.SetIconifyCallback(w.iconify)
 
 	glw.SetKeyCallback(w.keyEvent)
-	glw.SetCharModsCallback(w.charEvent)
+	glw.SetCharCallback(w.charEvent) // composed text input, incl. dead keys / IME
 	glw.SetMouseButtonCallback(w.mouseButtonEvent)
 	glw.SetScrollCallback(w.scrollEvent)
 	glw.SetCursorPosCallback(w.cursorPosEvent)
@@ -141,8 +141,11 @@ func (app *appImpl) fillQuadsBuff() gpu.BufferMgr {
 // proper context must have already been established outside this call!
 // dstBotZero is true if destination has Y=0 at bottom
 func (app *appImpl) draw(dstSz image.Point, src2dst mat32.Mat3, src oswin.Texture, sr image.Rectangle, op draw.Op, opts *oswin.DrawOptions, qbuff gpu.BufferMgr, dstBotZero bool) {
-	tx := src.(*textureImpl)
-	sr = sr.Intersect(tx.Bounds())
+	tx, uvoff := srcTexture(src)
+	if tx == nil {
+		return
+	}
+	sr = sr.Intersect(src.Bounds())
 	if sr.Empty() {
 		return
 	}
@@ -196,12 +199,14 @@ func (app *appImpl) draw(dstSz image.Point, src2dst mat32.Mat3, src oswin.Textur
 	//
 	// The PQRS quad is always axis-aligned. First of all, convert
 	// from pixel space to texture space.
+	// uvoff translates region-relative coordinates into the parent texture
+	// for textureRegion sources (zero for a plain texture).
 	tw := float32(tx.size.X)
 	th := float32(tx.size.Y)
-	px := float32(sr.Min.X-0) / tw
-	py := float32(sr.Min.Y-0) / th
-	qx := float32(sr.Max.X-0) / tw
-	sy := float32(sr.Max.Y-0) / th
+	px := float32(sr.Min.X+uvoff.X) / tw
+	py := float32(sr.Min.Y+uvoff.Y) / th
+	qx := float32(sr.Max.X+uvoff.X) / tw
+	sy := float32(sr.Max.Y+uvoff.Y) / th
 	// Due to axis alignment, qy = py and sx = px.
 	//
 	// The simultaneous equations are:
@@ -51,6 +51,7 @@ func (w *windowImpl) keyEvent(gw *glfw.Window, ky glfw.Key, scancode int, action
 	} else if action == glfw.Repeat {
 		act = key.Press
 	}
+	w.setKeyState(ec, act == key.Press) // polled state -- see KeyState
 
 	fw := theApp.WindowInFocus()
 	if w != fw {
@@ -92,15 +93,22 @@ func (w *windowImpl) keyEvent(gw *glfw.Window, ky glfw.Key, scancode int, action
 	}
 }
 
-// char input
-func (w *windowImpl) charEvent(gw *glfw.Window, char rune, mods glfw.ModifierKey) {
-	em := glfwMods(mods)
+// composed text input: the char callback delivers fully composed unicode
+// characters in layout order, after any dead-key sequences and IME
+// composition (e.g., for CJK input) -- this is the authoritative source of
+// text for entry widgets, as opposed to the physical key events above, which
+// drive command chords.  note: glfw has no preedit (in-progress composition)
+// API in the upstream 3.3 bindings, so only the committed text is available;
+// preedit display would require the IME fork of glfw.
+func (w *windowImpl) charEvent(gw *glfw.Window, char rune) {
+	em := lastMods // char callback does not supply mods
 	act := key.Press
 	che := &key.ChordEvent{
 		Event: key.Event{
 			Rune:      char,
 			Modifiers: em,
 			Action:    act,
+			Composed:  true,
 		},
 	}
 	fw := theApp.WindowInFocus()
@@ -144,6 +152,7 @@ func (w *windowImpl) mouseButtonEvent(gw *glfw.Window, button glfw.MouseButton,
 	}
 	lastMouseButton = but
 	lastMouseAction = act
+	w.setMouseState(but, act != mouse.Release) // polled state -- see MouseButtonState
 	event := &mouse.Event{
 		Where:     lastMousePos,
 		Button:    but,
@@ -154,6 +163,18 @@ func (w *windowImpl) mouseButtonEvent(gw *glfw.Window, button glfw.MouseButton,
 	if act == mouse.Press {
 		lastMouseClickTime = event.Time()
 	}
+	if but == mouse.Left && w.IsFrameless() && !w.DragRegion.Empty() {
+		switch act {
+		case mouse.Press:
+			if lastMousePos.In(w.DragRegion) {
+				cx, cy := gw.GetCursorPos()
+				w.winDragging = true
+				w.winDragOff = image.Point{int(cx), int(cy)}
+			}
+		case mouse.Release:
+			w.winDragging = false
+		}
+	}
 	w.Send(event)
 	glfw.PostEmptyEvent()
 }
@@ -170,8 +191,10 @@ func (w *windowImpl) scrollEvent(gw *glfw.Window, xoff, yoff float64) {
 			Action:    mouse.Scroll,
 			Modifiers: mods,
 		},
-		Delta: image.Point{int(-xoff), int(-yoff)},
 	}
+	// preserve both axes and fractional (trackpad) magnitudes -- Delta is
+	// rounded, DeltaPrec keeps full precision
+	event.SetDeltas(-xoff, -yoff)
 	event.Init()
 	w.Send(event)
 	glfw.PostEmptyEvent()
@@ -181,6 +204,13 @@ func (w *windowImpl) cursorPosEvent(gw *glfw.Window, x, y float64) {
 	if w.resettingPos {
 		return
 	}
+	if w.winDragging {
+		// move the whole window by the cursor delta -- afterwards the cursor
+		// is back at the same window-relative offset, so this is stable
+		wx, wy := gw.GetPos()
+		gw.SetPos(wx+int(x)-w.winDragOff.X, wy+int(y)-w.winDragOff.Y)
+		return
+	}
 	var where image.Point
 	if theApp.Platform() == oswin.MacOS {
 		where = image.Point{int(w.DevPixRatio * float32(x)), int(w.DevPixRatio * float32(y))}
@@ -0,0 +1,110 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glos
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/goki/gi/oswin/gamepad"
+)
+
+// gamepadState is the last-polled state for one glfw joystick,
+// used to detect changes and device connect / disconnect.
+type gamepadState struct {
+	present bool
+	axes    []float32
+	buttons []bool
+}
+
+var gamepads [glfw.JoystickLast + 1]gamepadState
+
+// initGamepads sets the joystick connect / disconnect callback and sends
+// Connect events for any gamepads already present at startup.
+// Must be called on main thread after glfw is initialized.
+func initGamepads() {
+	glfw.SetJoystickCallback(joystickChange)
+	for jid := glfw.Joystick1; jid <= glfw.JoystickLast; jid++ {
+		if jid.Present() {
+			gamepads[jid].present = true
+			sendGamepadEvent(jid, gamepad.Connect, nil, nil)
+		}
+	}
+}
+
+// joystickChange is the glfw joystick connect / disconnect callback.
+func joystickChange(joy glfw.Joystick, event glfw.PeripheralEvent) {
+	gs := &gamepads[joy]
+	switch event {
+	case glfw.Connected:
+		gs.present = true
+		gs.axes = nil
+		gs.buttons = nil
+		sendGamepadEvent(joy, gamepad.Connect, nil, nil)
+	case glfw.Disconnected:
+		gs.present = false
+		sendGamepadEvent(joy, gamepad.Disconnect, nil, nil)
+	}
+}
+
+// pollGamepads polls the axis / button state of all connected gamepads,
+// sending an Update event for any that have changed since last poll.
+// Called on main thread each pass through the main event loop.
+func (app *appImpl) pollGamepads() {
+	for jid := glfw.Joystick1; jid <= glfw.JoystickLast; jid++ {
+		gs := &gamepads[jid]
+		if !gs.present {
+			continue
+		}
+		axes := jid.GetAxes()
+		glbtns := jid.GetButtons()
+		btns := make([]bool, len(glbtns))
+		for i, b := range glbtns {
+			btns[i] = b == glfw.Press
+		}
+		if gamepadStateEqual(gs, axes, btns) {
+			continue
+		}
+		gs.axes = axes
+		gs.buttons = btns
+		sendGamepadEvent(jid, gamepad.Update, axes, btns)
+	}
+}
+
+// gamepadStateEqual returns true if given axis / button state is identical
+// to the last-polled state.
+func gamepadStateEqual(gs *gamepadState, axes []float32, btns []bool) bool {
+	if len(gs.axes) != len(axes) || len(gs.buttons) != len(btns) {
+		return false
+	}
+	for i, ax := range axes {
+		if gs.axes[i] != ax {
+			return false
+		}
+	}
+	for i, b := range btns {
+		if gs.buttons[i] != b {
+			return false
+		}
+	}
+	return true
+}
+
+// sendGamepadEvent sends a gamepad event to the window in focus, if any.
+func sendGamepadEvent(joy glfw.Joystick, act gamepad.Actions, axes []float32, btns []bool) {
+	win := theApp.WindowInFocus()
+	if win == nil {
+		return
+	}
+	ev := &gamepad.Event{
+		ID:      int(joy),
+		Action:  act,
+		Axes:    axes,
+		Buttons: btns,
+	}
+	if gamepads[joy].present {
+		ev.Name = joy.GetName()
+	}
+	ev.Init()
+	win.Send(ev)
+}
@@ -33,11 +33,12 @@ var glTypes = map[gpu.Types]uint32{
 type gpuImpl struct {
 	// mu is in case we need a cpu-wide mutex -- mostly it is the
 	// window-specific glctxtMu that is used
-	mu        sync.Mutex
-	bindPoint int
-	debug     bool
-	dbCbSet   bool  // is the callback debug set?
-	lastErr   error // last error from callback
+	mu         sync.Mutex
+	bindPoint  int
+	debug      bool
+	dbCbSet    bool  // is the callback debug set?
+	lastErr    error // last error from callback
+	maxTexSize int   // max texture size (GL_MAX_TEXTURE_SIZE), queried in Init
 }
 
 var theGPU = &gpuImpl{}
@@ -53,22 +54,39 @@ func (gp *gpuImpl) Init(debug bool) error {
 		return err
 	}
 	gpu.TheGPU = theGPU
+	var mts int32
+	gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &mts)
+	gp.maxTexSize = int(mts)
 	gp.debug = debug
 	if debug {
 		version := gl.GoStr(gl.GetString(gl.VERSION))
 		fmt.Println("OpenGL version", version)
 		// Query the extensions to determine if we can enable the debug callback
+		// KHR_debug (core in GL 4.3+) is preferred, ARB_debug_output is the fallback
+		hasKHR := false
+		hasARB := false
 		var numExtensions int32
 		gl.GetIntegerv(gl.NUM_EXTENSIONS, &numExtensions)
 		for i := int32(0); i < numExtensions; i++ {
 			extension := gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i)))
 			// fmt.Println(extension)
-			if extension == "GL_ARB_debug_output" {
-				gp.dbCbSet = true
-				gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS_ARB)
-				gl.DebugMessageCallbackARB(gl.DebugProc(gp.DebugMsg), gl.Ptr(nil))
+			switch extension {
+			case "GL_KHR_debug":
+				hasKHR = true
+			case "GL_ARB_debug_output":
+				hasARB = true
 			}
 		}
+		if hasKHR {
+			gp.dbCbSet = true
+			gl.Enable(gl.DEBUG_OUTPUT)
+			gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS)
+			gl.DebugMessageCallback(gl.DebugProc(gp.DebugMsg), gl.Ptr(nil))
+		} else if hasARB {
+			gp.dbCbSet = true
+			gl.Enable(gl.DEBUG_OUTPUT_SYNCHRONOUS_ARB)
+			gl.DebugMessageCallbackARB(gl.DebugProc(gp.DebugMsg), gl.Ptr(nil))
+		}
 	}
 	return nil
 }
@@ -124,6 +142,17 @@ func (gp *gpuImpl) Type(typ gpu.Types) uint32 {
 	return glTypes[typ]
 }
 
+// MaxTextureSize returns the maximum size (width or height, in pixels)
+// of a 2D texture supported by this GPU (GL_MAX_TEXTURE_SIZE).
+func (gp *gpuImpl) MaxTextureSize() int {
+	if gp.maxTexSize == 0 { // not yet queried -- requires an active context
+		var mts int32
+		gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &mts)
+		gp.maxTexSize = int(mts)
+	}
+	return gp.maxTexSize
+}
+
 // NewProgram returns a new Program with given name -- for standalone programs.
 // See also NewPipeline.
 func (gp *gpuImpl) NewProgram(name string) gpu.Program {
@@ -185,7 +214,8 @@ func (gp *gpuImpl) NewUniforms(name string) gpu.Uniforms {
 	return us
 }
 
-// 	NextUniformBindingPoint returns the next avail uniform binding point.
+//	NextUniformBindingPoint returns the next avail uniform binding point.
+//
 // Counts up from 0 -- this call increments for next call.
 func (gp *gpuImpl) NextUniformBindingPoint() int {
 	bp := gp.bindPoint
@@ -196,6 +226,10 @@ func (gp *gpuImpl) NextUniformBindingPoint() int {
 //////////////////////////////////////////////////////////////
 // Debugging
 
+// DebugMsg is the callback registered with KHR_debug / ARB_debug_output
+// that logs all debug messages from the GL driver, decoding the source,
+// type, and severity enums -- notification-level messages are filtered
+// out to avoid spamming the log.
 func (gp *gpuImpl) DebugMsg(
 	source uint32,
 	gltype uint32,
@@ -204,11 +238,51 @@ func (gp *gpuImpl) DebugMsg(
 	length int32,
 	message string,
 	userParam unsafe.Pointer) {
-	gp.lastErr = fmt.Errorf("glos gl error msg: %v source: %v gltype %v id %v severity %v length %v",
-		message, source, gltype, id, severity, length)
+	if severity == gl.DEBUG_SEVERITY_NOTIFICATION {
+		return
+	}
+	gp.lastErr = fmt.Errorf("glos gl debug msg: %v source: %v type: %v severity: %v id: %v",
+		message, glDebugEnumString(source, glDebugSources), glDebugEnumString(gltype, glDebugTypes), glDebugEnumString(severity, glDebugSeverities), id)
 	log.Println(gp.lastErr)
 }
 
+// glDebugEnumString returns the string for given debug enum from map,
+// or the hex value if not found.
+func glDebugEnumString(enum uint32, m map[uint32]string) string {
+	if s, ok := m[enum]; ok {
+		return s
+	}
+	return fmt.Sprintf("0x%x", enum)
+}
+
+var glDebugSources = map[uint32]string{
+	gl.DEBUG_SOURCE_API:             "API",
+	gl.DEBUG_SOURCE_WINDOW_SYSTEM:   "WindowSystem",
+	gl.DEBUG_SOURCE_SHADER_COMPILER: "ShaderCompiler",
+	gl.DEBUG_SOURCE_THIRD_PARTY:     "ThirdParty",
+	gl.DEBUG_SOURCE_APPLICATION:     "Application",
+	gl.DEBUG_SOURCE_OTHER:           "Other",
+}
+
+var glDebugTypes = map[uint32]string{
+	gl.DEBUG_TYPE_ERROR:               "Error",
+	gl.DEBUG_TYPE_DEPRECATED_BEHAVIOR: "DeprecatedBehavior",
+	gl.DEBUG_TYPE_UNDEFINED_BEHAVIOR:  "UndefinedBehavior",
+	gl.DEBUG_TYPE_PORTABILITY:         "Portability",
+	gl.DEBUG_TYPE_PERFORMANCE:         "Performance",
+	gl.DEBUG_TYPE_MARKER:              "Marker",
+	gl.DEBUG_TYPE_PUSH_GROUP:          "PushGroup",
+	gl.DEBUG_TYPE_POP_GROUP:           "PopGroup",
+	gl.DEBUG_TYPE_OTHER:               "Other",
+}
+
+var glDebugSeverities = map[uint32]string{
+	gl.DEBUG_SEVERITY_HIGH:         "High",
+	gl.DEBUG_SEVERITY_MEDIUM:       "Medium",
+	gl.DEBUG_SEVERITY_LOW:          "Low",
+	gl.DEBUG_SEVERITY_NOTIFICATION: "Notification",
+}
+
 var glErrStrings = map[uint32]string{
 	gl.INVALID_ENUM:                  "INVALID_ENUM: Given when an enumeration parameter is not a legal enumeration for that function. This is given only for local problems; if the spec allows the enumeration in certain circumstances, where other parameters or state dictate those circumstances, then GL_INVALID_OPERATION is the result instead.",
 	gl.INVALID_VALUE:                 "INVALID_VALUE: Given when a value parameter is not a legal value for that function. This is only given for local problems; if the spec allows the value in certain circumstances, where other parameters or state dictate those circumstances, then GL_INVALID_OPERATION is the result instead.",
@@ -87,6 +87,12 @@ func (app *appImpl) getScreens() {
 		sc.Name = mon.GetName()
 		sc.ScreenNumber = sci
 		sc.Geometry = image.Rectangle{Min: image.Point{x, y}, Max: image.Point{x + vm.Width, y + vm.Height}}
+		wx, wy, ww, wh := mon.GetWorkarea() // excludes taskbars, docks, menu bars
+		if ww > 0 && wh > 0 {
+			sc.WorkArea = image.Rectangle{Min: image.Point{wx, wy}, Max: image.Point{wx + ww, wy + wh}}
+		} else {
+			sc.WorkArea = sc.Geometry
+		}
 		sc.DevicePixelRatio = cscx
 		sc.PixSize.X = int(float32(vm.Width) * cscx)
 		sc.PixSize.Y = int(float32(vm.Height) * cscx)
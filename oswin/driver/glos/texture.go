@@ -15,13 +15,23 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/go-gl/gl/v3.3-core/gl"
 	"github.com/goki/gi/mat32"
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/oswin/driver/internal/drawer"
 	"github.com/goki/gi/oswin/gpu"
+	"github.com/goki/ki/ints"
+	_ "golang.org/x/image/bmp"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff"
 )
 
 // note: use a different interface for different formats of "textures" such as a
@@ -36,6 +46,7 @@ type textureImpl struct {
 	name      string
 	size      image.Point
 	botZero   bool
+	srgb      bool
 	img       *image.RGBA // when loaded
 	imgTmp    *image.RGBA // for grab, prior to flip
 	fbuff     gpu.Framebuffer
@@ -59,15 +70,24 @@ func (tx *textureImpl) SetName(name string) {
 }
 
 // Open loads texture image from file.
-// format inferred from filename -- JPEG and PNG
-// supported by default.
+// Format inferred from the file contents -- JPEG, PNG, GIF, TIFF,
+// and BMP are supported by default (for GIF, the first frame is used --
+// see gi3d.OpenGIFFrame to get a specific frame).
 func (tx *textureImpl) Open(path string) error {
 	file, err := os.Open(path)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	im, _, err := image.Decode(file)
+	return tx.OpenReader(file, filepath.Ext(path))
+}
+
+// OpenReader loads texture image from the given reader, e.g., from an
+// embedded filesystem, zip archive, or network stream.  The format
+// argument is not needed here, as the format is inferred from the
+// image data itself -- same formats as Open are supported.
+func (tx *textureImpl) OpenReader(r io.Reader, format string) error {
+	im, _, err := image.Decode(r)
 	if err != nil {
 		return err
 	}
@@ -177,7 +197,35 @@ func (tx *textureImpl) SetSubImage(dp image.Point, src image.Image, sr image.Rec
 	if err != nil {
 		return err
 	}
+	tx.Activate(0)
+	tx.setSubImage(dp, rgba, sr)
+	return nil
+}
+
+// SetSubImages uploads the given list of dirty sub-rectangles of src to the
+// texture, each translated by dp -- the efficient path for streaming content
+// where only a few scanlines / regions change each frame: src is converted
+// and the texture activated once, and each rectangle uploads in a single
+// TexSubImage2D call (see setSubImage).
+// Must be called with a valid gpu context and on proper thread for that context.
+func (tx *textureImpl) SetSubImages(dp image.Point, src image.Image, srs []image.Rectangle) error {
+	rgba, err := rgbaImage(src)
+	if err != nil {
+		return err
+	}
+	tx.Activate(0)
+	for _, sr := range srs {
+		tx.setSubImage(sr.Min.Add(dp), rgba, sr)
+	}
+	return nil
+}
 
+// setSubImage uploads the sub-image defined by rgba and sr, such that sr.Min
+// in src-space aligns with dp in dst-space -- texture must already be
+// Activate'd.  When the rectangle is narrower than the source stride, the
+// GL unpack row length is set so the upload still happens in one call
+// instead of row-by-row.
+func (tx *textureImpl) setSubImage(dp image.Point, rgba *image.RGBA, sr image.Rectangle) {
 	// todo: if needed for windows, do this here:
 	// buf := src.(*imageImpl)
 	// buf.preUpload()
@@ -193,26 +241,30 @@ func (tx *textureImpl) SetSubImage(dp image.Point, src image.Image, sr image.Rec
 	dr := sr.Add(src2dst)
 	dr = dr.Intersect(tx.Bounds())
 	if dr.Empty() {
-		return nil
+		return
 	}
 
 	// Bring dr.Min in dst-space back to src-space to get the pixel image offset.
 	pix := rgba.Pix[rgba.PixOffset(dr.Min.X-src2dst.X, dr.Min.Y-src2dst.Y):]
 
-	tx.Activate(0)
-
 	width := dr.Dx()
 	if width*4 == rgba.Stride {
 		gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(dr.Min.X), int32(dr.Min.Y), int32(width), int32(dr.Dy()), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
-		return nil
+		return
+	}
+	if rgba.Stride%4 == 0 {
+		// tell the GL the source row length so the whole region uploads in
+		// one call despite the non-matching stride
+		gl.PixelStorei(gl.UNPACK_ROW_LENGTH, int32(rgba.Stride/4))
+		gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(dr.Min.X), int32(dr.Min.Y), int32(width), int32(dr.Dy()), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix))
+		gl.PixelStorei(gl.UNPACK_ROW_LENGTH, 0)
+		return
 	}
-	// TODO: can we use GL_UNPACK_ROW_LENGTH with glPixelStorei for stride in
-	// ES 3.0, instead of uploading the pixels row-by-row?
+	// fallback for strides that are not a whole number of pixels
 	for y, p := dr.Min.Y, 0; y < dr.Max.Y; y++ {
 		gl.TexSubImage2D(gl.TEXTURE_2D, 0, int32(dr.Min.X), int32(y), int32(width), 1, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pix[p:]))
 		p += rgba.Stride
 	}
-	return nil
 }
 
 // Size returns the size of the image
@@ -220,6 +272,12 @@ func (tx *textureImpl) Size() image.Point {
 	return tx.size
 }
 
+// Region returns a lightweight view onto the given sub-rectangle of this
+// texture, sharing the same GPU handle -- see oswin.Texture interface.
+func (tx *textureImpl) Region(r image.Rectangle) oswin.Texture {
+	return &textureRegion{textureImpl: tx, region: r.Intersect(tx.Bounds())}
+}
+
 func (tx *textureImpl) Bounds() image.Rectangle {
 	if tx == nil {
 		return image.ZR
@@ -241,6 +299,37 @@ func (tx *textureImpl) SetBotZero(botzero bool) {
 	tx.botZero = botzero
 }
 
+// SRGB returns true if this texture is uploaded to the GPU in the sRGB
+// color space, so the GPU linearizes the color values when sampling.
+func (tx *textureImpl) SRGB() bool {
+	return tx.srgb
+}
+
+// SetSRGB sets whether this texture is uploaded to the GPU in the sRGB
+// color space (GL_SRGB8_ALPHA8 internal format).  Use for color images
+// (diffuse / albedo maps) -- data textures such as normal, roughness or
+// metallic maps must remain linear (off, the default).  If the texture is
+// already on the GPU, the image is re-uploaded with the new internal format.
+// Must be called with a valid gpu context and on proper thread for that context.
+func (tx *textureImpl) SetSRGB(srgb bool) {
+	if tx.srgb == srgb {
+		return
+	}
+	tx.srgb = srgb
+	if tx.init && tx.img != nil {
+		tx.Transfer(0) // re-upload with new internal format
+	}
+}
+
+// internalFormat returns the GL internal format for uploading this texture:
+// sRGB if set (GPU linearizes on sample), plain RGBA otherwise.
+func (tx *textureImpl) internalFormat() int32 {
+	if tx.srgb {
+		return gl.SRGB8_ALPHA8
+	}
+	return gl.RGBA
+}
+
 // SetSize sets the size of the texture.
 // If texture has been Activate'd, then this resizes the GPU side as well.
 // If Activate()'d, then must be called with a valid gpu context
@@ -260,6 +349,18 @@ func (tx *textureImpl) SetSize(size image.Point) {
 	}
 }
 
+// downscaleToMax returns given image scaled down proportionally so that
+// neither dimension exceeds mts (GPU max texture size), using bilinear
+// interpolation.
+func downscaleToMax(img *image.RGBA, mts int) *image.RGBA {
+	sz := img.Rect.Size()
+	sc := float32(mts) / float32(ints.MaxInt(sz.X, sz.Y))
+	nsz := image.Point{int(float32(sz.X) * sc), int(float32(sz.Y) * sc)}
+	dst := image.NewRGBA(image.Rectangle{Max: nsz})
+	xdraw.BiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+	return dst
+}
+
 // Activate establishes the GPU resources and handle for the
 // texture, using the given texture number (0-31 range).
 // If an image has already been set for this texture, then it is
@@ -268,6 +369,16 @@ func (tx *textureImpl) SetSize(size image.Point) {
 // Must be called with a valid gpu context and on proper thread for that context.
 func (tx *textureImpl) Activate(texNo int) {
 	if !tx.init {
+		if mts := theGPU.MaxTextureSize(); mts > 0 && (tx.size.X > mts || tx.size.Y > mts) {
+			log.Printf("glos.Texture: %v size: %v exceeds GPU max texture size: %d -- downscaling to fit\n", tx.name, tx.size, mts)
+			if tx.img != nil {
+				tx.img = downscaleToMax(tx.img, mts)
+				tx.size = tx.img.Rect.Size()
+			} else {
+				tx.size.X = ints.MinInt(tx.size.X, mts)
+				tx.size.Y = ints.MinInt(tx.size.Y, mts)
+			}
+		}
 		gl.GenTextures(1, &tx.handle)
 		gl.ActiveTexture(gl.TEXTURE0 + uint32(texNo))
 		gl.BindTexture(gl.TEXTURE_2D, tx.handle)
@@ -278,9 +389,9 @@ func (tx *textureImpl) Activate(texNo int) {
 		szx := int32(tx.size.X)
 		szy := int32(tx.size.Y)
 		if tx.img != nil {
-			gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, szx, szy, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(tx.img.Pix))
+			gl.TexImage2D(gl.TEXTURE_2D, 0, tx.internalFormat(), szx, szy, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(tx.img.Pix))
 		} else {
-			gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, szx, szy, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(nil))
+			gl.TexImage2D(gl.TEXTURE_2D, 0, tx.internalFormat(), szx, szy, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(nil))
 		}
 		tx.init = true
 	} else {
@@ -317,7 +428,7 @@ func (tx *textureImpl) Transfer(texNo int) bool {
 	szx := int32(tx.size.X)
 	szy := int32(tx.size.Y)
 	// note: TexImage2D automatically flips Y axis on way up to texture
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, szx, szy, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(tx.img.Pix))
+	gl.TexImage2D(gl.TEXTURE_2D, 0, tx.internalFormat(), szx, szy, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(tx.img.Pix))
 	return true
 }
 
@@ -392,6 +503,71 @@ func (tx *textureImpl) FrameDepthAt(x, y int) (float32, error) {
 	return depth, nil
 }
 
+// ReadRegion reads back just the given sub-region of the texture from the
+// GPU, using the texture's framebuffer -- much cheaper than a full
+// GrabImage for small areas (e.g., color picking under the cursor).
+// Returned image has the region's size, with top = 0 (Y flip handled).
+// Returns error if the texture does not have a framebuffer activated
+// for it (see ActivateFramebuffer).
+// Must be called with a valid gpu context and on proper thread for that context.
+func (tx *textureImpl) ReadRegion(r image.Rectangle) (*image.RGBA, error) {
+	if tx.fbuff == nil {
+		return nil, errors.New("Texture does not have a framebuffer activated for it -- cannot read region")
+	}
+	r = r.Intersect(image.Rectangle{Max: tx.size})
+	if r.Empty() {
+		return nil, errors.New("glos Texture2D ReadRegion: region is empty or outside texture bounds")
+	}
+	tx.ActivateFramebuffer()
+	sz := r.Size()
+	tmp := image.NewRGBA(image.Rectangle{Max: sz})
+	// gl has bottom = 0, so flip the Y origin of the region for reading
+	gy := tx.size.Y - r.Max.Y
+	gl.ReadPixels(int32(r.Min.X), int32(gy), int32(sz.X), int32(sz.Y), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(tmp.Pix))
+	img := image.NewRGBA(image.Rectangle{Max: sz})
+	tx.ImageFlipY(img, tmp)
+	return img, nil
+}
+
+////////////////////////////////////////////////
+//   textureRegion
+
+// textureRegion is a lightweight view onto a sub-rectangle of a parent
+// texture, sharing the same GPU handle -- used for texture atlases where
+// multiple logical textures are packed into one GL texture to reduce
+// texture binds.  When used as a source in Draw / Copy / Scale, the source
+// rectangle is translated into the parent texture's coordinates, so only
+// the region's pixels are rendered.  All GPU resource management (Delete
+// etc) remains with the parent texture.
+type textureRegion struct {
+	*textureImpl
+	region image.Rectangle
+}
+
+// Size returns the size of the region, not the parent texture
+func (tx *textureRegion) Size() image.Point {
+	return tx.region.Size()
+}
+
+// Bounds returns the bounds of the region, in region-relative coordinates
+func (tx *textureRegion) Bounds() image.Rectangle {
+	return image.Rectangle{Max: tx.region.Size()}
+}
+
+// srcTexture returns the underlying textureImpl for given draw source
+// texture, along with the offset to add to source coordinates to get
+// coordinates in that texture -- resolves a textureRegion view into its
+// parent texture and region offset.  Returns nil if src is neither.
+func srcTexture(src oswin.Texture) (*textureImpl, image.Point) {
+	switch t := src.(type) {
+	case *textureImpl:
+		return t, image.ZP
+	case *textureRegion:
+		return t.textureImpl, t.region.Min
+	}
+	return nil, image.ZP
+}
+
 ////////////////////////////////////////////////
 //   Drawer wrappers
 
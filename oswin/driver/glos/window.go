@@ -10,10 +10,13 @@
 package glos
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
+	"image/png"
 	"log"
+	"os"
 	"sync"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -22,6 +25,8 @@ import (
 	"github.com/goki/gi/oswin/driver/internal/drawer"
 	"github.com/goki/gi/oswin/driver/internal/event"
 	"github.com/goki/gi/oswin/gpu"
+	"github.com/goki/gi/oswin/key"
+	"github.com/goki/gi/oswin/mouse"
 	"github.com/goki/gi/oswin/window"
 	"github.com/goki/ki/bitflag"
 )
@@ -41,10 +46,19 @@ type windowImpl struct {
 	mainMenu       oswin.MainMenu
 	closeReqFunc   func(win oswin.Window)
 	closeCleanFunc func(win oswin.Window)
+	framePubFunc   func(win oswin.Window)
 	drawQuads      gpu.BufferMgr
 	fillQuads      gpu.BufferMgr
 	mouseDisabled  bool
+	rawMouseMotion bool
 	resettingPos   bool
+	winDragging    bool        // dragging the window itself via DragRegion
+	winDragOff     image.Point // raw cursor offset within window at drag start
+	evtFltMu       sync.Mutex
+	eventFilter    func(evi oswin.Event) oswin.Event
+	inputStateMu   sync.Mutex
+	keyState       map[key.Codes]bool   // keys currently held down -- see KeyState
+	mouseState     [mouse.ButtonsN]bool // mouse buttons currently pressed -- see MouseButtonState
 }
 
 // Handle returns the driver-specific handle for this window.
@@ -81,13 +95,12 @@ func (w *windowImpl) IsVisible() bool {
 // nothing further should happen.
 // Must call this on app main thread using oswin.TheApp.RunOnMain
 //
-// oswin.TheApp.RunOnMain(func() {
-//    if !win.Activate() {
-//        return
-//    }
-//    // do GPU calls here
-// })
-//
+//	oswin.TheApp.RunOnMain(func() {
+//	   if !win.Activate() {
+//	       return
+//	   }
+//	   // do GPU calls here
+//	})
 func (w *windowImpl) Activate() bool {
 	// note: activate is only run on main thread so we don't need to check for mutex
 	if w == nil || w.glw == nil {
@@ -119,15 +132,23 @@ func newGLWindow(opts *oswin.NewWindowOptions, sc *oswin.Screen) (*glfw.Window,
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 	glfw.WindowHint(glfw.Samples, 0) // don't do multisampling for main window -- only in sub-render
+	if bitflag.Has(opts.Flags, int(oswin.Transparent)) {
+		glfw.WindowHint(glfw.TransparentFramebuffer, glfw.True)
+	}
 	if glosDebug {
 		glfw.WindowHint(glfw.OpenGLDebugContext, glfw.True)
 	}
 
 	// todo: glfw.Samples -- multisampling
+	// note: all windows share the hidden shareWin context (last arg to
+	// CreateWindow below), which guarantees that textures and buffers
+	// uploaded in one window are usable in all others -- only VAOs and
+	// framebuffers are per-window and are managed per windowImpl.
 	if fullscreen {
 		glfw.WindowHint(glfw.Maximized, glfw.True)
 	}
-	if tool {
+	if tool || bitflag.Has(opts.Flags, int(oswin.Frameless)) {
+		// frameless remains resizable (hint above) for custom-drawn chrome
 		glfw.WindowHint(glfw.Decorated, glfw.False)
 	} else {
 		glfw.WindowHint(glfw.Decorated, glfw.True)
@@ -157,6 +178,46 @@ func (w *windowImpl) NextEvent() oswin.Event {
 	return e
 }
 
+// SetEventFilter sets a function that is called for every event just
+// before it is added to the window's event queue: it can return a
+// modified event to transform it, or nil to drop the event entirely.
+// Pass nil to remove the filter.
+func (w *windowImpl) SetEventFilter(fun func(evi oswin.Event) oswin.Event) {
+	w.evtFltMu.Lock()
+	w.eventFilter = fun
+	w.evtFltMu.Unlock()
+}
+
+// filterEvent passes the event through the event filter if one is set --
+// returns nil if the event should be dropped.
+func (w *windowImpl) filterEvent(event oswin.Event) oswin.Event {
+	w.evtFltMu.Lock()
+	fun := w.eventFilter
+	w.evtFltMu.Unlock()
+	if fun == nil {
+		return event
+	}
+	return fun(event)
+}
+
+// Send adds an event to the end of the window's event queue, after passing
+// it through the event filter if set -- see SetEventFilter.
+func (w *windowImpl) Send(event oswin.Event) {
+	if event = w.filterEvent(event); event == nil {
+		return
+	}
+	w.Deque.Send(event)
+}
+
+// SendFirst adds an event to the start of the window's event queue, after
+// passing it through the event filter if set -- see SetEventFilter.
+func (w *windowImpl) SendFirst(event oswin.Event) {
+	if event = w.filterEvent(event); event == nil {
+		return
+	}
+	w.Deque.SendFirst(event)
+}
+
 // winLoop is the window's own locked processing loop.
 func (w *windowImpl) winLoop() {
 outer:
@@ -177,6 +238,11 @@ outer:
 				break outer
 			}
 			if !theApp.noScreens {
+				if w.IsMinimized() { // fully occluded -- nothing will be seen,
+					// so don't waste the swap -- but must still release Publish
+					w.publishDone <- struct{}{}
+					continue
+				}
 				theApp.RunOnMain(func() {
 					if !w.Activate() {
 						return
@@ -186,6 +252,12 @@ outer:
 					// gpu.Draw.Clear(true, true)
 				})
 				w.publishDone <- struct{}{}
+				w.mu.Lock()
+				fpf := w.framePubFunc
+				w.mu.Unlock()
+				if fpf != nil {
+					fpf(w)
+				}
 			}
 		}
 	}
@@ -278,6 +350,50 @@ func (w *windowImpl) SetWinTexSubImage(dp image.Point, src image.Image, sr image
 	return err
 }
 
+// GrabImage returns a copy of the current rendered contents of the
+// window (including 3D content), e.g., for screenshots -- Y axis is
+// flipped so top = 0 in the returned image.  Returns an error if the
+// window has not yet been rendered.  This version manages the
+// TheApp.RunOnMain and context Activate steps itself, so it must NOT
+// be called on the app main thread.
+func (w *windowImpl) GrabImage() (*image.RGBA, error) {
+	if w.IsClosed() || w.winTex == nil {
+		return nil, fmt.Errorf("glos GrabImage: window %v not available", w.Nm)
+	}
+	var img *image.RGBA
+	var err error
+	theApp.RunOnMain(func() {
+		if !w.Activate() {
+			err = fmt.Errorf("glos GrabImage: could not activate window %v", w.Nm)
+			return
+		}
+		gii := w.winTex.GrabImage() // flips Y so top = 0
+		if gii == nil {
+			err = fmt.Errorf("glos GrabImage: window %v has not been rendered yet", w.Nm)
+			return
+		}
+		src := gii.(*image.RGBA)
+		img = image.NewRGBA(src.Bounds()) // copy: GrabImage returns internal image
+		copy(img.Pix, src.Pix)
+	})
+	return img, err
+}
+
+// SavePNG saves the current rendered contents of the window
+// (see GrabImage) to a PNG-encoded file at given path.
+func (w *windowImpl) SavePNG(path string) error {
+	img, err := w.GrabImage()
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return png.Encode(file, img)
+}
+
 ////////////////////////////////////////////////
 //   Drawer wrappers
 
@@ -361,6 +477,17 @@ func (w *windowImpl) Size() image.Point {
 	return w.PxSize
 }
 
+// RefreshRate returns the refresh rate of the screen this window is
+// currently displayed on, in Hz -- returns a default of 60 if it
+// cannot be determined.
+func (w *windowImpl) RefreshRate() float32 {
+	sc := w.Screen()
+	if sc == nil || sc.RefreshRate <= 0 {
+		return 60
+	}
+	return sc.RefreshRate
+}
+
 func (w *windowImpl) WinSize() image.Point {
 	return w.WnSize
 }
@@ -405,10 +532,13 @@ func (w *windowImpl) SetTitle(title string) {
 	})
 }
 
-func (w *windowImpl) SetSize(sz image.Point) {
+func (w *windowImpl) SetSize(sz image.Point) bool {
 	if w.IsClosed() {
-		return
+		return false
 	}
+	_, csz, _ := oswin.ConstrainWinGeom(w.Pos, sz)
+	chg := csz != sz
+	sz = csz
 	// note: anything run on main only doesn't need lock -- implicit lock
 	w.app.RunOnMain(func() {
 		if w.glw == nil { // by time we got to main, could be diff
@@ -416,22 +546,26 @@ func (w *windowImpl) SetSize(sz image.Point) {
 		}
 		w.glw.SetSize(sz.X, sz.Y)
 	})
+	return !chg
 }
 
-func (w *windowImpl) SetPixSize(sz image.Point) {
+func (w *windowImpl) SetPixSize(sz image.Point) bool {
 	if w.IsClosed() {
-		return
+		return false
 	}
 	sc := w.getScreen()
 	sz.X = int(float32(sz.X) / sc.DevicePixelRatio)
 	sz.Y = int(float32(sz.Y) / sc.DevicePixelRatio)
-	w.SetSize(sz)
+	return w.SetSize(sz)
 }
 
-func (w *windowImpl) SetPos(pos image.Point) {
+func (w *windowImpl) SetPos(pos image.Point) bool {
 	if w.IsClosed() {
-		return
+		return false
 	}
+	cpos, _, _ := oswin.ConstrainWinGeom(pos, w.WnSize)
+	chg := cpos != pos
+	pos = cpos
 	// note: anything run on main only doesn't need lock -- implicit lock
 	w.app.RunOnMain(func() {
 		if w.glw == nil { // by time we got to main, could be diff
@@ -439,12 +573,14 @@ func (w *windowImpl) SetPos(pos image.Point) {
 		}
 		w.glw.SetPos(pos.X, pos.Y)
 	})
+	return !chg
 }
 
-func (w *windowImpl) SetGeom(pos image.Point, sz image.Point) {
+func (w *windowImpl) SetGeom(pos image.Point, sz image.Point) bool {
 	if w.IsClosed() {
-		return
+		return false
 	}
+	pos, sz, chg := oswin.ConstrainWinGeom(pos, sz)
 	// note: anything run on main only doesn't need lock -- implicit lock
 	w.app.RunOnMain(func() {
 		if w.glw == nil { // by time we got to main, could be diff
@@ -453,6 +589,35 @@ func (w *windowImpl) SetGeom(pos image.Point, sz image.Point) {
 		w.glw.SetSize(sz.X, sz.Y)
 		w.glw.SetPos(pos.X, pos.Y)
 	})
+	return !chg
+}
+
+func (w *windowImpl) SetOpacity(opacity float32) {
+	if w.IsClosed() {
+		return
+	}
+	opacity = mat32.Clamp(opacity, 0, 1)
+	// note: anything run on main only doesn't need lock -- implicit lock
+	w.app.RunOnMain(func() {
+		if w.glw == nil { // by time we got to main, could be diff
+			return
+		}
+		w.glw.SetOpacity(opacity)
+	})
+}
+
+func (w *windowImpl) Opacity() float32 {
+	if w.IsClosed() {
+		return 1
+	}
+	op := float32(1)
+	w.app.RunOnMain(func() {
+		if w.glw == nil { // by time we got to main, could be diff
+			return
+		}
+		op = w.glw.GetOpacity()
+	})
+	return op
 }
 
 func (w *windowImpl) show() {
@@ -485,6 +650,19 @@ func (w *windowImpl) Raise() {
 	})
 }
 
+func (w *windowImpl) RequestAttention() {
+	if w.IsClosed() || w.IsFocus() { // already focused = already has attention
+		return
+	}
+	// note: anything run on main only doesn't need lock -- implicit lock
+	w.app.RunOnMain(func() {
+		if w.glw == nil { // by time we got to main, could be diff
+			return
+		}
+		w.glw.RequestAttention()
+	})
+}
+
 func (w *windowImpl) Minimize() {
 	if w.IsClosed() {
 		return
@@ -498,6 +676,12 @@ func (w *windowImpl) Minimize() {
 	})
 }
 
+func (w *windowImpl) SetFramePublishedFunc(fun func(win oswin.Window)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.framePubFunc = fun
+}
+
 func (w *windowImpl) SetCloseReqFunc(fun func(win oswin.Window)) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -570,10 +754,83 @@ func (w *windowImpl) SetCursorEnabled(enabled, raw bool) {
 	} else {
 		w.mouseDisabled = true
 		w.glw.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-		if raw && glfw.RawMouseMotionSupported() {
+		w.SetRawMouseMotion(raw)
+	}
+}
+
+// SetRawMouseMotion turns raw (unfiltered, unaccelerated) mouse motion
+// on / off for the disabled-cursor mode -- motion events then carry the
+// position delta in Where relative to From (see mouse.MoveEvent.Delta).
+// Returns false if not supported on this platform (e.g., Mac).
+func (w *windowImpl) SetRawMouseMotion(raw bool) bool {
+	if !glfw.RawMouseMotionSupported() {
+		return false
+	}
+	w.rawMouseMotion = raw
+	if w.glw != nil {
+		if raw {
 			w.glw.SetInputMode(glfw.RawMouseMotion, glfw.True)
+		} else {
+			w.glw.SetInputMode(glfw.RawMouseMotion, glfw.False)
 		}
 	}
+	return true
+}
+
+// setKeyState records the current pressed state of given key from the key
+// event callback -- see KeyState
+func (w *windowImpl) setKeyState(code key.Codes, down bool) {
+	w.inputStateMu.Lock()
+	if w.keyState == nil {
+		w.keyState = make(map[key.Codes]bool)
+	}
+	if down {
+		w.keyState[code] = true
+	} else {
+		delete(w.keyState, code)
+	}
+	w.inputStateMu.Unlock()
+}
+
+// setMouseState records the current pressed state of given mouse button
+// from the mouse button event callback -- see MouseButtonState
+func (w *windowImpl) setMouseState(but mouse.Buttons, down bool) {
+	w.inputStateMu.Lock()
+	if but >= 0 && but < mouse.ButtonsN {
+		w.mouseState[but] = down
+	}
+	w.inputStateMu.Unlock()
+}
+
+// clearInputState clears all held key / mouse button state -- called when
+// the window loses focus, as the corresponding release events go elsewhere
+func (w *windowImpl) clearInputState() {
+	w.inputStateMu.Lock()
+	w.keyState = nil
+	w.mouseState = [mouse.ButtonsN]bool{}
+	w.inputStateMu.Unlock()
+}
+
+// KeyState returns whether the physical key with the given key code
+// (a key.Codes value) is currently held down on this window, for polling
+// in game-style loops -- maintained from the same callbacks that generate
+// key events, so it stays consistent with the event stream.
+// Safe to call from any goroutine.
+func (w *windowImpl) KeyState(code uint32) bool {
+	w.inputStateMu.Lock()
+	defer w.inputStateMu.Unlock()
+	return w.keyState[key.Codes(code)]
+}
+
+// MouseButtonState returns whether the given mouse button (a mouse.Buttons
+// value) is currently pressed on this window -- see KeyState.
+func (w *windowImpl) MouseButtonState(but int32) bool {
+	if but < 0 || but >= int32(mouse.ButtonsN) {
+		return false
+	}
+	w.inputStateMu.Lock()
+	defer w.inputStateMu.Unlock()
+	return w.mouseState[but]
 }
 
 /////////////////////////////////////////////////////////
@@ -690,8 +947,12 @@ func (w *windowImpl) closeReq(gw *glfw.Window) {
 	go w.CloseReq()
 }
 
+// refresh is the glfw callback for an OS repaint request (e.g., window
+// contents damaged by another window or un-minimized) -- sends a
+// window.Paint event so the app decides whether / how to re-render,
+// rather than forcing a Publish here -- supports on-demand rendering.
 func (w *windowImpl) refresh(gw *glfw.Window) {
-	go w.Publish()
+	w.sendWindowEvent(window.Paint)
 }
 
 func (w *windowImpl) focus(gw *glfw.Window, focused bool) {
@@ -706,6 +967,7 @@ func (w *windowImpl) focus(gw *glfw.Window, focused bool) {
 	} else {
 		// fmt.Printf("unfoc win: %v, foc: %v\n", w.Nm, bitflag.HasAtomic(&w.Flag, int(oswin.Focus)))
 		bitflag.ClearAtomic(&w.Flag, int(oswin.Focus))
+		w.clearInputState() // release events go elsewhere -- don't leave keys stuck down
 		w.sendWindowEvent(window.DeFocus)
 	}
 }
@@ -719,5 +981,6 @@ func (w *windowImpl) iconify(gw *glfw.Window, iconified bool) {
 		bitflag.ClearAtomic(&w.Flag, int(oswin.Minimized))
 		w.getScreen()
 		w.sendWindowEvent(window.Minimize)
+		w.sendWindowEvent(window.Paint) // publishes were skipped while minimized
 	}
 }
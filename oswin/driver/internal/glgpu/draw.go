@@ -53,6 +53,40 @@ func (dr *Drawing) DepthTest(on bool) {
 	}
 }
 
+// DepthFunc sets the depth comparison function -- DepthLEqual is the
+// default, restored whenever DepthTest is turned on.
+func (dr *Drawing) DepthFunc(df gpu.DepthFuncs) {
+	switch df {
+	case gpu.DepthLess:
+		gl.DepthFunc(gl.LESS)
+	case gpu.DepthEqual:
+		gl.DepthFunc(gl.EQUAL)
+	case gpu.DepthGEqual:
+		gl.DepthFunc(gl.GEQUAL)
+	case gpu.DepthGreater:
+		gl.DepthFunc(gl.GREATER)
+	case gpu.DepthNotEqual:
+		gl.DepthFunc(gl.NOTEQUAL)
+	case gpu.DepthAlways:
+		gl.DepthFunc(gl.ALWAYS)
+	case gpu.DepthNever:
+		gl.DepthFunc(gl.NEVER)
+	default:
+		gl.DepthFunc(gl.LEQUAL)
+	}
+}
+
+// PolygonOffset sets the depth offset applied while rasterizing filled
+// polygons -- (0, 0) disables the offset.
+func (dr *Drawing) PolygonOffset(factor, units float32) {
+	if factor == 0 && units == 0 {
+		gl.Disable(gl.POLYGON_OFFSET_FILL)
+	} else {
+		gl.Enable(gl.POLYGON_OFFSET_FILL)
+		gl.PolygonOffset(factor, units)
+	}
+}
+
 // StencilTest turns on / off stencil testing
 func (dr *Drawing) StencilTest(on bool) {
 	if on {
@@ -94,6 +128,28 @@ func (dr *Drawing) Multisample(on bool) {
 	}
 }
 
+// SRGB turns on or off sRGB framebuffer output conversion: when on,
+// linear color values computed by the shaders are gamma-encoded to sRGB
+// when written to the render target.
+func (dr *Drawing) SRGB(on bool) {
+	if on {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	} else {
+		gl.Disable(gl.FRAMEBUFFER_SRGB)
+	}
+}
+
+// ClipPlane turns on / off the user-defined clipping plane
+// (GL_CLIP_DISTANCE0) -- the plane itself is set by writing
+// gl_ClipDistance[0] in the vertex shader.
+func (dr *Drawing) ClipPlane(on bool) {
+	if on {
+		gl.Enable(gl.CLIP_DISTANCE0)
+	} else {
+		gl.Disable(gl.CLIP_DISTANCE0)
+	}
+}
+
 // Op sets the blend function based on go standard draw operation
 // Src disables blending, and Over uses alpha-blending
 func (dr *Drawing) Op(op draw.Op) {
@@ -115,6 +171,13 @@ func (dr *Drawing) Wireframe(on bool) {
 	}
 }
 
+// ColorMask enables / disables writing to the given color channels of
+// the current render target -- e.g., for compositing anaglyph stereo.
+// All channels are on by default.
+func (dr *Drawing) ColorMask(r, g, b, a bool) {
+	gl.ColorMask(r, g, b, a)
+}
+
 // Viewport sets the rendering viewport to given rectangle.
 // It is important to update this for each render -- cannot assume it.
 func (dr *Drawing) Viewport(rect image.Rectangle) {
@@ -149,6 +212,36 @@ func (dr *Drawing) TriangleStripsIndexed(start, count int) {
 	gl.DrawElements(gl.TRIANGLE_STRIP, int32(count), gl.UNSIGNED_INT, gl.PtrOffset(start*4))
 }
 
+// primGLMode returns the GL drawing mode for given primitive type
+func primGLMode(prim gpu.Primitives) uint32 {
+	switch prim {
+	case gpu.PrimTriangleStrip:
+		return gl.TRIANGLE_STRIP
+	case gpu.PrimTriangleFan:
+		return gl.TRIANGLE_FAN
+	case gpu.PrimLines:
+		return gl.LINES
+	case gpu.PrimPoints:
+		return gl.POINTS
+	default:
+		return gl.TRIANGLES
+	}
+}
+
+// Primitives uses all existing settings to draw the given primitive type
+// (non-indexed)
+func (dr *Drawing) Primitives(prim gpu.Primitives, start, count int) {
+	gl.DrawArrays(primGLMode(prim), int32(start), int32(count))
+}
+
+// PrimitivesIndexed uses all existing settings to draw the given primitive
+// type indexed.  You must have activated an IndexesBuffer that supplies
+// the indexes, and start + count determine range of such indexes
+// to use, and must be within bounds for that.
+func (dr *Drawing) PrimitivesIndexed(prim gpu.Primitives, start, count int) {
+	gl.DrawElements(primGLMode(prim), int32(count), gl.UNSIGNED_INT, gl.PtrOffset(start*4))
+}
+
 // Flush ensures that all rendering is pushed to current render target.
 // Especially useful for rendering to framebuffers (Window SwapBuffer
 // automatically does a flush)
@@ -118,6 +118,10 @@ const (
 	// DNDFocusEvent is for Enter / Exit events of the DND into / out of a given widget
 	DNDFocusEvent
 
+	// GamepadEvent is for gamepad / joystick state changes, and
+	// connect / disconnect of devices -- sent to the window in focus
+	GamepadEvent
+
 	// CustomEventType is a user-defined event with a data interface{} field
 	CustomEventType
 
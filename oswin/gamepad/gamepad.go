@@ -0,0 +1,87 @@
+// Copyright (c) 2020, The GoKi Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gamepad defines an event for gamepad / joystick input,
+// for the GoGi GUI system.  The driver polls the state of connected
+// gamepads each pass through its event loop and sends an Update event
+// to the window in focus whenever the state changes, along with
+// Connect / Disconnect events as devices come and go.
+package gamepad
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/goki/gi/oswin"
+	"github.com/goki/ki/kit"
+)
+
+// gamepad.Event is a gamepad / joystick event.
+type Event struct {
+	oswin.EventBase
+
+	// ID is the driver-level id of the gamepad (e.g., glfw joystick
+	// number), constant for as long as the device remains connected.
+	ID int
+
+	// Name is the human-readable device name as reported by the driver.
+	Name string
+
+	// Action is the gamepad action: state Update, Connect, Disconnect.
+	Action Actions
+
+	// Axes has the current position of each analog axis, in -1..1
+	// normalized units.  Nil for Connect / Disconnect events.
+	Axes []float32
+
+	// Buttons has the current pressed state of each button.
+	// Nil for Connect / Disconnect events.
+	Buttons []bool
+}
+
+// Actions describes the action taken for a gamepad event.
+type Actions int32
+
+const (
+	// Update is a change in the axis / button state of a connected gamepad.
+	Update Actions = iota
+
+	// Connect is a gamepad being plugged in (or present at startup).
+	Connect
+
+	// Disconnect is a gamepad being unplugged.
+	Disconnect
+
+	ActionsN
+)
+
+//go:generate stringer -type=Actions
+
+var KiT_Actions = kit.Enums.AddEnum(ActionsN, kit.NotBitFlag, nil)
+
+/////////////////////////////
+// oswin.Event interface
+
+func (ev Event) Type() oswin.EventType {
+	return oswin.GamepadEvent
+}
+
+func (ev Event) HasPos() bool {
+	return false
+}
+
+func (ev Event) Pos() image.Point {
+	return image.ZP
+}
+
+func (ev Event) OnFocus() bool {
+	return false
+}
+
+func (ev Event) String() string {
+	return fmt.Sprintf("Type: %v Action: %v ID: %v Time: %v", ev.Type(), ev.Action, ev.ID, ev.Time())
+}
+
+// check for interface implementation
+var _ oswin.Event = &Event{}
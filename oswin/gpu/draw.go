@@ -7,8 +7,78 @@ package gpu
 import (
 	"image"
 	"image/draw"
+
+	"github.com/goki/ki/kit"
+)
+
+// DepthFuncs are the depth comparison functions for DepthFunc --
+// DepthLEqual is the zero-value default used throughout.
+type DepthFuncs int32
+
+const (
+	// DepthLEqual passes if the incoming depth is <= the stored depth (the default)
+	DepthLEqual DepthFuncs = iota
+
+	// DepthLess passes if the incoming depth is strictly < the stored depth
+	DepthLess
+
+	// DepthEqual passes if the incoming depth is == the stored depth
+	DepthEqual
+
+	// DepthGEqual passes if the incoming depth is >= the stored depth
+	DepthGEqual
+
+	// DepthGreater passes if the incoming depth is strictly > the stored depth
+	DepthGreater
+
+	// DepthNotEqual passes if the incoming depth is != the stored depth
+	DepthNotEqual
+
+	// DepthAlways always passes -- draws regardless of stored depth
+	DepthAlways
+
+	// DepthNever never passes
+	DepthNever
+
+	DepthFuncsN
+)
+
+//go:generate stringer -type=DepthFuncs
+
+var KiT_DepthFuncs = kit.Enums.AddEnum(DepthFuncsN, kit.NotBitFlag, nil)
+
+// Primitives are the primitive types that can be drawn from vertex / index
+// data -- PrimTriangles is the zero-value default used throughout.
+type Primitives int32
+
+const (
+	// PrimTriangles renders each consecutive group of 3 vertices / indexes
+	// as an independent triangle (the default)
+	PrimTriangles Primitives = iota
+
+	// PrimTriangleStrip renders a connected strip of triangles, where each
+	// vertex / index after the first two adds one triangle -- much more
+	// compact than independent triangles for grids and ribbons
+	PrimTriangleStrip
+
+	// PrimTriangleFan renders a fan of triangles all sharing the first
+	// vertex, where each vertex / index after the first two adds one triangle
+	PrimTriangleFan
+
+	// PrimLines renders each consecutive pair of vertices / indexes as an
+	// independent line segment
+	PrimLines
+
+	// PrimPoints renders each vertex / index as an independent point
+	PrimPoints
+
+	PrimitivesN
 )
 
+//go:generate stringer -type=Primitives
+
+var KiT_Primitives = kit.Enums.AddEnum(PrimitivesN, kit.NotBitFlag, nil)
+
 // Draw is the current oswin gpu Drawing instance.
 // Call methods as, e.g.: gpu.Draw.Triangles(..) etc..
 var Draw Drawing
@@ -25,6 +95,17 @@ type Drawing interface {
 	// DepthTest turns on / off depth testing (standard less-than-or-equal depth assumed).
 	DepthTest(on bool)
 
+	// DepthFunc sets the depth comparison function -- DepthLEqual is the
+	// default, restored whenever DepthTest is turned on.
+	DepthFunc(df DepthFuncs)
+
+	// PolygonOffset sets the depth offset applied while rasterizing filled
+	// polygons: factor scales with the polygon's depth slope, units is in
+	// minimum resolvable depth units -- (0, 0) disables the offset.
+	// Use small negative values (e.g., -1, -1) to pull coplanar decals /
+	// outlines in front of the underlying surface without z-fighting.
+	PolygonOffset(factor, units float32)
+
 	// StencilTest turns on / off stencil testing
 	StencilTest(on bool)
 
@@ -36,6 +117,17 @@ type Drawing interface {
 	// Multisample turns on or off multisampling (antialiasing)
 	Multisample(on bool)
 
+	// SRGB turns on or off sRGB framebuffer output conversion: when on,
+	// linear color values computed by the shaders are gamma-encoded to sRGB
+	// when written to the render target.  Pair with sRGB color textures
+	// (see oswin.Texture SetSRGB) so the full render path is gamma-correct.
+	SRGB(on bool)
+
+	// ClipPlane turns on / off the user-defined clipping plane
+	// (GL_CLIP_DISTANCE0) -- the plane itself is set by writing
+	// gl_ClipDistance[0] in the vertex shader.
+	ClipPlane(on bool)
+
 	// Op sets the blend function based on go standard draw operation
 	// Src disables blending, and Over uses alpha-blending
 	Op(op draw.Op)
@@ -43,6 +135,11 @@ type Drawing interface {
 	// Wireframe sets the rendering to lines instead of fills if on = true
 	Wireframe(on bool)
 
+	// ColorMask enables / disables writing to the given color channels of
+	// the current render target -- e.g., for compositing anaglyph stereo.
+	// All channels are on by default.
+	ColorMask(r, g, b, a bool)
+
 	// Viewport sets the rendering viewport to given rectangle.
 	// It is important to update this for each render -- cannot assume it.
 	Viewport(rect image.Rectangle)
@@ -67,6 +164,19 @@ type Drawing interface {
 	// to use, and must be within bounds for that.
 	TriangleStripsIndexed(start, count int)
 
+	// Primitives uses all existing settings to draw the given primitive type
+	// (non-indexed) -- the generalization of Triangles / TriangleStrips to
+	// all Primitives types.
+	Primitives(prim Primitives, start, count int)
+
+	// PrimitivesIndexed uses all existing settings to draw the given
+	// primitive type indexed -- the generalization of TrianglesIndexed /
+	// TriangleStripsIndexed to all Primitives types.
+	// You must have activated an IndexesBuffer that supplies
+	// the indexes, and start + count determine range of such indexes
+	// to use, and must be within bounds for that.
+	PrimitivesIndexed(prim Primitives, start, count int)
+
 	// Flush ensures that all rendering is pushed to current render target.
 	// Especially useful for rendering to framebuffers (Window SwapBuffer
 	// automatically does a flush)
@@ -57,6 +57,12 @@ type GPU interface {
 	// Type returns the GPU data type id for given type
 	Type(typ Types) uint32
 
+	// MaxTextureSize returns the maximum size (width or height, in
+	// pixels) of a 2D texture supported by this GPU
+	// (i.e., GL_MAX_TEXTURE_SIZE) -- queried once at initialization.
+	// Textures larger than this are logged and downscaled to fit.
+	MaxTextureSize() int
+
 	// NewProgram returns a new Program with given name -- for standalone programs.
 	// See also NewPipeline.
 	NewProgram(name string) Program
@@ -63,6 +63,14 @@ type Event struct {
 	// Action is the key action taken: Press, Release, or None (for key repeats).
 	Action Actions
 
+	// Composed is true if this event came from the system text-input path
+	// (e.g., the glfw char callback) instead of a raw physical key event:
+	// Rune is then a fully composed character, reflecting dead-key sequences
+	// and IME composition (e.g., for CJK input), and Code is not meaningful.
+	// Text-entry widgets should insert text from Composed events and use
+	// non-composed events only for navigation / command chords.
+	Composed bool
+
 	// TODO: add a Device ID, for multiple input devices?
 }
 
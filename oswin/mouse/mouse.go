@@ -17,8 +17,10 @@ package mouse
 import (
 	"fmt"
 	"image"
+	"math"
 	"time"
 
+	"github.com/goki/gi/mat32"
 	"github.com/goki/gi/oswin"
 	"github.com/goki/gi/oswin/key"
 	"github.com/goki/ki/kit"
@@ -136,8 +138,23 @@ func (e MoveEvent) Delta() image.Point {
 type ScrollEvent struct {
 	Event
 
-	// Delta is the amount of scrolling in each axis
+	// Delta is the amount of scrolling in each axis, in whole pixels
 	Delta image.Point
+
+	// DeltaPrec is the high-precision scroll amount in each axis, preserving
+	// the fractional deltas produced by trackpads and high-resolution wheels
+	// -- Delta is this rounded to whole pixels (see SetDeltas).  Receivers
+	// doing smooth scrolling / zooming should prefer this over Delta.
+	DeltaPrec mat32.Vec2
+}
+
+// SetDeltas sets the high-precision DeltaPrec from given per-axis scroll
+// amounts, and sets Delta to the same values rounded to the nearest whole
+// pixel -- used by drivers that deliver float64 scroll deltas, so that both
+// axes and sub-integer magnitudes are preserved.
+func (e *ScrollEvent) SetDeltas(x, y float64) {
+	e.DeltaPrec = mat32.Vec2{float32(x), float32(y)}
+	e.Delta = image.Point{int(math.Round(x)), int(math.Round(y))}
 }
 
 // NonZeroDelta attempts to find a non-zero delta -- often only get Y
@@ -152,6 +169,18 @@ func (e ScrollEvent) NonZeroDelta(prefX bool) int {
 	return e.Delta.Y
 }
 
+// NonZeroDeltaPrec is the high-precision version of NonZeroDelta, using
+// DeltaPrec -- see there.
+func (e ScrollEvent) NonZeroDeltaPrec(prefX bool) float32 {
+	if prefX {
+		if e.DeltaPrec.X == 0 {
+			return e.DeltaPrec.Y
+		}
+		return e.DeltaPrec.X
+	}
+	return e.DeltaPrec.Y
+}
+
 /////////////////////////////////////////////////////////////////
 
 // mouse.FocusEvent records actions of Enter and Exit of mouse into a given
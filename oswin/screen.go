@@ -28,6 +28,13 @@ type Screen struct {
 	// size units, which may not be same as raw pixels
 	Geometry image.Rectangle
 
+	// WorkArea is the usable work area of the screen, in the same
+	// window manager size units as Geometry, excluding any areas
+	// occupied by system taskbars, docks, or menu bars.  If the
+	// driver cannot determine it, it is the same as Geometry.
+	// New windows should generally be positioned within this area.
+	WorkArea image.Rectangle
+
 	// DevicePixelRatio is a factor that scales the screen's
 	// "natural" pixel coordinates into actual device pixels.
 	// On OS-X  it is backingScaleFactor = 2.0 on "retina"
@@ -13,6 +13,7 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	"io"
 
 	"github.com/goki/gi/mat32"
 )
@@ -28,6 +29,10 @@ import (
 // Textures can also be drawn onto Textures, and can grab rendered output
 // from 3D graphics rendering (e.g., via gpu.FrameBuffer and gi3d packages).
 //
+// All windows share a common GPU context, so a Texture uploaded under one
+// window can be drawn on any other window -- only the per-window steps
+// (TheApp.RunOnMain with that window Activate()'d) differ per target.
+//
 // Unlike Window, the Drawer interface for Texture does *not* manage the
 // TheApp.RunOnMain and context Activate steps needed to set GPU context properly -
 // these must be done prior to calling any of those routines.  Also, a
@@ -57,6 +62,15 @@ type Texture interface {
 	// and on proper thread for that context.
 	Open(path string) error
 
+	// OpenReader loads texture image from the given reader, e.g., from an
+	// embedded filesystem, zip archive, or network stream.  The format
+	// argument is an optional hint, e.g., the file extension -- the format
+	// is otherwise inferred from the image data itself.
+	// Generally call this prior to doing Activate --
+	// if Activate()'d, then must be called with a valid gpu context
+	// and on proper thread for that context.
+	OpenReader(r io.Reader, format string) error
+
 	// Image returns the current image -- typically as an *image.RGBA.
 	// This is the image that was last set using Open, SetImage, or GrabImage.
 	// Use GrabImage to get the current GPU-side image, e.g., for rendering targets.
@@ -96,6 +110,18 @@ type Texture interface {
 	// Must be called with a valid gpu context and on proper thread for that context.
 	SetSubImage(dp image.Point, src image.Image, sr image.Rectangle) error
 
+	// SetSubImages uploads the given list of dirty sub-rectangles of src to
+	// the texture, each translated by dp (i.e., sr.Min in src-space aligns
+	// with sr.Min + dp in dst-space -- use a zero dp when src and texture
+	// share the same coordinate space).  This is the efficient path for
+	// streaming content (video, remote framebuffers) where only a few
+	// scanlines / regions change each frame: src is converted and the
+	// texture activated once, and each rectangle is uploaded in a single
+	// call regardless of stride.  Same activation requirements as
+	// SetSubImage.
+	// Must be called with a valid gpu context and on proper thread for that context.
+	SetSubImages(dp image.Point, src image.Image, srs []image.Rectangle) error
+
 	// Size returns the size of the texture
 	Size() image.Point
 
@@ -109,6 +135,14 @@ type Texture interface {
 	// image.Rectangle{Max: t.Size()}.
 	Bounds() image.Rectangle
 
+	// Region returns a lightweight view onto the given sub-rectangle of
+	// this texture, sharing the same GPU handle, for use as a source in
+	// Draw / Copy / Scale calls.  This supports texture atlases, where
+	// multiple logical textures are packed into one GPU texture to reduce
+	// texture binds.  The parent texture remains responsible for all GPU
+	// resource management and must outlive any regions referencing it.
+	Region(r image.Rectangle) Texture
+
 	// BotZero returns true if this texture has the Y=0 pixels at the bottom
 	// of the image.  Otherwise, Y=0 is at the top, which is the default
 	// for most images loaded from files.
@@ -119,6 +153,21 @@ type Texture interface {
 	// for most images loaded from files.
 	SetBotZero(botzero bool)
 
+	// SRGB returns true if this texture is uploaded to the GPU in the sRGB
+	// color space, so the GPU linearizes the color values when sampling.
+	SRGB() bool
+
+	// SetSRGB sets whether this texture is uploaded to the GPU in the sRGB
+	// color space.  Color images (e.g., diffuse / albedo maps, photos) are
+	// authored in sRGB and should set this so lighting math operates on
+	// linear values -- pair with sRGB framebuffer output for correct gamma
+	// (see gpu.Drawing.SRGB).  Data textures (normal, roughness, metallic
+	// maps etc) encode non-color values and must remain linear (off, the
+	// default).  If the texture is already on the GPU, the image is
+	// re-uploaded with the new internal format.
+	// Must be called with a valid gpu context and on proper thread for that context.
+	SetSRGB(srgb bool)
+
 	// Activate establishes the GPU resources and handle for the
 	// texture, using the given texture number (0-31 range).
 	// If an image has already been set for this texture, then it is
@@ -170,6 +219,15 @@ type Texture interface {
 	// Must be called with a valid gpu context and on proper thread for that context.
 	FrameDepthAt(x, y int) (float32, error)
 
+	// ReadRegion reads back just the given sub-region of the texture from
+	// the GPU, using the texture's framebuffer -- much cheaper than a full
+	// GrabImage for small areas (e.g., color picking under the cursor).
+	// Returned image has the region's size, with top = 0 (Y flip handled).
+	// Returns error if the texture does not have a framebuffer activated
+	// for it (see ActivateFramebuffer).
+	// Must be called with a valid gpu context and on proper thread for that context.
+	ReadRegion(r image.Rectangle) (*image.RGBA, error)
+
 	Drawer
 }
 
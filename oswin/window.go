@@ -39,7 +39,6 @@ import (
 //
 // IMPORTANT: ALL GPU (e.g., OPENGL) CALLS MUST USE oswin.TheApp.RunOnMain()
 // to execute on the main thread!!
-//
 type Window interface {
 
 	// Name returns the name of the window -- name is used strictly for
@@ -68,30 +67,59 @@ type Window interface {
 
 	// SetSize sets the size of the window, in OS-specific window manager
 	// units that may not include any high DPI factors (DevPixRatio)
-	// (i.e., the same units as returned in WinSize)
-	SetSize(sz image.Point)
+	// (i.e., the same units as returned in WinSize).
+	// The size is clamped to the screen work area (see ConstrainWinGeom) --
+	// returns true if the requested size was applied exactly, false if it
+	// had to be adjusted.
+	SetSize(sz image.Point) bool
 
 	// SetPixSize sets the size of the window, in actual pixel units.
 	// Divides by DevPixRatio before calling SetSize.
-	SetPixSize(sz image.Point)
+	SetPixSize(sz image.Point) bool
 
 	// SetPos sets the position of the window, in OS window manager
 	// coordinates, which may be different from Size() coordinates
-	// that reflect high DPI
-	SetPos(pos image.Point)
+	// that reflect high DPI.
+	// The position is clamped so the window remains reachable on the
+	// currently-connected screens (see ConstrainWinGeom) -- returns true
+	// if the requested position was applied exactly, false if it had to
+	// be adjusted (e.g., a saved position on a now-disconnected monitor).
+	SetPos(pos image.Point) bool
 
 	// SetGeom sets the position and size in one call -- use this if doing
 	// both because sequential calls to SetPos and SetSize might fail on some
 	// platforms.  Uses OS-specific window manager units that may not include
 	// any high DPI factors (DevPixRatio)
-	// (i.e., the same units as returned in WinSize, Pos())
-	SetGeom(pos image.Point, sz image.Point)
+	// (i.e., the same units as returned in WinSize, Pos()).
+	// The geometry is clamped as in SetPos / SetSize (see ConstrainWinGeom)
+	// -- returns true if the requested geometry was applied exactly, false
+	// if it had to be adjusted.
+	SetGeom(pos image.Point, sz image.Point) bool
+
+	// SetOpacity sets the opacity of the entire window, including any
+	// decorations, from 0 (fully transparent) to 1 (fully opaque) --
+	// values are clamped to that range.  No-op on platforms that do not
+	// support window opacity.  For per-pixel transparency, request the
+	// Transparent flag at window creation instead.
+	SetOpacity(opacity float32)
+
+	// Opacity returns the current opacity of the entire window (1 = fully
+	// opaque, which is also returned on platforms that do not support
+	// window opacity).
+	Opacity() float32
 
 	// Raise requests that the window be at the top of the stack of windows,
 	// and receive focus.  If it is iconified, it will be de-iconified.  This
 	// is the only supported mechanism for de-iconifying.
 	Raise()
 
+	// RequestAttention requests the user's attention to the window without
+	// stealing focus, using the platform's notification mechanism (e.g.,
+	// flashing the taskbar entry or bouncing the dock icon) -- use when a
+	// background window has something to report, such as a long-running
+	// task finishing or an error.  No-op if the window is already focused.
+	RequestAttention()
+
 	// Minimize requests that the window be iconified, making it no longer
 	// visible or active -- rendering should not occur for minimized windows.
 	Minimize()
@@ -115,6 +143,12 @@ type Window interface {
 	// information about its properties.
 	Screen() *Screen
 
+	// RefreshRate returns the refresh rate of the screen this window is
+	// currently displayed on, in Hz -- returns a default of 60 if it
+	// cannot be determined.  Use this instead of a fixed timer interval
+	// to drive animations at the actual display rate.
+	RefreshRate() float32
+
 	// Parent returns the parent object of a given window -- for GoGi it is a
 	// gi.Window but could be something else in other frameworks.
 	Parent() interface{}
@@ -154,9 +188,20 @@ type Window interface {
 	IsClosed() bool
 
 	// IsVisible returns true if this window is not closed or minimized and
-	// there are active screens
+	// there are active screens.  This is the occlusion hint for throttling:
+	// Publish is skipped while not visible, and apps / animation loops
+	// should likewise avoid rendering work until the window.Minimize event
+	// signals that visibility has been restored.
 	IsVisible() bool
 
+	// SetFramePublishedFunc sets a function that is called each time the
+	// window's contents have been published (buffer swap) to the display,
+	// which is synchronized with the vsync refresh -- can be used to drive
+	// animation in lockstep with the actual display updates (see also
+	// RefreshRate).  It is called on the window's own goroutine, after
+	// Publish() completes -- do not do significant work in it directly.
+	SetFramePublishedFunc(fun func(win Window))
+
 	// SetCloseReqFunc sets the function that is called whenever there is a
 	// request to close the window (via a OS or a call to CloseReq() method).  That
 	// function can then adjudicate whether and when to actually call Close.
@@ -243,6 +288,18 @@ type Window interface {
 	// window's thread.
 	SetWinTexSubImage(dp image.Point, src image.Image, sr image.Rectangle) error
 
+	// GrabImage returns a copy of the current rendered contents of the
+	// window (including 3D content), e.g., for screenshots -- Y axis is
+	// flipped so top = 0 in the returned image.  Returns an error if the
+	// window has not yet been rendered.  This version manages the
+	// TheApp.RunOnMain and context Activate steps itself, so it must NOT
+	// be called on the app main thread.
+	GrabImage() (*image.RGBA, error)
+
+	// SavePNG saves the current rendered contents of the window
+	// (see GrabImage) to a PNG-encoded file at given path.
+	SavePNG(path string) error
+
 	// Handle returns the driver-specific handle for this window.
 	// Currently, for all platforms, this is *glfw.Window, but that
 	// cannot always be assumed.  Only provided for unforseen emergency use --
@@ -262,6 +319,47 @@ type Window interface {
 	// which can provide better control in a game environment (not avail on Mac).
 	SetCursorEnabled(enabled, raw bool)
 
+	// SetRawMouseMotion turns raw (unfiltered, unaccelerated) mouse motion
+	// on / off for the disabled-cursor mode (see SetCursorEnabled) -- the
+	// smoothest input for first-person / orbit camera control.  Motion is
+	// delivered as mouse.MoveEvent / DragEvent with the position delta in
+	// Where relative to From (see MoveEvent.Delta).  Only takes effect
+	// while the cursor is disabled.  Returns false if raw motion is not
+	// supported on this platform (e.g., Mac).
+	SetRawMouseMotion(raw bool) bool
+
+	// KeyState returns whether the physical key with the given key code is
+	// currently held down on this window, for polling "is this key down"
+	// each frame in game-style loops (e.g., continuous WASD camera
+	// movement), alongside the event-driven model.  code is a key.Codes
+	// value, passed as uint32 because the key package imports oswin --
+	// see gi.Window.KeyState for a typed wrapper.  The state is maintained
+	// from the same callbacks that generate key events, so it stays
+	// consistent with the event stream.  Safe to call from any goroutine.
+	KeyState(code uint32) bool
+
+	// MouseButtonState returns whether the given mouse button is currently
+	// pressed on this window -- but is a mouse.Buttons value, passed as
+	// int32 because the mouse package imports oswin -- see KeyState and
+	// gi.Window.MouseButtonState for a typed wrapper.
+	MouseButtonState(but int32) bool
+
+	// SetDragRegion sets a rectangle, in window pixel coordinates, in which
+	// a left mouse press-and-drag moves the whole window, for Frameless
+	// windows with custom-drawn title bars -- set to the hit region of your
+	// title bar (excluding any buttons), and update it on resize.
+	// Set to the zero rectangle to disable.
+	SetDragRegion(r image.Rectangle)
+
+	// SetEventFilter sets a function that is called for every event just
+	// before it is added to the window's event queue: it can return a
+	// modified event to transform it, or nil to drop the event entirely.
+	// Useful for event logging, input recording / playback (automated UI
+	// testing), and global shortcut handling.  The filter is called from
+	// the event-generating thread, so it must be fast and thread-safe.
+	// Pass nil to remove the filter.
+	SetEventFilter(fun func(evi Event) Event)
+
 	EventDeque
 
 	Drawer
@@ -280,6 +378,7 @@ type WindowBase struct {
 	LogDPI      float32
 	Par         interface{}
 	Flag        int64
+	DragRegion  image.Rectangle // region in which dragging moves the window -- see SetDragRegion
 }
 
 func (w WindowBase) Name() string {
@@ -322,6 +421,14 @@ func (w *WindowBase) IsFullscreen() bool {
 	return bitflag.HasAtomic(&w.Flag, int(Fullscreen))
 }
 
+func (w *WindowBase) IsFrameless() bool {
+	return bitflag.HasAtomic(&w.Flag, int(Frameless))
+}
+
+func (w *WindowBase) SetDragRegion(r image.Rectangle) {
+	w.DragRegion = r
+}
+
 func (w *WindowBase) IsMinimized() bool {
 	return bitflag.HasAtomic(&w.Flag, int(Minimized))
 }
@@ -354,6 +461,17 @@ const (
 	// Fullscreen indicates a window that occupies the entire screen.
 	Fullscreen
 
+	// Transparent indicates a window with a transparent framebuffer,
+	// supporting per-pixel transparency (e.g., for HUD overlays) where
+	// the platform supports it -- must be requested at creation time.
+	Transparent
+
+	// Frameless indicates a window with no native decorations (title bar,
+	// borders) that nevertheless remains resizable and movable, for
+	// custom-drawn window chrome -- use SetDragRegion to define the region
+	// that moves the window when dragged.
+	Frameless
+
 	// Minimized indicates a window reduced to an icon, or otherwise no longer
 	// visible or active.  Otherwise, the window should be assumed to be
 	// visible.
@@ -408,6 +526,14 @@ func (o *NewWindowOptions) SetFullscreen() {
 	bitflag.Set(&o.Flags, int(Fullscreen))
 }
 
+func (o *NewWindowOptions) SetTransparent() {
+	bitflag.Set(&o.Flags, int(Transparent))
+}
+
+func (o *NewWindowOptions) SetFrameless() {
+	bitflag.Set(&o.Flags, int(Frameless))
+}
+
 func WindowFlagsToBool(flags int64) (dialog, modal, tool, fullscreen bool) {
 	dialog = bitflag.Has(flags, int(Dialog))
 	modal = bitflag.Has(flags, int(Modal))
@@ -443,11 +569,81 @@ func sanitizeUTF8(s string, n int) string {
 	return s[:i]
 }
 
+// WinGeomVisMargin is the minimum extent, in window manager units, of a
+// window that must remain within a screen work area for the window (and
+// its title bar) to be considered reachable -- used by ConstrainWinGeom.
+var WinGeomVisMargin = 32
+
+// screenWorkArea returns the usable work area of given screen, excluding
+// taskbars, docks etc, falling back on the full geometry if not set.
+func screenWorkArea(sc *Screen) image.Rectangle {
+	wa := sc.WorkArea
+	if wa.Empty() {
+		wa = sc.Geometry
+	}
+	return wa
+}
+
+// ConstrainWinGeom clamps the given window position and size, in OS window
+// manager units, against the work areas of the currently-connected screens,
+// so that the window fits on a screen and at least WinGeomVisMargin of it
+// (including the title bar at the top) remains reachable -- e.g., for
+// restoring a saved position from a now-disconnected monitor.  Returns the
+// constrained geometry and whether any adjustment was made.  Drivers apply
+// this in SetPos, SetSize, and SetGeom.
+func ConstrainWinGeom(pos, sz image.Point) (cpos, csz image.Point, changed bool) {
+	cpos, csz = pos, sz
+	ns := TheApp.NScreens()
+	if ns == 0 {
+		return
+	}
+	// use the screen with the largest overlap with the requested geometry
+	wa := screenWorkArea(TheApp.Screen(0))
+	barea := -1
+	for i := 0; i < ns; i++ {
+		swa := screenWorkArea(TheApp.Screen(i))
+		ov := swa.Intersect(image.Rectangle{Min: pos, Max: pos.Add(sz)})
+		oa := ov.Dx() * ov.Dy()
+		if oa > barea {
+			barea = oa
+			wa = swa
+		}
+	}
+	wsz := wa.Size()
+	if csz.X > wsz.X {
+		csz.X = wsz.X
+	}
+	if csz.Y > wsz.Y {
+		csz.Y = wsz.Y
+	}
+	mrg := WinGeomVisMargin
+	if cpos.X > wa.Max.X-mrg {
+		cpos.X = wa.Max.X - mrg
+	}
+	if cpos.X+csz.X < wa.Min.X+mrg {
+		cpos.X = wa.Min.X + mrg - csz.X
+	}
+	// title bar is at the top -- keep it below the top of the work area
+	// and above the bottom so it can be grabbed
+	if cpos.Y > wa.Max.Y-mrg {
+		cpos.Y = wa.Max.Y - mrg
+	}
+	if cpos.Y < wa.Min.Y {
+		cpos.Y = wa.Min.Y
+	}
+	changed = cpos != pos || csz != sz
+	return
+}
+
 // Fixup fills in defaults and updates everything based on current screen and
 // window context Specific hardware can fine-tune this as well, in driver code
 func (o *NewWindowOptions) Fixup() {
 	sc := TheApp.Screen(0)
-	scsz := sc.Geometry.Size()
+	wa := sc.WorkArea // usable region, excluding taskbars, docks etc
+	if wa.Empty() {
+		wa = sc.Geometry
+	}
+	scsz := wa.Size()
 
 	dialog, modal, _, _ := WindowFlagsToBool(o.Flags)
 
@@ -489,17 +685,23 @@ func (o *NewWindowOptions) Fixup() {
 				o.Pos.X = lp.X + lsz.X // tile to right -- could depend on orientation
 				o.Pos.Y = lp.Y + 72    // and move down a bit
 			}
-		} else { // center in screen
-			o.Pos.X = scsz.X/2 - o.Size.X/2
-			o.Pos.Y = scsz.Y/2 - o.Size.Y/2
+		} else { // center in work area
+			o.Pos.X = wa.Min.X + scsz.X/2 - o.Size.X/2
+			o.Pos.Y = wa.Min.Y + scsz.Y/2 - o.Size.Y/2
 		}
 	}
 
-	// final sanity fixes
-	if o.Pos.X+o.Size.X > scsz.X {
-		o.Pos.X = scsz.X - o.Size.X
+	// final sanity fixes -- keep on-screen, within the work area
+	if o.Pos.X+o.Size.X > wa.Max.X {
+		o.Pos.X = wa.Max.X - o.Size.X
+	}
+	if o.Pos.Y+o.Size.Y > wa.Max.Y {
+		o.Pos.Y = wa.Max.Y - o.Size.Y
+	}
+	if o.Pos.X < wa.Min.X {
+		o.Pos.X = wa.Min.X
 	}
-	if o.Pos.Y+o.Size.Y > scsz.Y {
-		o.Pos.Y = scsz.Y - o.Size.Y
+	if o.Pos.Y < wa.Min.Y {
+		o.Pos.Y = wa.Min.Y
 	}
 }